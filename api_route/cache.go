@@ -0,0 +1,17 @@
+package apiroute
+
+import (
+	"sonit_server/constant/permission"
+	"sonit_server/handler"
+	"sonit_server/utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitializeCacheHandlerRoute(server *gin.Engine, port string) {
+	// Context path
+	var contextPath string = "cache"
+
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.SYSTEM_MONITOR))
+	adminAuthGroup.GET("/metrics", handler.GetCacheMetrics)
+}
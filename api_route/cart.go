@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -8,9 +9,22 @@ import (
 )
 
 func InitializeCartHandlerRoute(server *gin.Engine, port string) {
+	// Define cart endpoints with owner required
 	var authGroup = server.Group("carts", middleware.Authorize)
-	authGroup.GET("/:id", handler.ViewCartDetail)
-	authGroup.POST("/item/add", handler.AddItemToCart)
-	authGroup.PUT("/item/edit", handler.EditItemInCart)
-	authGroup.DELETE("/item/remove", handler.RemoveItemInCart)
+	authGroup.GET("/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.ViewCartDetail)
+	authGroup.PUT("/:id/acknowledge", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.AcknowledgeCartChanges)
+	authGroup.POST("/item/add", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("request", "user_id")), ""), handler.AddItemToCart)
+	authGroup.PUT("/item/edit", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("request", "user_id")), ""), handler.EditItemInCart)
+	authGroup.DELETE("/item/remove", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.RemoveItemInCart)
+	authGroup.PUT("/reminders", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.EditCartReminderSubscription)
+
+	// Define unsubscribe endpoint of reminder mails, identified by the link token
+	server.GET("carts/reminders/unsubscribe", handler.UnsubscribeCartReminders)
+
+	// Define guest cart endpoints, identified by cart token
+	var guestGroup = server.Group("carts/guest")
+	guestGroup.GET("", handler.ViewGuestCart)
+	guestGroup.POST("/item/add", handler.AddItemToGuestCart)
+	guestGroup.PUT("/item/edit", handler.EditItemInGuestCart)
+	guestGroup.DELETE("/item/remove", handler.RemoveItemFromGuestCart)
 }
@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,8 +12,8 @@ func InitializeCategoryHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "categories"
 
-	// Define Category endpoints with admin required
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
+	// Define Category endpoints with permission required
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.CATEGORY_WRITE))
 	adminAuthGroup.GET("", handler.GetAllCategories)
 	adminAuthGroup.GET("/name/:name", handler.GetCategoriesByName)
 	adminAuthGroup.GET("/status", handler.GetCategoriesByStatus)
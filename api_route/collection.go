@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,8 +12,8 @@ func InitializeCollectionHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "collections"
 
-	// Define Collection endpoints with admin required
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
+	// Define Collection endpoints with permission required
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.COLLECTION_WRITE))
 	adminAuthGroup.GET("/status", handler.GetCollectionsByStatus)
 	adminAuthGroup.POST("/create", handler.CreateCollection)
 	adminAuthGroup.PUT("/update", handler.UpdateCollection)
@@ -0,0 +1,16 @@
+package apiroute
+
+import (
+	"sonit_server/constant/permission"
+	"sonit_server/handler"
+	"sonit_server/utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitializeInventoryHandlerRoute(server *gin.Engine, port string) {
+	var adminAuthGroup = server.Group("inventory", middleware.Authorize, middleware.RequirePermission(permission.INVENTORY_ADJUST))
+	adminAuthGroup.GET("/low-stock", handler.GetLowStockInventories)
+	adminAuthGroup.GET("/stock", handler.GetWarehouseInventories)
+	adminAuthGroup.PUT("/threshold", handler.UpdateInventoryThreshold)
+}
@@ -0,0 +1,23 @@
+package apiroute
+
+import (
+	"sonit_server/handler"
+	"sonit_server/utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitializeMfaHandlerRoute(server *gin.Engine, port string) {
+	// Context path
+	var contextPath string = "users"
+
+	var authGroup = server.Group(contextPath, middleware.Authorize)
+	authGroup.POST("/:id/mfa/totp/enroll", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.EnrollTotp)
+	authGroup.POST("/:id/mfa/totp/confirm", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.ConfirmTotp)
+	authGroup.DELETE("/:id/mfa/totp", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.DisableTotp)
+	authGroup.POST("/:id/mfa/recovery-codes", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.RegenerateRecoveryCodes)
+
+	// Second login step
+	var norCredentialGroup = server.Group("auth")
+	norCredentialGroup.POST("/login/mfa", middleware.RateLimit(middleware.LOGIN_POLICY), handler.VerifyMfaLogin)
+}
@@ -1,6 +1,8 @@
 package apiroute
 
 import (
+	domain_status "sonit_server/constant/domain_status"
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,14 +13,14 @@ func InitializeOrderHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "orders"
 
-	// Define Order endpoints with admin required
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
+	// Define Order endpoints with permission required
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.ORDER_READ))
 	adminAuthGroup.GET("", handler.GetOrders)
 
-	// Define Order endpoints with basic required
+	// Define Order endpoints with owner required
 	var authGroup = server.Group(contextPath, middleware.Authorize)
-	authGroup.GET("/user/:id", handler.GetOrdersByUser)
-	authGroup.GET("/:id", handler.GetOrder)
-	authGroup.POST("/create", handler.CreateOrder)
-	authGroup.PUT("/update", handler.UpdateOrder)
+	authGroup.GET("/user/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.ORDER_READ), handler.GetOrdersByUser)
+	authGroup.GET("/:id", middleware.RequireOwnership(middleware.OrderOwner(middleware.FromParam("id")), permission.ORDER_READ), handler.GetOrder)
+	authGroup.POST("/create", middleware.RateLimit(middleware.CHECKOUT_POLICY), middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), permission.ORDER_WRITE), handler.CreateOrder)
+	authGroup.PUT("/update", middleware.RequireOwnership(middleware.OrderOwner(middleware.FromBody("order_id")), permission.ORDER_WRITE), middleware.RequirePermissionWhen(middleware.FromBody("status"), domain_status.ORDER_REFUNDED, permission.ORDER_REFUND), handler.UpdateOrder)
 }
@@ -1,6 +1,8 @@
 package apiroute
 
 import (
+	domain_status "sonit_server/constant/domain_status"
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,17 +13,17 @@ func InitializePaymentHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "payments"
 
-	// Define Payment endpoints with admin required
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
-	adminAuthGroup.GET("", handler.GetAllPayments)
-	adminAuthGroup.PUT("/update", handler.UpdatePayment)
+	// Define Payment endpoints with permission required
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize)
+	adminAuthGroup.GET("", middleware.RequirePermission(permission.PAYMENT_READ), handler.GetAllPayments)
+	adminAuthGroup.PUT("/update", middleware.RequirePermission(permission.PAYMENT_WRITE), middleware.RequirePermissionWhen(middleware.FromBody("status"), domain_status.PAYMENT_REFUNDED, permission.ORDER_REFUND), handler.UpdatePayment)
 
-	// Define Payment endpoints with basic required
+	// Define Payment endpoints with owner required
 	var authGroup = server.Group(contextPath, middleware.Authorize)
-	authGroup.GET("/user/:id", handler.GetPaymentsByUser)
-	authGroup.GET("/:id", handler.GetPaymentById)
-	authGroup.POST("/create/cart", handler.CreatePaymentThroughCart)
-	authGroup.POST("/create/direct", handler.CreatePaymentDirect)
+	authGroup.GET("/user/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.PAYMENT_READ), handler.GetPaymentsByUser)
+	authGroup.GET("/:id", middleware.RequireOwnership(middleware.PaymentOwner(middleware.FromParam("id")), permission.PAYMENT_READ), handler.GetPaymentById)
+	authGroup.POST("/create/cart", middleware.RateLimit(middleware.CHECKOUT_POLICY), middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), permission.PAYMENT_WRITE), handler.CreatePaymentThroughCart)
+	authGroup.POST("/create/direct", middleware.RateLimit(middleware.CHECKOUT_POLICY), middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), permission.PAYMENT_WRITE), handler.CreatePaymentDirect)
 
 	var norGroup = server.Group(contextPath)
 	norGroup.GET("/callback-success/:id", handler.CallbackPaymentSuccess)
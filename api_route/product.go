@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,13 +12,13 @@ func InitializeProductHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "products"
 
-	// Define Product endpoints with admin required
+	// Define Product endpoints with permission required
 	var norGroup = server.Group(contextPath)
 	norGroup.GET("/customer-ui", handler.GetProductsCustomerUI)
 	norGroup.GET("/price-interval", handler.GetProductsByPriceInterval)
 	norGroup.GET("/:id", handler.GetProductById)
 
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.PRODUCT_WRITE))
 	adminAuthGroup.GET("", handler.GetAllProducts)
 	adminAuthGroup.GET("/category/:id", handler.GetProductsByCategory)
 	adminAuthGroup.GET("/collection/:id", handler.GetProductsByCollection)
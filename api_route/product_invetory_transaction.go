@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -8,9 +9,11 @@ import (
 )
 
 func InitializeProductInventoryTransactionHandlerRoute(server *gin.Engine, port string) {
-	var adminAuthGroup = server.Group("product-inventory-transactions", middleware.Authorize, middleware.AdminAuthorization)
+	var adminAuthGroup = server.Group("product-inventory-transactions", middleware.Authorize, middleware.RequirePermission(permission.INVENTORY_ADJUST))
 	adminAuthGroup.GET("/product/:id", handler.GetInventoryTransactionsByProduct)
 	adminAuthGroup.GET("/:id", handler.GetProductInventoryTransaction)
 	adminAuthGroup.POST("/create", handler.CreateProductInventoryTransaction)
-	adminAuthGroup.PUT("/update", handler.UpdateProductInventoryTransaction)
+	adminAuthGroup.POST("/correct", handler.CorrectProductInventoryTransaction)
+	adminAuthGroup.POST("/transfer", handler.TransferInventory)
+	adminAuthGroup.POST("/import", handler.ImportInventoryTransactions)
 }
@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,8 +12,8 @@ func InitializeRoleHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "roles"
 
-	// Define role endpoints with admin required
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
+	// Define role endpoints with permission required
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.ROLE_MANAGE))
 	adminAuthGroup.GET("", handler.GetAllRoles)
 	adminAuthGroup.GET("/name/:name", handler.GetRolesByName)
 	adminAuthGroup.GET("/status", handler.GetRolesByStatus)
@@ -20,6 +21,9 @@ func InitializeRoleHandlerRoute(server *gin.Engine, port string) {
 	adminAuthGroup.PUT("/update", handler.UpdateRole)
 	adminAuthGroup.PATCH("activate/:id", handler.ActivateRole)
 	adminAuthGroup.DELETE("remove/:id", handler.RemoveRole)
+	adminAuthGroup.GET("/permissions", handler.GetAllPermissions)
+	adminAuthGroup.GET("/:id/permissions", handler.GetRolePermissions)
+	adminAuthGroup.PUT("/:id/permissions", handler.SetRolePermissions)
 
 	// Define role endpoints with basic required
 	var authGroup = server.Group(contextPath, middleware.Authorize)
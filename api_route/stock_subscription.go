@@ -0,0 +1,17 @@
+package apiroute
+
+import (
+	"sonit_server/constant/permission"
+	"sonit_server/handler"
+	"sonit_server/utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitializeStockSubscriptionHandlerRoute(server *gin.Engine, port string) {
+	// Define back in stock subscription endpoints with owner required
+	var authGroup = server.Group("stock-subscriptions", middleware.Authorize)
+	authGroup.GET("/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.GetStockSubscriptions)
+	authGroup.POST("", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.SubscribeToStock)
+	authGroup.DELETE("", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.UnsubscribeFromStock)
+}
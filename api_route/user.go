@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,30 +12,44 @@ func InitializeUserHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "users"
 
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.USER_MANAGE))
 	adminAuthGroup.GET("", handler.GetAllUsers)
 	adminAuthGroup.GET("/role/:role", handler.GetUsersByRole)
 	adminAuthGroup.GET("/status/:status", handler.GetUsersByStatus)
+	adminAuthGroup.POST("/:id/unlock", handler.UnlockAccount)
+	adminAuthGroup.GET("/:id/lockout-events", handler.GetLockoutEvents)
 
 	var authGroup = server.Group(contextPath, middleware.Authorize)
-	authGroup.GET("/:id", handler.GetUser)
-	authGroup.PUT("/update-info/:actorId", handler.UpdateUser)
-	authGroup.POST("/vip/create", handler.CreateVipCode)
+	authGroup.GET("/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.GetUser)
+	authGroup.PUT("/update-info/:actorId", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("actorId")), ""), handler.UpdateUser)
+	authGroup.POST("/vip/create", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), permission.USER_MANAGE), handler.CreateVipCode)
+	authGroup.GET("/:id/sessions", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.GetUserSessions)
+	authGroup.DELETE("/:id/sessions", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.RevokeUserSessions)
+	authGroup.DELETE("/:id/sessions/:sessionId", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.RevokeUserSessions)
+	authGroup.PUT("/:id/password", middleware.RateLimit(middleware.LOGIN_POLICY), middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.ChangePassword)
+	authGroup.GET("/:id/export", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.ExportUserData)
+	authGroup.GET("/:id/vip-tier", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.GetVipTierProgress)
+	authGroup.DELETE("/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.RequestAccountDeletion)
+	authGroup.DELETE("/:id/deletion", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.CancelAccountDeletion)
 	//authGroup.PUT("/id/:id/status/:status", handler.ChangeUserStatus)
 	//authGroup.PUT("/logout/:userId", handler.Logout)
 
 	var norGroup = server.Group(contextPath)
 	//norGroup.POST("/login", handler.Login)
-	norGroup.POST("/create", handler.CreateUser)
+	norGroup.POST("/create", middleware.RateLimit(middleware.REGISTER_POLICY), handler.CreateUser)
 	//norGroup.PUT("/:email", handler.Re)
-	norGroup.PUT("/reset-password/:token/:password/:confirmPassword")
+	norGroup.POST("/reset-password", middleware.RateLimit(middleware.LOGIN_POLICY), handler.ResetPassword)
 	norGroup.GET("/verify-action", handler.VerifyAction)
 
 	// Auth group with login, logout
 	var norCredentialGroup = server.Group("auth")
-	norCredentialGroup.POST("/login", handler.Login)
-	norCredentialGroup.POST("/refresh-token", handler.RefreshToken)
+	norCredentialGroup.POST("/login", middleware.RateLimit(middleware.LOGIN_POLICY), handler.Login)
+	norCredentialGroup.POST("/oidc/:provider", middleware.RateLimit(middleware.LOGIN_POLICY), handler.LoginWithOidc)
+	norCredentialGroup.POST("/refresh-token", middleware.RateLimit(middleware.LOGIN_POLICY), handler.RefreshToken)
+
+	// Public keys for other services to verify tokens
+	server.GET("/.well-known/jwks.json", handler.GetJWKS)
 
 	var authCredentialGroup = server.Group("auth", middleware.Authorize)
-	authCredentialGroup.POST("/logout/:id", handler.Logout)
+	authCredentialGroup.POST("/logout/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), ""), handler.Logout)
 }
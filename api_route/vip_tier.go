@@ -0,0 +1,24 @@
+package apiroute
+
+import (
+	"sonit_server/constant/permission"
+	"sonit_server/handler"
+	"sonit_server/utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitializeVipTierHandlerRoute(server *gin.Engine, port string) {
+	// Context path
+	var contextPath string = "vip-tiers"
+
+	// Define VIP tier endpoints with permission required
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.VIP_TIER_WRITE))
+	adminAuthGroup.POST("", handler.CreateVipTier)
+	adminAuthGroup.PUT("/update", handler.UpdateVipTier)
+	adminAuthGroup.DELETE("/remove/:id", handler.RemoveVipTier)
+
+	// Define VIP tier endpoints with basic required
+	var norGroup = server.Group(contextPath)
+	norGroup.GET("", handler.GetVipTiers)
+}
@@ -1,6 +1,7 @@
 package apiroute
 
 import (
+	"sonit_server/constant/permission"
 	"sonit_server/handler"
 	"sonit_server/utils/middleware"
 
@@ -11,8 +12,8 @@ func InitializeVoucherHandlerRoute(server *gin.Engine, port string) {
 	// Context path
 	var contextPath string = "vouchers"
 
-	// Define Voucher endpoints with admin required
-	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.AdminAuthorization)
+	// Define Voucher endpoints with permission required
+	var adminAuthGroup = server.Group(contextPath, middleware.Authorize, middleware.RequirePermission(permission.VOUCHER_WRITE))
 	adminAuthGroup.POST("", handler.CreateVoucher)
 	adminAuthGroup.GET("", handler.GetAllVouchers)
 	adminAuthGroup.GET("/valid", handler.GetAllValidVouchers)
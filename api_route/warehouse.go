@@ -0,0 +1,16 @@
+package apiroute
+
+import (
+	"sonit_server/constant/permission"
+	"sonit_server/handler"
+	"sonit_server/utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitializeWarehouseHandlerRoute(server *gin.Engine, port string) {
+	var adminAuthGroup = server.Group("warehouses", middleware.Authorize, middleware.RequirePermission(permission.INVENTORY_ADJUST))
+	adminAuthGroup.GET("", handler.GetWarehouses)
+	adminAuthGroup.POST("", handler.CreateWarehouse)
+	adminAuthGroup.PUT("", handler.UpdateWarehouse)
+}
@@ -0,0 +1,19 @@
+package apiroute
+
+import (
+	"sonit_server/constant/permission"
+	"sonit_server/handler"
+	"sonit_server/utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitializeWishlistHandlerRoute(server *gin.Engine, port string) {
+	// Define wishlist endpoints with owner required
+	var authGroup = server.Group("wishlists", middleware.Authorize)
+	authGroup.GET("/:id", middleware.RequireOwnership(middleware.UserOwner(middleware.FromParam("id")), permission.USER_MANAGE), handler.ViewWishlist)
+	authGroup.POST("/item/add", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.AddItemToWishlist)
+	authGroup.DELETE("/item/remove", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.RemoveItemFromWishlist)
+	authGroup.POST("/item/move-to-cart", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.MoveWishlistItemToCart)
+	authGroup.POST("/item/save-for-later", middleware.RequireOwnership(middleware.UserOwner(middleware.FromBody("user_id")), ""), handler.SaveCartItemForLater)
+}
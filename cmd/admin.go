@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	"sonit_server/model/dto/request"
+	businesslogic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// First migration holding the permission catalog
+const permissions_migration_version int = 5
+
+// Separator of id list in a CSV cell, e.g. cat1|cat2
+const csv_list_sep_char string = "|"
+
+// Header of voucher CSV file
+var voucherCsvHeader = []string{"code", "discount", "amount", "description", "allowed_category_ids", "allowed_product_ids", "expired_at"}
+
+func runMigrate(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("migrate", flag.ContinueOnError)
+	var action = flags.String("action", action_type.MIGRATION_TYPE, "migration or rollback")
+	var version = flags.Int("version", 1, "target schema version")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := db.MigrateDB(*action, *version, db_server.InitializePostgreSQL(), logger); err != nil {
+		return err
+	}
+
+	// Permissions are granted to the configured admin role rather than a role id fixed in the scripts
+	if *action != action_type.MIGRATION_TYPE || *version < permissions_migration_version {
+		return nil
+	}
+
+	service, err := businesslogic.GenerateRoleService()
+	if err != nil {
+		return err
+	}
+
+	granted, err := service.GrantAdminPermissions(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Granted %d permissions to admin role %s.\n", granted, config.Get().Role.AdminRole)
+	return nil
+}
+
+func runCreateAdmin(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("user create-admin", flag.ContinueOnError)
+	var email = flags.String("email", "", "admin email")
+	var password = flags.String("password", "", "admin password")
+	var name = flags.String("name", "", "admin full name")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" || *password == "" {
+		return errors.New("--email and --password are required")
+	}
+
+	service, err := businesslogic.GenerateUserService()
+	if err != nil {
+		return err
+	}
+
+	id, err := service.CreateAdminAccount(request.CreateUserRequest{
+		FullName: *name,
+		Email:    *email,
+		Password: *password,
+	}, context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Created admin account " + *email + " with id " + id)
+	return nil
+}
+
+func runResetUserPassword(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	var email = flags.String("email", "", "account email")
+	var password = flags.String("password", "", "new password")
+	var forceChange = flags.Bool("force-change", false, "require user to change password at next login")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" || *password == "" {
+		return errors.New("--email and --password are required")
+	}
+
+	service, err := businesslogic.GenerateUserService()
+	if err != nil {
+		return err
+	}
+
+	if err := service.ForceResetPassword(request.ForceResetPasswordRequest{
+		Email:         *email,
+		Password:      *password,
+		IsHaveToReset: *forceChange,
+	}, context.Background()); err != nil {
+		return err
+	}
+
+	fmt.Println("Password of " + *email + " has been reset.")
+	return nil
+}
+
+func runImportVouchers(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("voucher import", flag.ContinueOnError)
+	var file = flags.String("file", "", "path to vouchers file (.csv or .json)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return errors.New("--file is required")
+	}
+
+	vouchers, err := loadVouchers(*file)
+	if err != nil {
+		return err
+	}
+
+	var imported, failed int
+	for _, voucher := range vouchers {
+		// Service closes its connection after each call
+		service, err := businesslogic.GenerateVoucherService()
+		if err != nil {
+			return err
+		}
+
+		if err := service.CreateVoucher(voucher, context.Background()); err != nil {
+			fmt.Println("Skip voucher " + voucher.Code + " - " + err.Error())
+			failed++
+			continue
+		}
+
+		imported++
+	}
+
+	fmt.Printf("Imported %d vouchers, %d failed.\n", imported, failed)
+	return nil
+}
+
+func loadVouchers(path string) ([]request.CreateVoucherRequest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var res []request.CreateVoucherRequest
+		if err := json.NewDecoder(f).Decode(&res); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	case ".csv":
+		return readVoucherCsv(f)
+	}
+
+	return nil, errors.New("unsupported voucher file format " + filepath.Ext(path))
+}
+
+func readVoucherCsv(r io.Reader) ([]request.CreateVoucherRequest, error) {
+	var reader = csv.NewReader(r)
+	reader.FieldsPerRecord = len(voucherCsvHeader)
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 || strings.ToLower(rows[0][0]) != voucherCsvHeader[0] {
+		return nil, errors.New("voucher CSV must start with header " + strings.Join(voucherCsvHeader, ","))
+	}
+
+	var res []request.CreateVoucherRequest
+	for i, row := range rows[1:] {
+		var line = i + 2
+
+		discount, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid discount %s", line, row[1])
+		}
+
+		amount, err := strconv.ParseInt(row[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount %s", line, row[2])
+		}
+
+		expiredAt, err := parseCliTime(row[6])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid expired_at %s", line, row[6])
+		}
+
+		res = append(res, request.CreateVoucherRequest{
+			Code:               row[0],
+			Discount:           discount,
+			Amount:             amount,
+			Description:        row[3],
+			AllowedCategoryIDs: splitCsvList(row[4]),
+			AllowedProductIDs:  splitCsvList(row[5]),
+			ExpiredAt:          expiredAt,
+		})
+	}
+
+	return res, nil
+}
+
+func splitCsvList(cell string) []string {
+	var res []string
+	for _, item := range strings.Split(cell, csv_list_sep_char) {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
+
+// Accept both RFC3339 and date only formats
+func parseCliTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
+
+func runAdjustInventory(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("inventory adjust", flag.ContinueOnError)
+	var productId = flags.String("product", "", "product id")
+	var amount = flags.Int64("amount", 0, "adjusted quantity, must be positive")
+	var action = flags.String("action", businesslogic.Import_action, "import, export or return")
+	var note = flags.String("note", "Manual adjustment", "transaction note")
+	var warehouseId = flags.String("warehouse", "", "warehouse id, default warehouse if empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *productId == "" || *amount <= 0 {
+		return errors.New("--product and a positive --amount are required")
+	}
+
+	service, err := businesslogic.GenerateProductInventoryTransactionService()
+	if err != nil {
+		return err
+	}
+
+	if err := service.CreateProductInventoryTransaction(request.CreateProductInventoryTransactionRequest{
+		ProductId:   *productId,
+		Amount:      *amount,
+		Action:      *action,
+		WarehouseId: *warehouseId,
+		Note:        *note,
+		Date:        time.Now(),
+	}, context.Background()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Inventory of %s adjusted: %s %d.\n", *productId, *action, *amount)
+	return nil
+}
+
+// Record a spreadsheet of inventory transactions or a stocktake at once, fails with row errors if a row is invalid
+func runImportInventory(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("inventory import", flag.ContinueOnError)
+	var path = flags.String("file", "", "path to a .csv or .xlsx file of sku, quantity, action and note columns")
+	var mode = flags.String("mode", "transaction", "transaction or stocktake, stocktake quantities are counted quantities")
+	var warehouseId = flags.String("warehouse", "", "warehouse id, default warehouse if empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return errors.New("--file is required")
+	}
+
+	data, err := os.ReadFile(*path)
+	if err != nil {
+		return err
+	}
+
+	service, err := businesslogic.GenerateProductInventoryTransactionService()
+	if err != nil {
+		return err
+	}
+
+	res, err := service.ImportInventoryTransactions(request.ImportInventoryRequest{
+		Mode:        *mode,
+		WarehouseId: *warehouseId,
+		FileName:    *path,
+		Data:        data,
+	}, context.Background())
+	if err != nil {
+		return err
+	}
+
+	if !res.IsApplied {
+		for _, rowErr := range res.Errors {
+			fmt.Printf("Line %d (%s): %s\n", rowErr.Line, rowErr.Sku, rowErr.Message)
+		}
+
+		return errors.New(fmt.Sprintf("%d of %d rows are invalid, nothing was recorded", len(res.Errors), res.Rows))
+	}
+
+	fmt.Printf("Imported %d rows (%s): %d inventory transactions recorded.\n", res.Rows, res.Mode, res.Transactions)
+	return nil
+}
+
+// Fails if a product inventory doesn't match the sum of its ledger, so that it can be scheduled as a check
+func runVerifyInventory(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("inventory verify", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	service, err := businesslogic.GenerateInventoryService()
+	if err != nil {
+		return err
+	}
+
+	drifts, err := service.VerifyInventoryLedger(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("Every product and warehouse inventory matches its ledger.")
+		return nil
+	}
+
+	for _, drift := range drifts {
+		var location = drift.ProductId
+		if drift.WarehouseId != "" {
+			location += " at " + drift.WarehouseId
+		}
+
+		fmt.Printf("%s: current %d, ledger %d, drift %d\n", location, drift.CurrentQuantity, drift.LedgerQuantity, drift.CurrentQuantity-drift.LedgerQuantity)
+	}
+
+	return errors.New(fmt.Sprintf("%d product inventories drifted from the ledger", len(drifts)))
+}
+
+func runListRoutes(args []string, logger *log.Logger) error {
+	gin.SetMode(gin.ReleaseMode)
+
+	var server = gin.New()
+	corsConfig(server)
+	setupApiRoutes(server, config.Get().Server.ApiPort)
+	setupSwagger(server, config.Get().Server.ApiPort)
+
+	for _, route := range server.Routes() {
+		fmt.Printf("%-7s %s\n", route.Method, route.Path)
+	}
+
+	return nil
+}
+
+// Generate a new signing key into the key directory, old keys are kept to verify tokens issued before rotation
+func runGenerateTokenKey(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("jwt generate-key", flag.ContinueOnError)
+	var algorithm = flags.String("alg", "RS256", "RS256 or EdDSA")
+	var dir = flags.String("dir", config.Get().Auth.JwtKeyDir, "key directory")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	kid, err := utils.GenerateTokenKeyFile(*dir, *algorithm)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Generated key " + kid + " in " + *dir + ".")
+	fmt.Println("Set JWT_ALGORITHM=" + *algorithm + " and JWT_ACTIVE_KID=" + kid + " (or leave it empty to use the newest key) then restart the server.")
+	return nil
+}
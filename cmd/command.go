@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Command line entry of the server application, e.g. "serve", "user create-admin"
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string, logger *log.Logger) error
+	lenient     bool // Command still runs with invalid configuration
+}
+
+const default_command string = "serve"
+
+// Registered commands, resolved with the longest matched name
+var commands = []command{
+	{
+		name:        "serve",
+		usage:       "serve",
+		description: "Run API server",
+		run:         runServe,
+	},
+	{
+		name:        "migrate",
+		usage:       "migrate --action migration|rollback --version <n>",
+		description: "Migrate or rollback database schema",
+		run:         runMigrate,
+	},
+	{
+		name:        "seed",
+		usage:       "seed [--profile dev|demo] [--file <path>]",
+		description: "Seed database with fixtures",
+		run:         runSeed,
+	},
+	{
+		name:        "user create-admin",
+		usage:       "user create-admin --email <email> --password <password> [--name <full name>]",
+		description: "Create an activated admin account",
+		run:         runCreateAdmin,
+	},
+	{
+		name:        "user reset-password",
+		usage:       "user reset-password --email <email> --password <password> [--force-change]",
+		description: "Reset password of an account",
+		run:         runResetUserPassword,
+	},
+	{
+		name:        "voucher import",
+		usage:       "voucher import --file <vouchers.csv|vouchers.json>",
+		description: "Import vouchers from a CSV or JSON file",
+		run:         runImportVouchers,
+	},
+	{
+		name:        "inventory adjust",
+		usage:       "inventory adjust --product <id> --amount <n> --action import|export|return [--warehouse <id>] [--note <note>]",
+		description: "Adjust product inventory with a transaction",
+		run:         runAdjustInventory,
+	},
+	{
+		name:        "inventory import",
+		usage:       "inventory import --file <path.csv|path.xlsx> [--mode transaction|stocktake] [--warehouse <id>]",
+		description: "Record inventory transactions or a stocktake from a spreadsheet at once",
+		run:         runImportInventory,
+	},
+	{
+		name:        "inventory verify",
+		usage:       "inventory verify",
+		description: "Recompute product and warehouse inventories from the ledger and report drift",
+		run:         runVerifyInventory,
+	},
+	{
+		name:        "job run",
+		usage:       "job run [--name account-deletion]",
+		description: "Run background jobs once, e.g. from cron",
+		run:         runJobs,
+	},
+	{
+		name:        "jwt generate-key",
+		usage:       "jwt generate-key [--alg RS256|EdDSA] [--dir <key dir>]",
+		description: "Generate a new token signing key for rotation",
+		run:         runGenerateTokenKey,
+		lenient:     true,
+	},
+	{
+		name:        "routes",
+		usage:       "routes",
+		description: "List registered API routes",
+		run:         runListRoutes,
+		lenient:     true,
+	},
+	{
+		name:        "config print",
+		usage:       "config print [--redacted=false]",
+		description: "Print resolved configuration",
+		run:         runPrintConfig,
+		lenient:     true,
+	},
+}
+
+// Find the command matching the arguments, returns the remaining arguments as the command's flags
+func resolveCommand(args []string) (*command, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		args = append([]string{default_command}, args...)
+	}
+
+	if args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		printUsage()
+		return nil, nil, nil
+	}
+
+	var res *command
+	var consumed int
+	for i := range commands {
+		var parts = strings.Fields(commands[i].name)
+		if len(parts) > len(args) || len(parts) <= consumed {
+			continue
+		}
+
+		if strings.Join(args[:len(parts)], " ") == commands[i].name {
+			res = &commands[i]
+			consumed = len(parts)
+		}
+	}
+
+	if res == nil {
+		printUsage()
+		return nil, nil, errors.New("unknown command " + strings.Join(args, " "))
+	}
+
+	return res, args[consumed:], nil
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: sonit_server <command> [flags]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n      %s\n", c.name, c.description, c.usage)
+	}
+}
@@ -1,21 +1,40 @@
 package cmd
 
 import (
-	"fmt"
+	"flag"
 	"log"
+	"os"
 	"sonit_server/constant/noti"
+	"sonit_server/utils/config"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
-// Load .env file
-func loadEnv(logger *log.Logger) {
-	if err := godotenv.Load(); err != nil {
-		logger.Println(fmt.Sprintf(noti.ENV_LOAD_ERR_MSG, "") + err.Error())
+// Load configuration from env, .env files and optional YAML file
+func loadConfig(logger *log.Logger) error {
+	if _, err := config.Load(); err != nil {
+		logger.Println(noti.CONFIG_LOAD_ERR_MSG + err.Error())
+		return err
 	}
+
+	return nil
+}
+
+// Print loaded configuration, secrets are masked unless --redacted=false
+func runPrintConfig(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("config print", flag.ContinueOnError)
+	var redacted = flags.Bool("redacted", true, "mask secret values")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var cfg = config.Get()
+	cfg.Print(os.Stdout, *redacted)
+
+	return cfg.Validate()
 }
 
 // Enable CORS
@@ -23,7 +42,7 @@ func corsConfig(server *gin.Engine) {
 	server.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Allow all origins, or specify ["http://example.com"]
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Cart-Token"}, // Guest cart token
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
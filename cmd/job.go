@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	businesslogic "sonit_server/usecase/business_logic"
+	"sonit_server/utils/config"
+	"strings"
+	"time"
+)
+
+// Background job, run periodically by serve or once by "job run"
+type job struct {
+	name     string
+	interval func() time.Duration                   // Configured interval, see config.JobConfig
+	run      func(ctx context.Context) (int, error) // Returns number of processed items
+}
+
+// Registered background jobs
+var jobs = []job{
+	{
+		name:     "account-deletion",
+		interval: func() time.Duration { return config.Get().Job.AccountDeletionInterval },
+		run:      runAccountDeletionJob,
+	},
+	{
+		name:     "vip-tier",
+		interval: func() time.Duration { return config.Get().Job.VipTierInterval },
+		run:      runVipTierJob,
+	},
+	{
+		name:     "guest-cart",
+		interval: func() time.Duration { return config.Get().Job.GuestCartInterval },
+		run:      runGuestCartJob,
+	},
+	{
+		name:     "abandoned-cart",
+		interval: func() time.Duration { return config.Get().Job.AbandonedCartInterval },
+		run:      runAbandonedCartJob,
+	},
+	{
+		name:     "wishlist",
+		interval: func() time.Duration { return config.Get().Job.WishlistInterval },
+		run:      runWishlistJob,
+	},
+	{
+		name:     "stock-notification",
+		interval: func() time.Duration { return config.Get().Job.StockNotifyInterval },
+		run:      runStockNotificationJob,
+	},
+	{
+		name:     "low-stock-digest",
+		interval: func() time.Duration { return config.Get().Job.LowStockDigestInterval },
+		run:      runLowStockDigestJob,
+	},
+}
+
+// Anonymize accounts whose deletion grace period has passed
+func runAccountDeletionJob(ctx context.Context) (int, error) {
+	service, err := businesslogic.GenerateUserService()
+	if err != nil {
+		return 0, err
+	}
+
+	return service.ProcessAccountDeletions(ctx)
+}
+
+// Promote and demote users to the tier matching their spend
+func runVipTierJob(ctx context.Context) (int, error) {
+	service, err := businesslogic.GenerateVipTierService()
+	if err != nil {
+		return 0, err
+	}
+
+	return service.EvaluateVipTiers(ctx)
+}
+
+// Remove expired guest carts
+func runGuestCartJob(ctx context.Context) (int, error) {
+	service, err := businesslogic.GenerateCartService()
+	if err != nil {
+		return 0, err
+	}
+
+	return service.RemoveExpiredGuestCarts(ctx)
+}
+
+// Purge expired carts then remind owners of carts left without checkout.
+// Each service call closes its connection so a new service is generated per call
+func runAbandonedCartJob(ctx context.Context) (int, error) {
+	service, err := businesslogic.GenerateCartService()
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := service.RemoveExpiredCarts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if service, err = businesslogic.GenerateCartService(); err != nil {
+		return purged, err
+	}
+
+	reminded, err := service.SendAbandonedCartReminders(ctx)
+	if err != nil {
+		return purged, err
+	}
+
+	return purged + reminded, nil
+}
+
+// Mail owners of saved products which were restocked or dropped in price
+func runWishlistJob(ctx context.Context) (int, error) {
+	service, err := businesslogic.GenerateWishlistService()
+	if err != nil {
+		return 0, err
+	}
+
+	return service.NotifyWishlistChanges(ctx)
+}
+
+// Mail a batch of subscribers whose product was restocked
+func runStockNotificationJob(ctx context.Context) (int, error) {
+	service, err := businesslogic.GenerateStockSubscriptionService()
+	if err != nil {
+		return 0, err
+	}
+
+	return service.SendBackInStockNotifications(ctx)
+}
+
+// Mail the list of low stock products to inventory staff
+func runLowStockDigestJob(ctx context.Context) (int, error) {
+	service, err := businesslogic.GenerateInventoryService()
+	if err != nil {
+		return 0, err
+	}
+
+	return service.SendLowStockDigest(ctx)
+}
+
+func runJobs(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("job run", flag.ContinueOnError)
+	var name = flags.String("name", "", "job name, every job if empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, j := range jobs {
+		names = append(names, j.name)
+		if *name != "" && *name != j.name {
+			continue
+		}
+
+		processed, err := j.run(context.Background())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Job %s processed %d items.\n", j.name, processed)
+		if *name != "" {
+			return nil
+		}
+	}
+
+	if *name != "" {
+		return errors.New("unknown job " + *name + ", available jobs: " + strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// Run each job with a positive interval in background of the API server
+func startJobs(logger *log.Logger) {
+	for _, j := range jobs {
+		var interval = j.interval()
+		if interval <= 0 {
+			continue
+		}
+
+		go func() {
+			var ticker = time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				processed, err := j.run(context.Background())
+				if err != nil {
+					logger.Println("Error run job " + j.name + " - " + err.Error())
+					continue
+				}
+
+				if processed > 0 {
+					logger.Printf("Job %s processed %d items.\n", j.name, processed)
+				}
+			}
+		}()
+	}
+}
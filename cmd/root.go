@@ -1,9 +1,10 @@
 package cmd
 
 import (
+	"log"
 	"os"
-	"sonit_server/constant/env"
 	"sonit_server/utils"
+	"sonit_server/utils/config"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,8 +14,34 @@ func Execute() {
 	// Initialize logger config
 	var logger = utils.GetLogConfig()
 
-	// Load env
-	loadEnv(logger)
+	// Resolve command, serve API by default
+	cmd, args, err := resolveCommand(os.Args[1:])
+	if err != nil {
+		logger.Println(err.Error())
+		os.Exit(2)
+	}
+
+	if cmd == nil {
+		return
+	}
+
+	// Load config, missing required values stop the application before any work
+	if err := loadConfig(logger); err != nil && !cmd.lenient {
+		os.Exit(1)
+	}
+
+	if err := cmd.run(args, logger); err != nil {
+		logger.Println("Error run " + cmd.name + " command - " + err.Error())
+		os.Exit(1)
+	}
+}
+
+// Run gin server for API
+func runServe(args []string, logger *log.Logger) error {
+	// Token signing keys must be ready before serving
+	if err := utils.InitializeTokenKeys(logger); err != nil {
+		return err
+	}
 
 	// Initialize gin server for API
 	var server = gin.Default()
@@ -23,7 +50,7 @@ func Execute() {
 	corsConfig(server)
 
 	// Get API port
-	var apiPort = os.Getenv(env.API_PORT)
+	var apiPort = config.Get().Server.ApiPort
 
 	// Set up API routes
 	setupApiRoutes(server, apiPort)
@@ -34,8 +61,9 @@ func Execute() {
 	// Setup payments
 	setupPayments(logger)
 
+	// Start background jobs
+	startJobs(logger)
+
 	// Run server
-	if err := server.Run(":" + apiPort); err != nil {
-		logger.Println("Error run server - " + err.Error())
-	}
+	return server.Run(":" + apiPort)
 }
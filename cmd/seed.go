@@ -0,0 +1,573 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sonit_server/constant/currency"
+	repo "sonit_server/data_access"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/entity"
+	businesslogic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	seed_fixture_folder_dir string = "sql_script/seed/fixture"
+	seed_default_profile    string = "dev"
+)
+
+// Supported fixture file extensions, ordered by lookup priority
+var seedFixtureExtensions = []string{".yaml", ".yml", ".json"}
+
+type seeder struct {
+	logger            *log.Logger
+	roleRepo          data_access.IRoleRepo
+	permissionRepo    data_access.IPermissionRepo
+	userRepo          data_access.IUserRepo
+	userSecurityRepo  data_access.IUserSecurityRepo
+	categoryRepo      data_access.ICategoryRepo
+	collectionRepo    data_access.ICollectionRepo
+	productRepo       data_access.IProductRepo
+	inventoryRepo     data_access.IProductInventoryRepo
+	inventoryTxRepo   data_access.IProductInventoryTransactionRepo
+	voucherRepo       data_access.IVoucherRepo
+	inserted, skipped int
+	repaired          int // Records left incomplete by an interrupted run
+}
+
+// Seed database with fixtures of a profile (dev, demo, ...) or a specific fixture file
+func runSeed(args []string, logger *log.Logger) error {
+	var flags = flag.NewFlagSet("seed", flag.ContinueOnError)
+	var profile = flags.String("profile", seed_default_profile, "fixture profile name under "+seed_fixture_folder_dir)
+	var file = flags.String("file", "", "path to a fixture file, overrides --profile")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var path = *file
+	if path == "" {
+		p, err := resolveFixturePath(*profile)
+		if err != nil {
+			return err
+		}
+
+		path = p
+	}
+
+	fixture, err := loadFixture(path)
+	if err != nil {
+		return err
+	}
+
+	cnn, err := db.ConnectDB(logger, db_server.InitializePostgreSQL())
+	if err != nil {
+		return err
+	}
+	defer cnn.Close()
+
+	var s = &seeder{
+		logger:           logger,
+		roleRepo:         repo.InitializeCachedRoleRepo(cnn, logger),
+		permissionRepo:   repo.InitializeCachedPermissionRepo(cnn, logger),
+		userRepo:         repo.InitializeUserRepo(cnn, logger),
+		userSecurityRepo: repo.InitializeUserSecurityRepo(cnn, logger),
+		categoryRepo:     repo.InitializeCachedCategoryRepo(cnn, logger),
+		collectionRepo:   repo.InitializeCachedCollectionRepo(cnn, logger),
+		productRepo:      repo.InitializeCachedProductRepo(cnn, logger),
+		inventoryRepo:    repo.InitializeProductInventoryRepo(cnn, logger),
+		inventoryTxRepo:  repo.InitializeProductInventoryTransactionRepo(cnn, logger),
+		voucherRepo:      repo.InitializeCachedVoucherRepo(cnn, logger),
+	}
+
+	if err := s.seed(fixture, context.Background()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Seeded %s: %d inserted, %d repaired, %d skipped (already exist).\n", path, s.inserted, s.repaired, s.skipped)
+	return nil
+}
+
+func resolveFixturePath(profile string) (string, error) {
+	for _, ext := range seedFixtureExtensions {
+		var path = filepath.Join(seed_fixture_folder_dir, profile+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no fixture found for profile %s in %s", profile, seed_fixture_folder_dir)
+}
+
+func loadFixture(path string) (*request.SeedFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var res request.SeedFixture
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &res)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &res)
+	default:
+		return nil, errors.New("unsupported fixture format " + filepath.Ext(path))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture %s - %s", path, err.Error())
+	}
+
+	return &res, nil
+}
+
+// Insert fixtures in dependency order, records already existed are skipped so seeding can be re-run safely.
+// Repos don't share a database transaction, so an entity written in several steps (role and permissions,
+// user and security, product, inventory and initial stock) is completed on the next run if a step failed
+func (s *seeder) seed(fixture *request.SeedFixture, ctx context.Context) error {
+	for _, role := range fixture.Roles {
+		if err := s.seedRole(role, ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range fixture.Users {
+		if err := s.seedUser(user, ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, category := range fixture.Categories {
+		if err := s.seedCategory(category, ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, collection := range fixture.Collections {
+		if err := s.seedCollection(collection, ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, product := range fixture.Products {
+		if err := s.seedProduct(product, ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, voucher := range fixture.Vouchers {
+		if err := s.seedVoucher(voucher, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *seeder) seedRole(req request.SeedRole, ctx context.Context) error {
+	if req.RoleId == "" || req.RoleName == "" {
+		return errors.New("role fixture requires role_id and role_name")
+	}
+
+	existed, err := s.roleRepo.GetRoleById(req.RoleId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if existed != nil {
+		return s.repairRolePermissions(req, ctx)
+	}
+
+	var curTime = time.Now()
+	if err := s.roleRepo.CreateRole(entity.Role{
+		RoleId:       req.RoleId,
+		RoleName:     req.RoleName,
+		ActiveStatus: true,
+		CreatedAt:    curTime,
+		UpdatedAt:    curTime,
+	}, ctx); err != nil {
+		return err
+	}
+
+	if len(req.Permissions) > 0 {
+		if err := s.permissionRepo.SetRolePermissions(req.RoleId, req.Permissions, ctx); err != nil {
+			return err
+		}
+	}
+
+	s.inserted++
+	return nil
+}
+
+// Grant fixture permissions to an existing role which has none
+func (s *seeder) repairRolePermissions(req request.SeedRole, ctx context.Context) error {
+	if len(req.Permissions) == 0 {
+		s.skipped++
+		return nil
+	}
+
+	permissions, err := s.permissionRepo.GetPermissionsByRole(req.RoleId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if permissions != nil && len(*permissions) > 0 {
+		s.skipped++
+		return nil
+	}
+
+	if err := s.permissionRepo.SetRolePermissions(req.RoleId, req.Permissions, ctx); err != nil {
+		return err
+	}
+
+	s.repaired++
+	return nil
+}
+
+func (s *seeder) seedUser(req request.SeedUser, ctx context.Context) error {
+	if req.Email == "" || req.Password == "" {
+		return errors.New("user fixture requires email and password")
+	}
+
+	existed, err := s.userRepo.GetUserByEmail(req.Email, ctx)
+	if err != nil {
+		return err
+	}
+
+	if existed != nil {
+		return s.repairUserSecurity(existed.UserId, ctx)
+	}
+
+	if !utils.IsPasswordSecure(req.Password) {
+		return fmt.Errorf("password of user %s is not secure", req.Email)
+	}
+
+	hashPw, err := utils.ToHashString(req.Password, s.logger)
+	if err != nil {
+		return err
+	}
+
+	if req.UserId == "" {
+		req.UserId = utils.GenerateId()
+	}
+
+	if req.RoleId == "" {
+		req.RoleId = config.Get().Role.UserRole
+	}
+
+	if req.FullName == "" {
+		req.FullName = req.Email
+	}
+
+	if req.Gender == "" {
+		req.Gender = "Unknown"
+	}
+
+	var curTime = time.Now()
+	if err := s.userRepo.CreateUser(entity.User{
+		UserId:        req.UserId,
+		RoleId:        req.RoleId,
+		FullName:      req.FullName,
+		Email:         req.Email,
+		Password:      hashPw,
+		ProfileAvatar: req.ProfileAvatar,
+		Gender:        req.Gender,
+		IsVip:         req.IsVip,
+		IsActive:      true,
+		IsActivated:   true,
+		CreatedAt:     curTime,
+		UpdatedAt:     curTime,
+	}, ctx); err != nil {
+		return err
+	}
+
+	if err := s.createUserSecurity(req.UserId, ctx); err != nil {
+		return err
+	}
+
+	s.inserted++
+	return nil
+}
+
+// Create the missing security record of an existing user
+func (s *seeder) repairUserSecurity(userId string, ctx context.Context) error {
+	security, err := s.userSecurityRepo.GetUserSecurity(userId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if security != nil {
+		s.skipped++
+		return nil
+	}
+
+	if err := s.createUserSecurity(userId, ctx); err != nil {
+		return err
+	}
+
+	s.repaired++
+	return nil
+}
+
+func (s *seeder) createUserSecurity(userId string, ctx context.Context) error {
+	var tmpTime = utils.GetPrimitiveTime()
+	return s.userSecurityRepo.CreateUserSecurity(entity.UserSecurity{
+		UserId:     userId,
+		FailAccess: 0,
+		LastFail:   &tmpTime,
+	}, ctx)
+}
+
+func (s *seeder) seedCategory(req request.SeedCategory, ctx context.Context) error {
+	if req.CategoryId == "" || req.CategoryName == "" {
+		return errors.New("category fixture requires category_id and category_name")
+	}
+
+	existed, err := s.categoryRepo.GetCategoryById(req.CategoryId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if existed != nil {
+		s.skipped++
+		return nil
+	}
+
+	var curTime = time.Now()
+	if err := s.categoryRepo.CreateCategory(entity.Category{
+		CategoryId:   req.CategoryId,
+		CategoryName: req.CategoryName,
+		Description:  req.Description,
+		ActiveStatus: true,
+		CreatedAt:    curTime,
+		UpdatedAt:    curTime,
+	}, ctx); err != nil {
+		return err
+	}
+
+	s.inserted++
+	return nil
+}
+
+func (s *seeder) seedCollection(req request.SeedCollection, ctx context.Context) error {
+	if req.CollectionId == "" || req.CollectionName == "" {
+		return errors.New("collection fixture requires collection_id and collection_name")
+	}
+
+	existed, err := s.collectionRepo.GetCollectionById(req.CollectionId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if existed != nil {
+		s.skipped++
+		return nil
+	}
+
+	var curTime = time.Now()
+	if err := s.collectionRepo.CreateCollection(entity.Collection{
+		CollectionId:   req.CollectionId,
+		CollectionName: req.CollectionName,
+		Description:    req.Description,
+		ActiveStatus:   true,
+		CreatedAt:      curTime,
+		UpdatedAt:      curTime,
+	}, ctx); err != nil {
+		return err
+	}
+
+	s.inserted++
+	return nil
+}
+
+func (s *seeder) seedProduct(req request.SeedProduct, ctx context.Context) error {
+	if req.ProductId == "" || req.ProductName == "" {
+		return errors.New("product fixture requires product_id and product_name")
+	}
+
+	if req.Price < 0 || req.Quantity < 0 {
+		return fmt.Errorf("product %s has negative price or quantity", req.ProductId)
+	}
+
+	existed, err := s.productRepo.GetProductById(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if existed != nil {
+		return s.repairProductInventory(req, ctx)
+	}
+
+	if req.Currency == "" {
+		req.Currency = currency.VIETNAM_DONG
+	}
+
+	var curTime = time.Now()
+	if err := s.productRepo.CreateProduct(entity.Product{
+		ProductId:    req.ProductId,
+		CategoryId:   req.CategoryId,
+		CollectionId: req.CollectionId,
+		ProductName:  req.ProductName,
+		Description:  req.Description,
+		Image:        req.Image,
+		Size:         req.Size,
+		Color:        req.Color,
+		Price:        req.Price,
+		Currency:     req.Currency,
+		ActiveStatus: true,
+		CreatedAt:    curTime,
+		UpdatedAt:    curTime,
+	}, ctx); err != nil {
+		return err
+	}
+
+	if err := s.inventoryRepo.CreateProductInventory(entity.ProductInventory{
+		ProductId: req.ProductId,
+	}, ctx); err != nil {
+		return err
+	}
+
+	if err := s.stockInitialQuantity(req, ctx); err != nil {
+		return err
+	}
+
+	s.inserted++
+	return nil
+}
+
+// Create the missing inventory of an existing product and its initial stock if the ledger of the product is still empty
+func (s *seeder) repairProductInventory(req request.SeedProduct, ctx context.Context) error {
+	inventory, err := s.inventoryRepo.GetProductInventory(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	var isRepaired bool
+	if inventory == nil {
+		if err := s.inventoryRepo.CreateProductInventory(entity.ProductInventory{
+			ProductId: req.ProductId,
+		}, ctx); err != nil {
+			return err
+		}
+
+		inventory = &entity.ProductInventory{ProductId: req.ProductId}
+		isRepaired = true
+	}
+
+	if req.Quantity > 0 && inventory.CurrentQuantity == 0 {
+		_, count, err := s.inventoryTxRepo.GetInventoryTransactionsByProduct(request.GetProductInventoryTrasactionsByProductRequest{
+			ProductId: req.ProductId,
+			InventoryTransaction: request.GetProductInventoryTrasactionsRequest{
+				Pagination: request.SearchPaginatioRequest{
+					PageNumber: 1,
+					FilterProp: "created_at",
+					Order:      "ASC",
+				},
+			},
+		}, ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			if err := s.stockInitialQuantity(req, ctx); err != nil {
+				return err
+			}
+
+			isRepaired = true
+		}
+	}
+
+	if isRepaired {
+		s.repaired++
+	} else {
+		s.skipped++
+	}
+
+	return nil
+}
+
+// Initial quantity goes through the ledger, stocked at the default warehouse
+func (s *seeder) stockInitialQuantity(req request.SeedProduct, ctx context.Context) error {
+	if req.Quantity <= 0 {
+		return nil
+	}
+
+	var curTime = time.Now()
+	_, err := s.inventoryTxRepo.AppendProductInventoryTransactions([]entity.ProductInventoryTransaction{
+		{
+			TransactionId:  utils.GenerateId(),
+			ProductId:      req.ProductId,
+			WarehouseId:    config.Get().Inventory.DefaultWarehouse,
+			Amount:         req.Quantity,
+			Action:         businesslogic.Import_action,
+			Note:           "Initial stock",
+			Date:           curTime,
+			CreatedAt:      curTime,
+			UpdatedAt:      curTime,
+			QuantityChange: req.Quantity,
+		},
+	}, ctx)
+
+	return err
+}
+
+func (s *seeder) seedVoucher(req request.SeedVoucher, ctx context.Context) error {
+	if req.Code == "" {
+		return errors.New("voucher fixture requires code")
+	}
+
+	existed, err := s.voucherRepo.GetVoucherByCode(req.Code, ctx)
+	if err != nil {
+		return err
+	}
+
+	if existed != nil {
+		s.skipped++
+		return nil
+	}
+
+	if req.VoucherId == "" {
+		req.VoucherId = utils.GenerateId()
+	}
+
+	if req.ExpiresInDays <= 0 {
+		req.ExpiresInDays = 30
+	}
+
+	var curTime = time.Now()
+	if err := s.voucherRepo.CreateVoucher(entity.Voucher{
+		VoucherId:          req.VoucherId,
+		Code:               req.Code,
+		Discount:           req.Discount,
+		Amount:             req.Amount,
+		Description:        req.Description,
+		ActiveStatus:       true,
+		AllowedCategoryIDs: req.AllowedCategoryIDs,
+		AllowedProductIDs:  req.AllowedProductIDs,
+		ExpiredAt:          curTime.AddDate(0, 0, req.ExpiresInDays),
+		CreatedAt:          curTime,
+		UpdatedAt:          curTime,
+	}, ctx); err != nil {
+		return err
+	}
+
+	s.inserted++
+	return nil
+}
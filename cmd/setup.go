@@ -4,14 +4,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	api_route "sonit_server/api_route"
-	"sonit_server/constant/env"
-	payment_env "sonit_server/constant/env/payment"
 	"sonit_server/constant/noti"
 	payment_method "sonit_server/constant/payment_method"
 	"sonit_server/docs"
 	_ "sonit_server/docs"
+	"sonit_server/utils/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/payOSHQ/payos-lib-golang"
@@ -26,6 +24,9 @@ func setupApiRoutes(server *gin.Engine, port string) {
 	// User API endpoints
 	api_route.InitializeUserHandlerRoute(server, port)
 
+	// Two-factor authentication API endpoints
+	api_route.InitializeMfaHandlerRoute(server, port)
+
 	// Collection API endpoints
 	api_route.InitializeCollectionHandlerRoute(server, port)
 
@@ -35,21 +36,39 @@ func setupApiRoutes(server *gin.Engine, port string) {
 	// Voucher API endpoints
 	api_route.InitializeVoucherHandlerRoute(server, port)
 
+	// VIP Tier API endpoints
+	api_route.InitializeVipTierHandlerRoute(server, port)
+
 	// Product API endpoints
 	api_route.InitializeProductHandlerRoute(server, port)
 
 	// Product Inventory Transaction API endpoints
 	api_route.InitializeProductInventoryTransactionHandlerRoute(server, port)
 
+	// Inventory API endpoints
+	api_route.InitializeInventoryHandlerRoute(server, port)
+
+	// Warehouse API endpoints
+	api_route.InitializeWarehouseHandlerRoute(server, port)
+
 	// Cart API endpoints
 	api_route.InitializeCartHandlerRoute(server, port)
 
+	// Wishlist API endpoints
+	api_route.InitializeWishlistHandlerRoute(server, port)
+
+	// Stock subscription API endpoints
+	api_route.InitializeStockSubscriptionHandlerRoute(server, port)
+
 	// Payment API endpoints
 	api_route.InitializePaymentHandlerRoute(server, port)
 
 	// Order API endpoints
 	api_route.InitializeOrderHandlerRoute(server, port)
 
+	// Cache API endpoints
+	api_route.InitializeCacheHandlerRoute(server, port)
+
 	server.GET("/", func(ctx *gin.Context) {
 		ctx.Redirect(http.StatusMovedPermanently, "/swagger/index.html#")
 	})
@@ -61,7 +80,7 @@ func setupSwagger(server *gin.Engine, port string) {
 	docs.SwaggerInfo.Version = "1.0"
 	docs.SwaggerInfo.Schemes = []string{"http"}
 	docs.SwaggerInfo.Host = "localhost:" + port
-	docs.SwaggerInfo.Host = os.Getenv(env.SWAGGER_HOST)
+	docs.SwaggerInfo.Host = config.Get().Server.SwaggerHost
 
 	// Add swagger route
 	server.GET("swagger/*any", gin_swagger.WrapHandler(swagger_files.Handler))
@@ -69,7 +88,7 @@ func setupSwagger(server *gin.Engine, port string) {
 
 func setupPayments(logger *log.Logger) {
 	// Payos
-	if err := payos.Key(os.Getenv(payment_env.PAYOS_CLIENT_ID), os.Getenv(payment_env.PAYOS_API_KEY), os.Getenv(payment_env.PAYOS_CHECKSUM_KEY)); err != nil {
+	if err := payos.Key(config.Get().Payment.PayosClientId, config.Get().Payment.PayosApiKey, config.Get().Payment.PayosChecksumKey); err != nil {
 		logger.Println(fmt.Sprintf(noti.PAYMENT_INIT_ENV_ERR_MSG, payment_method.PAYOS) + err.Error())
 	}
 }
@@ -0,0 +1,9 @@
+package actiontype
+
+// Rules to merge quantity of a product in both guest cart and user cart, the result never exceeds stock
+const (
+	CART_MERGE_SUM   string = "sum"   // Add both quantities
+	CART_MERGE_MAX   string = "max"   // Keep the larger quantity
+	CART_MERGE_GUEST string = "guest" // Guest cart wins
+	CART_MERGE_USER  string = "user"  // User cart wins, only new products are added
+)
@@ -0,0 +1,8 @@
+package actiontype
+
+// Account lockout audit actions
+const (
+	LOCKOUT_LOCK_ACTION        string = "LOCK"
+	LOCKOUT_UNLOCK_ACTION      string = "UNLOCK"      // By admin
+	LOCKOUT_AUTO_UNLOCK_ACTION string = "AUTO_UNLOCK" // Lock time passed
+)
@@ -4,4 +4,11 @@ const (
 	RESET_PASSWORD_TYPE string = "RESET_PASSWORD"
 	ACTIVATE_TYPE       string = "ACTIVATE"
 	VERIFY_TYPE         string = "VERIFY"
+	MFA_REQUIRED_TYPE   string = "MFA_REQUIRED" // Password is correct, second factor is needed
+)
+
+// Social login providers
+const (
+	GOOGLE_PROVIDER   string = "google"
+	FACEBOOK_PROVIDER string = "facebook"
 )
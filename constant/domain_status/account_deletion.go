@@ -0,0 +1,7 @@
+package domainstatus
+
+const (
+	ACCOUNT_DELETION_SCHEDULED string = "SCHEDULED" // CHỜ HẾT THỜI GIAN ÂN HẠN
+	ACCOUNT_DELETION_CANCELLED string = "CANCELLED" // NGƯỜI DÙNG HỦY YÊU CẦU
+	ACCOUNT_DELETION_COMPLETED string = "COMPLETED" // ĐÃ ẨN DANH HÓA TÀI KHOẢN
+)
@@ -0,0 +1,9 @@
+package domainstatus
+
+// Status of a cart line revalidated against current product and stock
+const (
+	CART_LINE_AVAILABLE          string = "AVAILABLE"
+	CART_LINE_PRICE_CHANGED      string = "PRICE_CHANGED"      // Repriced since acknowledged
+	CART_LINE_INSUFFICIENT_STOCK string = "INSUFFICIENT_STOCK" // Less stock than the line quantity
+	CART_LINE_UNAVAILABLE        string = "UNAVAILABLE"        // Deactivated, removed or out of stock
+)
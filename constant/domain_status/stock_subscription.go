@@ -0,0 +1,9 @@
+package domainstatus
+
+// Status of a back in stock subscription
+const (
+	STOCK_SUBSCRIPTION_WAITING  string = "WAITING"  // Product out of stock
+	STOCK_SUBSCRIPTION_QUEUED   string = "QUEUED"   // Restocked, mail waiting for its turn
+	STOCK_SUBSCRIPTION_NOTIFIED string = "NOTIFIED" // Mail sent
+	STOCK_SUBSCRIPTION_FAILED   string = "FAILED"   // Mail failed too many times
+)
@@ -0,0 +1,6 @@
+package filesupport
+
+const (
+	CSV_FORMAT  string = "csv"
+	XLSX_FORMAT string = "xlsx"
+)
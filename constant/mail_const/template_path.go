@@ -10,6 +10,18 @@ const (
 
 	RESET_PASSWORD_MAIL_TEMPLATE string = "html_template/mail/ResetPasswordForm.html"
 
+	ACCOUNT_LOCKED_MAIL_TEMPLATE string = "html_template/mail/AccountLockedForm.html"
+
+	ACCOUNT_DELETION_MAIL_TEMPLATE string = "html_template/mail/AccountDeletionForm.html"
+
+	ABANDONED_CART_MAIL_TEMPLATE string = "html_template/mail/AbandonedCartForm.html"
+
+	WISHLIST_MAIL_TEMPLATE string = "html_template/mail/WishlistForm.html"
+
+	BACK_IN_STOCK_MAIL_TEMPLATE string = "html_template/mail/BackInStockForm.html"
+
+	LOW_STOCK_MAIL_TEMPLATE string = "html_template/mail/LowStockDigestForm.html"
+
 	PAYMENT_CALLBACK_SUCCESS_TEMPLATE string = "html_template/mail/payment/success.html"
 
 	PAYMENT_CALLBACK_CANCEL_TEMPLATE string = "html_template/mail/payment/cancel.html"
@@ -5,6 +5,8 @@ const (
 
 	REPO_ERR_MSG string = "Error in %s repository at "
 
+	CACHE_ERR_MSG string = "Error in cache store at Method - "
+
 	MAIL_ERR_MSG string = "Error while generating mail at %s -  "
 
 	GIN_ERR_MSG string = "Error while starting gin server in %s service - "
@@ -17,6 +19,8 @@ const (
 	ENV_LOAD_ERR_MSG string = "Error while loading .env file in %s service - "
 
 	ENV_SET_ERR_MSG string = "Error while setting environment variable %s with value %s - "
+
+	CONFIG_LOAD_ERR_MSG string = "Error while loading configuration - "
 )
 
 // Database
@@ -33,3 +37,8 @@ const (
 	PAYMENT_INIT_ENV_ERR_MSG                 string = "Error while setup %s enrionment - "
 	PAYMENT_GENERATE_TRANSACTION_URL_ERR_MSG string = "Error while generating %s transaction URL - "
 )
+
+// Inventory
+const (
+	INVENTORY_HOOK_ERR_MSG string = "Error while handling inventory change of product %s - "
+)
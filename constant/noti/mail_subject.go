@@ -5,6 +5,15 @@ const (
 	UPDATE_EMAIL_MAIL_SUBJECT         string = "Account Profile Update Email Verification"
 	RECOVER_ACCOUNT_MAIL_SUBJECT      string = "Account Recovery Verification"
 	VERIFY_ACCOUNT_MAIL_SUBJECT       string = "Account Verification"
+	ACCOUNT_LOCKED_MAIL_SUBJECT       string = "Account Temporarily Locked"
+	ACCOUNT_DELETION_MAIL_SUBJECT     string = "Account Deletion Confirmation"
+)
+
+const (
+	ABANDONED_CART_MAIL_SUBJECT string = "Items Are Waiting In Your Cart"
+	WISHLIST_MAIL_SUBJECT       string = "Good News About Your Saved Items"
+	BACK_IN_STOCK_MAIL_SUBJECT  string = "A Product You Wanted Is Back In Stock"
+	LOW_STOCK_MAIL_SUBJECT      string = "Daily Low Stock Digest"
 )
 
 const (
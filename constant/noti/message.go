@@ -21,4 +21,8 @@ const (
 	INACTIVE_ACCOUNT_MESSAGE string = "Your account is not active now. If you have any need, please contact admin for more information or request a demand to reactivate your account."
 
 	TOKEN_EXPIRED_MESSAGE string = "Token expired."
+
+	DELETE_ACCOUNT_MESSAGE string = "Please check your mail box to confirm deleting your account."
+
+	CART_REMINDER_UNSUBSCRIBED_MESSAGE string = "You will no longer receive reminders of your cart."
 )
@@ -19,11 +19,55 @@ const (
 
 	DATA_EXISTED_WARN_MSG string = "%s is already existed. Please try another %s."
 
-	PASSWORD_NOT_SECURE_WARN_MSG string = "Password must be at least 8 characters long and include a combination of uppercase and lowercase letters, numbers, and special characters. Please try another password."
+	PASSWORD_NOT_SECURE_WARN_MSG string = "Password must be at least %d characters long%s. Please try another password."
+
+	PASSWORD_BREACHED_WARN_MSG string = "This password has appeared in a data breach. Please try another password."
+
+	PASSWORD_REUSED_WARN_MSG string = "This password has been used recently. Please try another password."
+
+	PASSWORD_NOT_MATCHED_WARN_MSG string = "Passwords do not match. Please try again."
+
+	PASSWORD_INCORRECT_WARN_MSG string = "Current password is incorrect. Please try again."
 
 	GENERIC_RIGHT_ACCESS_WARN_MSG string = "You have no rights to access this action."
 
 	INVALID_OBJECT_WARN_MSG string = "The object already expired."
 
 	ITEM_OUT_OF_STOCK_WARN_MSG string = "This product is out of stock with %d items added to cart."
+
+	CART_CHANGED_WARN_MSG string = "Items in your cart have changed. Please review and acknowledge the changes before checkout."
+
+	PRODUCT_IN_STOCK_WARN_MSG string = "This product is in stock. You can only subscribe to out-of-stock products."
+
+	TOO_MANY_REQUESTS_WARN_MSG string = "Too many requests. Please try again later."
+
+	INVENTORY_NOT_ENOUGH_WARN_MSG string = "Product inventory is not enough for this action. Please try again."
+
+	INVENTORY_TX_CORRECTED_WARN_MSG string = "This inventory transaction has already been corrected."
+
+	WAREHOUSE_INACTIVE_WARN_MSG string = "This warehouse is not active."
+
+	SPREADSHEET_INVALID_WARN_MSG string = "Unsupported or invalid file. Please upload a CSV or XLSX file of at most 5 MB."
+
+	INVENTORY_IMPORT_HEADER_WARN_MSG string = "The first row of the file must name its columns: sku, quantity, action and note."
+
+	INVENTORY_IMPORT_SKU_WARN_MSG string = "Product not found."
+
+	INVENTORY_IMPORT_QUANTITY_WARN_MSG string = "Quantity must be a whole number greater than zero."
+
+	INVENTORY_IMPORT_COUNT_WARN_MSG string = "Counted quantity must be a whole number, zero or more."
+
+	INVENTORY_IMPORT_ACTION_WARN_MSG string = "Unsupported action. Use import, export, sale, return or cancel."
+
+	INVENTORY_IMPORT_DUPLICATED_WARN_MSG string = "Product is counted on another row."
+
+	SESSION_REVOKED_WARN_MSG string = "Your session has ended. Please log in again."
+
+	ACCOUNT_LOCKED_WARN_MSG string = "Your account is temporarily locked due to too many failed login attempts. Please try again later."
+
+	MFA_CODE_INVALID_WARN_MSG string = "Invalid verification code. Please try again."
+
+	MFA_REQUIRED_WARN_MSG string = "Two-factor authentication is required for this action. Please enable it and log in again."
+
+	ACCOUNT_DELETION_SCHEDULED_WARN_MSG string = "Your account is already scheduled for deletion."
 )
@@ -0,0 +1,19 @@
+package permission
+
+// Permission catalog, each one is seeded in permissions table and granted to roles through role_permissions
+const (
+	USER_MANAGE      string = "user:manage"      // View, unlock and edit other accounts
+	ROLE_MANAGE      string = "role:manage"      // Roles and their permissions
+	CATEGORY_WRITE   string = "category:write"   // Manage categories
+	COLLECTION_WRITE string = "collection:write" // Manage collections
+	PRODUCT_WRITE    string = "product:write"    // Manage products, including inactive ones
+	INVENTORY_ADJUST string = "inventory:adjust" // Product inventory transactions
+	ORDER_READ       string = "order:read"       // Orders of every user
+	ORDER_WRITE      string = "order:write"      // Create and update orders of every user
+	ORDER_REFUND     string = "order:refund"     // Set orders and payments as refunded
+	PAYMENT_READ     string = "payment:read"     // Payments of every user
+	PAYMENT_WRITE    string = "payment:write"    // Update payment status, e.g. refunds
+	VOUCHER_WRITE    string = "voucher:write"    // Manage vouchers
+	VIP_TIER_WRITE   string = "vip_tier:write"   // Manage VIP tiers
+	SYSTEM_MONITOR   string = "system:monitor"   // Cache metrics
+)
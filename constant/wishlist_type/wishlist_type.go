@@ -0,0 +1,7 @@
+package wishlisttype
+
+// Lists a user can save products to
+const (
+	WISHLIST        string = "WISHLIST"
+	SAVED_FOR_LATER string = "SAVED_FOR_LATER" // Moved out of the cart
+)
@@ -0,0 +1,176 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	domain_status "sonit_server/constant/domain_status"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type accountDeletionRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeAccountDeletionRepo(db *sql.DB, logger *log.Logger) data_access.IAccountDeletionRepo {
+	return &accountDeletionRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetScheduledAccountDeletion implements dataaccess.IAccountDeletionRepo.
+func (a *accountDeletionRepo) GetScheduledAccountDeletion(userId string, ctx context.Context) (*entity.AccountDeletion, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetAccountDeletionTable()) + "GetScheduledAccountDeletion - "
+	var query string = "SELECT id, user_id, status, scheduled_at, completed_at, created_at, updated_at FROM " + entity.GetAccountDeletionTable() + " WHERE user_id = $1 AND status = $2"
+
+	var res entity.AccountDeletion
+	if err := a.db.QueryRow(query, userId, domain_status.ACCOUNT_DELETION_SCHEDULED).Scan(
+		&res.DeletionId, &res.UserId, &res.Status, &res.ScheduledAt,
+		&res.CompletedAt, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		a.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// GetDueAccountDeletions implements dataaccess.IAccountDeletionRepo.
+func (a *accountDeletionRepo) GetDueAccountDeletions(dueAt time.Time, ctx context.Context) (*[]entity.AccountDeletion, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetAccountDeletionTable()) + "GetDueAccountDeletions - "
+	var query string = "SELECT id, user_id, status, scheduled_at, completed_at, created_at, updated_at FROM " + entity.GetAccountDeletionTable() + " WHERE status = $1 AND scheduled_at <= $2 ORDER BY scheduled_at"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := a.db.Query(query, domain_status.ACCOUNT_DELETION_SCHEDULED, dueAt)
+	if err != nil {
+		a.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.AccountDeletion
+	for rows.Next() {
+		var x entity.AccountDeletion
+		if err := rows.Scan(&x.DeletionId, &x.UserId, &x.Status, &x.ScheduledAt, &x.CompletedAt, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			a.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// CreateAccountDeletion implements dataaccess.IAccountDeletionRepo.
+func (a *accountDeletionRepo) CreateAccountDeletion(deletion entity.AccountDeletion, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetAccountDeletionTable()) + "CreateAccountDeletion - "
+	var query string = "INSERT INTO " + entity.GetAccountDeletionTable() + " (id, user_id, status, scheduled_at, completed_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+
+	if _, err := a.db.Exec(query, deletion.DeletionId, deletion.UserId, deletion.Status, deletion.ScheduledAt, deletion.CompletedAt, deletion.CreatedAt, deletion.UpdatedAt); err != nil {
+		a.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// UpdateAccountDeletion implements dataaccess.IAccountDeletionRepo.
+func (a *accountDeletionRepo) UpdateAccountDeletion(deletion entity.AccountDeletion, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetAccountDeletionTable()) + "UpdateAccountDeletion - "
+	var query string = "UPDATE " + entity.GetAccountDeletionTable() + " SET status = $1, completed_at = $2, updated_at = $3 WHERE id = $4"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := a.db.Exec(query, deletion.Status, deletion.CompletedAt, deletion.UpdatedAt, deletion.DeletionId)
+	if err != nil {
+		a.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		a.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetAccountDeletionTable()))
+	}
+
+	return nil
+}
+
+// AnonymizeAccount implements dataaccess.IAccountDeletionRepo.
+// Replaces personal data of the user and removes its credentials in one transaction, orders and payments are kept for accounting
+func (a *accountDeletionRepo) AnonymizeAccount(deletion entity.AccountDeletion, user entity.User, shippingDetail string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetAccountDeletionTable()) + "AnonymizeAccount - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		a.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	var statements = []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			"UPDATE " + entity.GetUserTable() + " SET full_name = $1, email = $2, password = $3, profile_avatar = $4, gender = $5, is_vip = false, vip_code = NULL, is_active = false, is_have_to_reset_password = NULL, updated_at = $6 WHERE id = $7",
+			[]interface{}{user.FullName, user.Email, user.Password, user.ProfileAvatar, user.Gender, user.UpdatedAt, user.UserId},
+		},
+		{
+			"UPDATE " + entity.GetUserSecurityTable() + " SET access_token = NULL, refresh_token = NULL, action_token = NULL WHERE id = $1",
+			[]interface{}{user.UserId},
+		},
+		{
+			"UPDATE " + entity.GetShippingTable() + " SET shipping_detail = $1, updated_at = $2 WHERE id IN (SELECT id FROM " + entity.GetOrderTable() + " WHERE user_id = $3)",
+			[]interface{}{shippingDetail, user.UpdatedAt, user.UserId},
+		},
+		// Cart lines, including the ones merged from guest carts on login, are removed with the cart
+		{"DELETE FROM " + entity.GetCartTable() + " WHERE id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetIdentityTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetUserTotpTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetRecoveryCodeTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetPasswordHistoryTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetLockoutEventTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetSessionTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetUserTierTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetCartReminderTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetCartReminderOptOutTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetWishlistTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{"DELETE FROM " + entity.GetStockSubscriptionTable() + " WHERE user_id = $1", []interface{}{user.UserId}},
+		{
+			"UPDATE " + entity.GetAccountDeletionTable() + " SET status = $1, completed_at = $2, updated_at = $3 WHERE id = $4",
+			[]interface{}{deletion.Status, deletion.CompletedAt, deletion.UpdatedAt, deletion.DeletionId},
+		},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			a.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		a.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
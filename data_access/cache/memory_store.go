@@ -0,0 +1,97 @@
+package cache
+
+import (
+	data_access "sonit_server/interface/data_access"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	value     []byte
+	expiredAt time.Time // Zero means no expiration
+}
+
+// In-memory store used when Redis is not available, e.g. local run and tests
+type memoryStore struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+}
+
+func InitializeMemoryStore() data_access.ICache {
+	return &memoryStore{
+		items: make(map[string]memoryItem),
+	}
+}
+
+// Get implements dataaccess.ICache.
+func (m *memoryStore) Get(key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.getItem(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	return item.value, true, nil
+}
+
+// Set implements dataaccess.ICache.
+func (m *memoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var item = memoryItem{value: value}
+	if ttl > 0 {
+		item.expiredAt = time.Now().Add(ttl)
+	}
+
+	m.items[key] = item
+	return nil
+}
+
+// Delete implements dataaccess.ICache.
+func (m *memoryStore) Delete(keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+
+	return nil
+}
+
+// Incr implements dataaccess.ICache.
+func (m *memoryStore) Incr(key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var res int64
+	if item, ok := m.getItem(key); ok {
+		res, _ = strconv.ParseInt(string(item.value), 10, 64)
+	}
+
+	res++
+	var item = m.items[key]
+	item.value = []byte(strconv.FormatInt(res, 10))
+	m.items[key] = item
+
+	return res, nil
+}
+
+// Caller must hold the lock, expired item is removed
+func (m *memoryStore) getItem(key string) (memoryItem, bool) {
+	item, ok := m.items[key]
+	if !ok {
+		return item, false
+	}
+
+	if !item.expiredAt.IsZero() && time.Now().After(item.expiredAt) {
+		delete(m.items, key)
+		return item, false
+	}
+
+	return item, true
+}
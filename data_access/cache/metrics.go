@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type counter struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Cache hit / miss statistic of a namespace
+type Metric struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+var counters sync.Map // namespace -> *counter
+
+func getCounter(namespace string) *counter {
+	res, _ := counters.LoadOrStore(namespace, &counter{})
+	return res.(*counter)
+}
+
+func recordHit(namespace string) {
+	getCounter(namespace).hits.Add(1)
+}
+
+func recordMiss(namespace string) {
+	getCounter(namespace).misses.Add(1)
+}
+
+// Get hit / miss metrics of every namespace since the application started
+func GetMetrics() map[string]Metric {
+	var res = make(map[string]Metric)
+	counters.Range(func(key, value any) bool {
+		var c = value.(*counter)
+		var metric = Metric{
+			Hits:   c.hits.Load(),
+			Misses: c.misses.Load(),
+		}
+
+		if total := metric.Hits + metric.Misses; total > 0 {
+			metric.HitRate = float64(metric.Hits) / float64(total)
+		}
+
+		res[key.(string)] = metric
+		return true
+	})
+
+	return res
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	data_access "sonit_server/interface/data_access"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const key_prefix string = "sonit"
+
+// Group of cached keys sharing a version, bumping the version invalidates every key at once
+type Namespace struct {
+	name   string
+	store  data_access.ICache
+	ttl    time.Duration
+	logger *log.Logger
+}
+
+func NewNamespace(name string, store data_access.ICache, ttl time.Duration, logger *log.Logger) *Namespace {
+	return &Namespace{
+		name:   name,
+		store:  store,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+func (n *Namespace) versionKey() string {
+	return key_prefix + ":" + n.name + ":version"
+}
+
+// Current version of the namespace, 0 if never invalidated
+func (n *Namespace) version() int64 {
+	data, ok, err := n.store.Get(n.versionKey())
+	if err != nil || !ok {
+		return 0
+	}
+
+	res, _ := strconv.ParseInt(string(data), 10, 64)
+	return res
+}
+
+// Build versioned key, e.g. sonit:products:v3:id:prod1
+func (n *Namespace) Key(parts ...string) string {
+	return fmt.Sprintf("%s:%s:v%d:%s", key_prefix, n.name, n.version(), strings.Join(parts, ":"))
+}
+
+// Invalidate every cached key of the namespace
+func (n *Namespace) Invalidate() {
+	if _, err := n.store.Incr(n.versionKey()); err != nil {
+		n.logger.Println(fmt.Sprintf("Error while invalidating %s cache - ", n.name) + err.Error())
+	}
+}
+
+// Read value from cache, otherwise load it from source and cache the result.
+// Cache errors never fail the read, the source is used instead
+func ReadThrough[T any](n *Namespace, key string, load func() (T, error)) (T, error) {
+	if data, ok, err := n.store.Get(key); err == nil && ok {
+		var res T
+		if err := json.Unmarshal(data, &res); err == nil {
+			recordHit(n.name)
+			return res, nil
+		}
+	}
+
+	recordMiss(n.name)
+
+	res, err := load()
+	if err != nil {
+		return res, err
+	}
+
+	if data, err := json.Marshal(res); err == nil {
+		n.store.Set(key, data, n.ttl)
+	}
+
+	return res, nil
+}
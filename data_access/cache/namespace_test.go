@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testLogger = log.New(io.Discard, "", 0)
+
+// Store failing every call, e.g. Redis down
+type failingStore struct{}
+
+func (failingStore) Get(key string) ([]byte, bool, error) {
+	return nil, false, errors.New("store down")
+}
+
+func (failingStore) Set(key string, value []byte, ttl time.Duration) error {
+	return errors.New("store down")
+}
+
+func (failingStore) Delete(keys ...string) error {
+	return errors.New("store down")
+}
+
+func (failingStore) Incr(key string) (int64, error) {
+	return 0, errors.New("store down")
+}
+
+type cachedItem struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Loader counting its calls
+func countingLoader(calls *int, res cachedItem, err error) func() (cachedItem, error) {
+	return func() (cachedItem, error) {
+		*calls++
+		return res, err
+	}
+}
+
+func TestReadThroughCachesLoadedValue(t *testing.T) {
+	var ns = NewNamespace("test_read_through", InitializeMemoryStore(), time.Minute, testLogger)
+	var item = cachedItem{Id: "prod1", Name: "Ring"}
+	var before = GetMetrics()["test_read_through"]
+	var calls int
+
+	for i := 0; i < 3; i++ {
+		res, err := ReadThrough(ns, ns.Key("id", "prod1"), countingLoader(&calls, item, nil))
+		if err != nil {
+			t.Fatalf("read %d: unexpected error %v", i, err)
+		}
+
+		if res != item {
+			t.Fatalf("read %d: got %+v, want %+v", i, res, item)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("source loaded %d times, want 1", calls)
+	}
+
+	var metric = GetMetrics()["test_read_through"]
+	if hits, misses := metric.Hits-before.Hits, metric.Misses-before.Misses; hits != 2 || misses != 1 {
+		t.Fatalf("got %d hits and %d misses, want 2 and 1", hits, misses)
+	}
+}
+
+func TestReadThroughDoesNotCacheErrors(t *testing.T) {
+	var ns = NewNamespace("test_read_error", InitializeMemoryStore(), time.Minute, testLogger)
+	var loadErr = errors.New("db down")
+	var calls int
+
+	if _, err := ReadThrough(ns, ns.Key("all"), countingLoader(&calls, cachedItem{}, loadErr)); err != loadErr {
+		t.Fatalf("got error %v, want %v", err, loadErr)
+	}
+
+	var item = cachedItem{Id: "cate1"}
+	res, err := ReadThrough(ns, ns.Key("all"), countingLoader(&calls, item, nil))
+	if err != nil || res != item {
+		t.Fatalf("got %+v, %v after a failed load, want %+v", res, err, item)
+	}
+
+	if calls != 2 {
+		t.Fatalf("source loaded %d times, want 2", calls)
+	}
+}
+
+func TestReadThroughFallsBackToSource(t *testing.T) {
+	var tests = []struct {
+		name  string
+		store func() *Namespace
+	}{
+		{
+			name: "store errors",
+			store: func() *Namespace {
+				return NewNamespace("test_failing_store", failingStore{}, time.Minute, testLogger)
+			},
+		},
+		{
+			name: "undecodable value",
+			store: func() *Namespace {
+				var ns = NewNamespace("test_corrupted", InitializeMemoryStore(), time.Minute, testLogger)
+				ns.store.Set(ns.Key("id", "1"), []byte("{not json"), time.Minute)
+				return ns
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ns = tt.store()
+			var item = cachedItem{Id: "1"}
+			var calls int
+
+			res, err := ReadThrough(ns, ns.Key("id", "1"), countingLoader(&calls, item, nil))
+			if err != nil || res != item {
+				t.Fatalf("got %+v, %v, want %+v", res, err, item)
+			}
+
+			if calls != 1 {
+				t.Fatalf("source loaded %d times, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestReadThroughExpires(t *testing.T) {
+	var ns = NewNamespace("test_ttl", InitializeMemoryStore(), 20*time.Millisecond, testLogger)
+	var calls int
+
+	ReadThrough(ns, ns.Key("all"), countingLoader(&calls, cachedItem{}, nil))
+	time.Sleep(40 * time.Millisecond)
+	ReadThrough(ns, ns.Key("all"), countingLoader(&calls, cachedItem{}, nil))
+
+	if calls != 2 {
+		t.Fatalf("source loaded %d times, want 2 once the value expired", calls)
+	}
+}
+
+func TestNamespaceKeyIsVersioned(t *testing.T) {
+	var ns = NewNamespace("products", InitializeMemoryStore(), time.Minute, testLogger)
+
+	if key := ns.Key("id", "prod1"); key != "sonit:products:v0:id:prod1" {
+		t.Fatalf("got key %s before invalidation", key)
+	}
+
+	ns.Invalidate()
+	ns.Invalidate()
+
+	if key := ns.Key("id", "prod1"); key != "sonit:products:v2:id:prod1" {
+		t.Fatalf("got key %s after 2 invalidations", key)
+	}
+}
+
+func TestNamespaceInvalidate(t *testing.T) {
+	var store = InitializeMemoryStore()
+	var products = NewNamespace("products", store, time.Minute, testLogger)
+	var vouchers = NewNamespace("vouchers", store, time.Minute, testLogger)
+	var productCalls, voucherCalls int
+
+	ReadThrough(products, products.Key("all"), countingLoader(&productCalls, cachedItem{Name: "old"}, nil))
+	ReadThrough(vouchers, vouchers.Key("all"), countingLoader(&voucherCalls, cachedItem{}, nil))
+
+	products.Invalidate()
+
+	res, _ := ReadThrough(products, products.Key("all"), countingLoader(&productCalls, cachedItem{Name: "new"}, nil))
+	if res.Name != "new" || productCalls != 2 {
+		t.Fatalf("got %q after %d loads, want the value reloaded after invalidation", res.Name, productCalls)
+	}
+
+	// Other namespaces of the same store keep their keys
+	ReadThrough(vouchers, vouchers.Key("all"), countingLoader(&voucherCalls, cachedItem{}, nil))
+	if voucherCalls != 1 {
+		t.Fatalf("vouchers loaded %d times, want 1", voucherCalls)
+	}
+}
+
+func TestNamespaceInvalidateWithFailingStore(t *testing.T) {
+	var out strings.Builder
+	var ns = NewNamespace("test_failing_invalidate", failingStore{}, time.Minute, log.New(&out, "", 0))
+
+	ns.Invalidate()
+
+	if !strings.Contains(out.String(), "test_failing_invalidate") {
+		t.Fatalf("failed invalidation is not logged, got %q", out.String())
+	}
+}
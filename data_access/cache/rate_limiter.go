@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"errors"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+var (
+	limiter     data_access.IRateLimiter
+	limiterOnce sync.Once
+	hitSeq      atomic.Uint64 // Makes sorted set members unique within the same nanosecond
+)
+
+// Get shared rate limiter, backed by Redis when reachable otherwise in-memory
+func GetRateLimiter(logger *log.Logger) data_access.IRateLimiter {
+	limiterOnce.Do(func() {
+		if client := GetRedisClient(logger); client != nil {
+			limiter = InitializeRedisRateLimiter(client, logger)
+			return
+		}
+
+		logger.Println("Redis is not available, fall back to in-memory rate limiter.")
+		limiter = InitializeMemoryRateLimiter()
+	})
+
+	return limiter
+}
+
+// Sliding window limiter on a Redis sorted set, each hit is a member scored by its timestamp
+type redisRateLimiter struct {
+	client *redis.Client
+	logger *log.Logger
+}
+
+func InitializeRedisRateLimiter(client *redis.Client, logger *log.Logger) data_access.IRateLimiter {
+	return &redisRateLimiter{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Allow implements dataaccess.IRateLimiter.
+func (r *redisRateLimiter) Allow(key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	var now = time.Now().UnixNano()
+	var member = strconv.FormatInt(now, 10) + "-" + strconv.FormatUint(hitSeq.Add(1), 10)
+
+	var pipe = r.client.TxPipeline()
+	pipe.ZRemRangeByScore(key, "0", strconv.FormatInt(now-window.Nanoseconds(), 10))
+	var count = pipe.ZCard(key)
+	pipe.ZAdd(key, redis.Z{Score: float64(now), Member: member})
+	pipe.PExpire(key, window)
+
+	if _, err := pipe.Exec(); err != nil {
+		r.logger.Println(noti.CACHE_ERR_MSG + "Allow - " + err.Error())
+		return true, 0, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	if count.Val() < int64(limit) {
+		return true, 0, nil
+	}
+
+	// Rejected hits are not counted
+	r.client.ZRem(key, member)
+
+	oldest, err := r.client.ZRangeWithScores(key, 0, 0).Result()
+	if err != nil || len(oldest) == 0 {
+		return false, window, nil
+	}
+
+	return false, time.Duration(int64(oldest[0].Score) + window.Nanoseconds() - now), nil
+}
+
+const memory_limiter_sweep_interval uint64 = 1000
+
+type memoryWindow struct {
+	hits   []time.Time
+	window time.Duration
+}
+
+// Sliding window limiter kept in process memory
+type memoryRateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+	calls   uint64
+}
+
+func InitializeMemoryRateLimiter() data_access.IRateLimiter {
+	return &memoryRateLimiter{
+		windows: make(map[string]*memoryWindow),
+	}
+}
+
+// Allow implements dataaccess.IRateLimiter.
+func (m *memoryRateLimiter) Allow(key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var now = time.Now()
+
+	// Remove idle keys from time to time so memory doesn't grow with every client seen
+	m.calls++
+	if m.calls%memory_limiter_sweep_interval == 0 {
+		for k, w := range m.windows {
+			if len(w.hits) == 0 || now.Sub(w.hits[len(w.hits)-1]) > w.window {
+				delete(m.windows, k)
+			}
+		}
+	}
+
+	var w, ok = m.windows[key]
+	if !ok {
+		w = &memoryWindow{}
+		m.windows[key] = w
+	}
+	w.window = window
+
+	// Drop hits out of the window
+	var from = now.Add(-window)
+	var i int
+	for i < len(w.hits) && !w.hits[i].After(from) {
+		i++
+	}
+	w.hits = w.hits[i:]
+
+	if len(w.hits) >= limit {
+		return false, w.hits[0].Add(window).Sub(now), nil
+	}
+
+	w.hits = append(w.hits, now)
+	return true, 0, nil
+}
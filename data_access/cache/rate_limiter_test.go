@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimiterAllowsUpToLimit(t *testing.T) {
+	var limiter = InitializeMemoryRateLimiter()
+
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := limiter.Allow("login:1.1.1.1", 3, time.Minute); !ok {
+			t.Fatalf("hit %d rejected, want allowed", i+1)
+		}
+	}
+
+	ok, retryAfter, err := limiter.Allow("login:1.1.1.1", 3, time.Minute)
+	if ok || err != nil {
+		t.Fatalf("hit over the limit got %v, %v, want rejected", ok, err)
+	}
+
+	if retryAfter <= 0 || retryAfter > time.Minute {
+		t.Fatalf("got retry after %v, want within the window", retryAfter)
+	}
+}
+
+func TestMemoryRateLimiterKeysAreIndependent(t *testing.T) {
+	var limiter = InitializeMemoryRateLimiter()
+
+	limiter.Allow("login:1.1.1.1", 1, time.Minute)
+	if ok, _, _ := limiter.Allow("login:2.2.2.2", 1, time.Minute); !ok {
+		t.Fatal("hit of another key rejected")
+	}
+
+	if ok, _, _ := limiter.Allow("login:1.1.1.1", 1, time.Minute); ok {
+		t.Fatal("second hit of the first key allowed")
+	}
+}
+
+func TestMemoryRateLimiterSlidingWindow(t *testing.T) {
+	var limiter = InitializeMemoryRateLimiter()
+	var window = 100 * time.Millisecond
+
+	limiter.Allow("checkout:u1", 2, window)
+	time.Sleep(60 * time.Millisecond)
+	limiter.Allow("checkout:u1", 2, window)
+
+	// Both hits are still in the window
+	ok, retryAfter, _ := limiter.Allow("checkout:u1", 2, window)
+	if ok {
+		t.Fatal("third hit allowed within the window")
+	}
+
+	if retryAfter > 40*time.Millisecond+10*time.Millisecond {
+		t.Fatalf("got retry after %v, want the time left for the oldest hit", retryAfter)
+	}
+
+	// The oldest hit leaves the window, the second one doesn't
+	time.Sleep(50 * time.Millisecond)
+	if ok, _, _ := limiter.Allow("checkout:u1", 2, window); !ok {
+		t.Fatal("hit rejected once the oldest hit left the window")
+	}
+
+	if ok, _, _ := limiter.Allow("checkout:u1", 2, window); ok {
+		t.Fatal("hit allowed while the window is full again")
+	}
+}
+
+func TestMemoryRateLimiterDoesNotCountRejectedHits(t *testing.T) {
+	var limiter = InitializeMemoryRateLimiter()
+	var window = 50 * time.Millisecond
+
+	limiter.Allow("register:u1", 1, window)
+	for i := 0; i < 5; i++ {
+		limiter.Allow("register:u1", 1, window)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if ok, _, _ := limiter.Allow("register:u1", 1, window); !ok {
+		t.Fatal("rejected hits extended the window")
+	}
+}
+
+func TestMemoryRateLimiterConcurrentHits(t *testing.T) {
+	var limiter = InitializeMemoryRateLimiter()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var allowed int
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _, _ := limiter.Allow("login:burst", 10, time.Minute); ok {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	if allowed != 10 {
+		t.Fatalf("%d concurrent hits allowed, want 10", allowed)
+	}
+}
+
+func TestMemoryRateLimiterSweepsIdleKeys(t *testing.T) {
+	var limiter = InitializeMemoryRateLimiter().(*memoryRateLimiter)
+
+	for i := uint64(0); i < memory_limiter_sweep_interval-1; i++ {
+		limiter.Allow("idle:"+strconv.FormatUint(i, 10), 1, time.Millisecond)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	limiter.Allow("active", 1, time.Minute)
+
+	if len(limiter.windows) != 1 {
+		t.Fatalf("%d keys kept after sweep, want only the active one", len(limiter.windows))
+	}
+}
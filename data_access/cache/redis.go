@@ -2,8 +2,7 @@ package cache
 
 import (
 	"log"
-	"os"
-	"sonit_server/constant/env"
+	"sonit_server/utils/config"
 
 	"github.com/go-redis/redis"
 )
@@ -17,7 +16,7 @@ func GetRedisClient(logger *log.Logger) *redis.Client {
 	}
 
 	// Initialize new client
-	return newRedisClient(os.Getenv(env.REDIS_PORT), logger)
+	return newRedisClient(config.Get().Cache.RedisAddress, logger)
 }
 
 func newRedisClient(address string, logger *log.Logger) *redis.Client {
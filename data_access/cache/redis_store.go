@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"errors"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type redisStore struct {
+	client *redis.Client
+	logger *log.Logger
+}
+
+func InitializeRedisStore(client *redis.Client, logger *log.Logger) data_access.ICache {
+	return &redisStore{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Get implements dataaccess.ICache.
+func (r *redisStore) Get(key string) ([]byte, bool, error) {
+	res, err := r.client.Get(key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		r.logger.Println(noti.CACHE_ERR_MSG + "Get - " + err.Error())
+		return nil, false, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return res, true, nil
+}
+
+// Set implements dataaccess.ICache.
+func (r *redisStore) Set(key string, value []byte, ttl time.Duration) error {
+	if err := r.client.Set(key, value, ttl).Err(); err != nil {
+		r.logger.Println(noti.CACHE_ERR_MSG + "Set - " + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// Delete implements dataaccess.ICache.
+func (r *redisStore) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := r.client.Del(keys...).Err(); err != nil {
+		r.logger.Println(noti.CACHE_ERR_MSG + "Delete - " + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// Incr implements dataaccess.ICache.
+func (r *redisStore) Incr(key string) (int64, error) {
+	res, err := r.client.Incr(key).Result()
+	if err != nil {
+		r.logger.Println(noti.CACHE_ERR_MSG + "Incr - " + err.Error())
+		return 0, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"log"
+	data_access "sonit_server/interface/data_access"
+	"sync"
+	"time"
+)
+
+const revoked_token_key_prefix string = "sonit:revoked:"
+
+var (
+	sharedStore     data_access.ICache
+	sharedStoreOnce sync.Once
+)
+
+// Store for data which must survive regardless of the catalog cache driver, e.g. revoked tokens
+func getSharedStore(logger *log.Logger) data_access.ICache {
+	sharedStoreOnce.Do(func() {
+		if client := GetRedisClient(logger); client != nil {
+			sharedStore = InitializeRedisStore(client, logger)
+			return
+		}
+
+		sharedStore = InitializeMemoryStore()
+	})
+
+	return sharedStore
+}
+
+// Revoke a token by its jti until it expires by itself
+func RevokeToken(jti string, exp time.Time, logger *log.Logger) error {
+	var ttl = time.Until(exp)
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+
+	return getSharedStore(logger).Set(revoked_token_key_prefix+jti, []byte("1"), ttl)
+}
+
+func IsTokenRevoked(jti string, logger *log.Logger) bool {
+	_, ok, err := getSharedStore(logger).Get(revoked_token_key_prefix + jti)
+	return ok && err == nil
+}
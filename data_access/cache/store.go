@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"log"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/utils/config"
+	"sync"
+)
+
+const (
+	memory_driver string = "memory"
+	none_driver   string = "none"
+)
+
+var (
+	store     data_access.ICache
+	storeOnce sync.Once
+)
+
+// Get shared cache store by configured driver, Redis falls back to in-memory store if it can't be reached.
+// Returns nil if caching is disabled
+func GetCacheStore(logger *log.Logger) data_access.ICache {
+	storeOnce.Do(func() {
+		switch config.Get().Cache.Driver {
+		case none_driver:
+			store = nil
+		case memory_driver:
+			store = InitializeMemoryStore()
+		default: // redis
+			if client := GetRedisClient(logger); client != nil {
+				store = InitializeRedisStore(client, logger)
+			} else {
+				logger.Println("Redis is not available, fall back to in-memory cache.")
+				store = InitializeMemoryStore()
+			}
+		}
+	})
+
+	return store
+}
@@ -0,0 +1,80 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sonit_server/data_access/cache"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils/config"
+	"strconv"
+)
+
+// Read-through cache decorator of category repository
+type cachedCategoryRepo struct {
+	repo data_access.ICategoryRepo
+	ns   *cache.Namespace
+}
+
+func InitializeCachedCategoryRepo(db *sql.DB, logger *log.Logger) data_access.ICategoryRepo {
+	var repo = InitializeCategoryRepo(db, logger)
+
+	var ns = newCacheNamespace(entity.GetCategoryTable(), config.Get().Cache.CatalogTTL, logger)
+	if ns == nil {
+		return repo
+	}
+
+	return &cachedCategoryRepo{
+		repo: repo,
+		ns:   ns,
+	}
+}
+
+// GetAllCategories implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) GetAllCategories(ctx context.Context) (*[]entity.Category, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("all"), func() (*[]entity.Category, error) {
+		return c.repo.GetAllCategories(ctx)
+	})
+}
+
+// GetCategoriesByName implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) GetCategoriesByName(name string, ctx context.Context) (*[]entity.Category, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("name", name), func() (*[]entity.Category, error) {
+		return c.repo.GetCategoriesByName(name, ctx)
+	})
+}
+
+// GetCategoriesByStatus implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) GetCategoriesByStatus(status bool, ctx context.Context) (*[]entity.Category, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("status", strconv.FormatBool(status)), func() (*[]entity.Category, error) {
+		return c.repo.GetCategoriesByStatus(status, ctx)
+	})
+}
+
+// GetCategoryById implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) GetCategoryById(id string, ctx context.Context) (*entity.Category, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("id", id), func() (*entity.Category, error) {
+		return c.repo.GetCategoryById(id, ctx)
+	})
+}
+
+// CreateCategory implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) CreateCategory(category entity.Category, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.CreateCategory(category, ctx))
+}
+
+// UpdateCategory implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) UpdateCategory(category entity.Category, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.UpdateCategory(category, ctx))
+}
+
+// RemoveCategory implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) RemoveCategory(id string, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.RemoveCategory(id, ctx))
+}
+
+// ActivateCategory implements dataaccess.ICategoryRepo.
+func (c *cachedCategoryRepo) ActivateCategory(id string, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.ActivateCategory(id, ctx))
+}
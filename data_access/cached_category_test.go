@@ -0,0 +1,117 @@
+package dataaccess
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sonit_server/data_access/cache"
+	entity "sonit_server/model/entity"
+	"testing"
+	"time"
+)
+
+// Category repo counting reads, writes fail with err
+type fakeCategoryRepo struct {
+	reads int
+	name  string
+	err   error
+}
+
+func (f *fakeCategoryRepo) GetAllCategories(ctx context.Context) (*[]entity.Category, error) {
+	f.reads++
+	return &[]entity.Category{{CategoryId: "cate1", CategoryName: f.name}}, nil
+}
+
+func (f *fakeCategoryRepo) GetCategoriesByName(name string, ctx context.Context) (*[]entity.Category, error) {
+	f.reads++
+	return &[]entity.Category{}, nil
+}
+
+func (f *fakeCategoryRepo) GetCategoriesByStatus(status bool, ctx context.Context) (*[]entity.Category, error) {
+	f.reads++
+	return &[]entity.Category{}, nil
+}
+
+func (f *fakeCategoryRepo) GetCategoryById(id string, ctx context.Context) (*entity.Category, error) {
+	f.reads++
+	return &entity.Category{CategoryId: id, CategoryName: f.name}, nil
+}
+
+func (f *fakeCategoryRepo) CreateCategory(category entity.Category, ctx context.Context) error {
+	return f.err
+}
+
+func (f *fakeCategoryRepo) UpdateCategory(category entity.Category, ctx context.Context) error {
+	return f.err
+}
+
+func (f *fakeCategoryRepo) RemoveCategory(id string, ctx context.Context) error {
+	return f.err
+}
+
+func (f *fakeCategoryRepo) ActivateCategory(id string, ctx context.Context) error {
+	return f.err
+}
+
+func newTestCachedCategoryRepo(repo *fakeCategoryRepo) *cachedCategoryRepo {
+	return &cachedCategoryRepo{
+		repo: repo,
+		ns:   cache.NewNamespace(entity.GetCategoryTable(), cache.InitializeMemoryStore(), time.Minute, log.New(io.Discard, "", 0)),
+	}
+}
+
+func TestCachedCategoryRepoInvalidatesOnWrite(t *testing.T) {
+	var ctx = context.Background()
+	var writes = []struct {
+		name  string
+		write func(c *cachedCategoryRepo) error
+	}{
+		{"create", func(c *cachedCategoryRepo) error { return c.CreateCategory(entity.Category{CategoryId: "cate2"}, ctx) }},
+		{"update", func(c *cachedCategoryRepo) error { return c.UpdateCategory(entity.Category{CategoryId: "cate1"}, ctx) }},
+		{"remove", func(c *cachedCategoryRepo) error { return c.RemoveCategory("cate1", ctx) }},
+		{"activate", func(c *cachedCategoryRepo) error { return c.ActivateCategory("cate1", ctx) }},
+	}
+
+	for _, tt := range writes {
+		t.Run(tt.name, func(t *testing.T) {
+			var repo = &fakeCategoryRepo{name: "old"}
+			var cached = newTestCachedCategoryRepo(repo)
+
+			cached.GetAllCategories(ctx)
+			cached.GetCategoryById("cate1", ctx)
+
+			repo.name = "new"
+			if err := tt.write(cached); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			all, _ := cached.GetAllCategories(ctx)
+			category, _ := cached.GetCategoryById("cate1", ctx)
+			if (*all)[0].CategoryName != "new" || category.CategoryName != "new" {
+				t.Fatalf("stale reads after %s: %q and %q", tt.name, (*all)[0].CategoryName, category.CategoryName)
+			}
+
+			if repo.reads != 4 {
+				t.Fatalf("source read %d times, want 4", repo.reads)
+			}
+		})
+	}
+}
+
+func TestCachedCategoryRepoKeepsCacheOnFailedWrite(t *testing.T) {
+	var ctx = context.Background()
+	var repo = &fakeCategoryRepo{name: "old", err: errors.New("write failed")}
+	var cached = newTestCachedCategoryRepo(repo)
+
+	cached.GetCategoryById("cate1", ctx)
+
+	if err := cached.UpdateCategory(entity.Category{CategoryId: "cate1"}, ctx); err != repo.err {
+		t.Fatalf("got error %v, want %v", err, repo.err)
+	}
+
+	cached.GetCategoryById("cate1", ctx)
+	if repo.reads != 1 {
+		t.Fatalf("source read %d times, want 1", repo.reads)
+	}
+}
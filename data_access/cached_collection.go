@@ -0,0 +1,80 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sonit_server/data_access/cache"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils/config"
+	"strconv"
+)
+
+// Read-through cache decorator of collection repository
+type cachedCollectionRepo struct {
+	repo data_access.ICollectionRepo
+	ns   *cache.Namespace
+}
+
+func InitializeCachedCollectionRepo(db *sql.DB, logger *log.Logger) data_access.ICollectionRepo {
+	var repo = InitializeCollectionRepo(db, logger)
+
+	var ns = newCacheNamespace(entity.GetCollectionTable(), config.Get().Cache.CatalogTTL, logger)
+	if ns == nil {
+		return repo
+	}
+
+	return &cachedCollectionRepo{
+		repo: repo,
+		ns:   ns,
+	}
+}
+
+// GetAllCollections implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) GetAllCollections(ctx context.Context) (*[]entity.Collection, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("all"), func() (*[]entity.Collection, error) {
+		return c.repo.GetAllCollections(ctx)
+	})
+}
+
+// GetCollectionsByName implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) GetCollectionsByName(name string, ctx context.Context) (*[]entity.Collection, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("name", name), func() (*[]entity.Collection, error) {
+		return c.repo.GetCollectionsByName(name, ctx)
+	})
+}
+
+// GetCollectionsByStatus implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) GetCollectionsByStatus(status bool, ctx context.Context) (*[]entity.Collection, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("status", strconv.FormatBool(status)), func() (*[]entity.Collection, error) {
+		return c.repo.GetCollectionsByStatus(status, ctx)
+	})
+}
+
+// GetCollectionById implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) GetCollectionById(id string, ctx context.Context) (*entity.Collection, error) {
+	return cache.ReadThrough(c.ns, c.ns.Key("id", id), func() (*entity.Collection, error) {
+		return c.repo.GetCollectionById(id, ctx)
+	})
+}
+
+// CreateCollection implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) CreateCollection(collection entity.Collection, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.CreateCollection(collection, ctx))
+}
+
+// UpdateCollection implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) UpdateCollection(collection entity.Collection, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.UpdateCollection(collection, ctx))
+}
+
+// RemoveCollection implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) RemoveCollection(id string, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.RemoveCollection(id, ctx))
+}
+
+// ActivateCollection implements dataaccess.ICollectionRepo.
+func (c *cachedCollectionRepo) ActivateCollection(id string, ctx context.Context) error {
+	return invalidateOnSuccess(c.ns, c.repo.ActivateCollection(id, ctx))
+}
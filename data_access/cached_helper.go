@@ -0,0 +1,53 @@
+package dataaccess
+
+import (
+	"encoding/json"
+	"log"
+	"sonit_server/data_access/cache"
+	"strconv"
+	"time"
+)
+
+// Cached page of records with the total pages
+type cachedPage[T any] struct {
+	Data  *[]T `json:"data"`
+	Pages int  `json:"pages"`
+}
+
+// Build cache namespace for a table, nil if caching is disabled by the store or a zero TTL
+func newCacheNamespace(name string, ttl time.Duration, logger *log.Logger) *cache.Namespace {
+	var store = cache.GetCacheStore(logger)
+	if store == nil || ttl <= 0 {
+		return nil
+	}
+
+	return cache.NewNamespace(name, store, ttl, logger)
+}
+
+// Read paginated records through cache
+func readPageThrough[T any](ns *cache.Namespace, key string, load func() (*[]T, int, error)) (*[]T, int, error) {
+	res, err := cache.ReadThrough(ns, key, func() (cachedPage[T], error) {
+		data, pages, err := load()
+		return cachedPage[T]{Data: data, Pages: pages}, err
+	})
+
+	return res.Data, res.Pages, err
+}
+
+// Invalidate namespace if the write succeeded
+func invalidateOnSuccess(ns *cache.Namespace, err error) error {
+	if err == nil {
+		ns.Invalidate()
+	}
+
+	return err
+}
+
+func toCacheKey(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func pageKey(pageNumber int) string {
+	return strconv.Itoa(pageNumber)
+}
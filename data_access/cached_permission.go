@@ -0,0 +1,57 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sonit_server/data_access/cache"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils/config"
+)
+
+// Read-through cache decorator of permission repository, permission sets are read on every staff request.
+// It shares the roles namespace so any role change also invalidates cached permission sets
+type cachedPermissionRepo struct {
+	repo data_access.IPermissionRepo
+	ns   *cache.Namespace
+}
+
+func InitializeCachedPermissionRepo(db *sql.DB, logger *log.Logger) data_access.IPermissionRepo {
+	var repo = InitializePermissionRepo(db, logger)
+
+	var ns = newCacheNamespace(entity.GetRoleTable(), config.Get().Cache.RoleTTL, logger)
+	if ns == nil {
+		return repo
+	}
+
+	return &cachedPermissionRepo{
+		repo: repo,
+		ns:   ns,
+	}
+}
+
+// GetAllPermissions implements dataaccess.IPermissionRepo.
+func (p *cachedPermissionRepo) GetAllPermissions(ctx context.Context) (*[]entity.Permission, error) {
+	return cache.ReadThrough(p.ns, p.ns.Key("permissions"), func() (*[]entity.Permission, error) {
+		return p.repo.GetAllPermissions(ctx)
+	})
+}
+
+// GetPermissionsByRole implements dataaccess.IPermissionRepo.
+func (p *cachedPermissionRepo) GetPermissionsByRole(roleId string, ctx context.Context) (*[]string, error) {
+	return cache.ReadThrough(p.ns, p.ns.Key("permissions", roleId), func() (*[]string, error) {
+		return p.repo.GetPermissionsByRole(roleId, ctx)
+	})
+}
+
+// SetRolePermissions implements dataaccess.IPermissionRepo.
+func (p *cachedPermissionRepo) SetRolePermissions(roleId string, permissionIds []string, ctx context.Context) error {
+	return invalidateOnSuccess(p.ns, p.repo.SetRolePermissions(roleId, permissionIds, ctx))
+}
+
+// GrantAllPermissions implements dataaccess.IPermissionRepo.
+func (p *cachedPermissionRepo) GrantAllPermissions(roleId string, ctx context.Context) (int, error) {
+	res, err := p.repo.GrantAllPermissions(roleId, ctx)
+	return res, invalidateOnSuccess(p.ns, err)
+}
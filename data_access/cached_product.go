@@ -0,0 +1,124 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sonit_server/data_access/cache"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils/config"
+	"strconv"
+)
+
+// Read-through cache decorator of product repository
+type cachedProductRepo struct {
+	repo data_access.IProductRepo
+	ns   *cache.Namespace
+}
+
+func InitializeCachedProductRepo(db *sql.DB, logger *log.Logger) data_access.IProductRepo {
+	var repo = InitializeProductRepo(db, logger)
+
+	var ns = newCacheNamespace(entity.GetProductTable(), config.Get().Cache.CatalogTTL, logger)
+	if ns == nil {
+		return repo
+	}
+
+	return &cachedProductRepo{
+		repo: repo,
+		ns:   ns,
+	}
+}
+
+// GetAllProducts implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetAllProducts(pageNumber int, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("all", pageKey(pageNumber)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetAllProducts(pageNumber, ctx)
+	})
+}
+
+// GetProductsCustomerUI implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsCustomerUI(req request.GetProductsCustomerUI, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("customer-ui", toCacheKey(req)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsCustomerUI(req, ctx)
+	})
+}
+
+// GetProductsByCategory implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsByCategory(pageNumber int, id string, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("category", id, pageKey(pageNumber)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsByCategory(pageNumber, id, ctx)
+	})
+}
+
+// GetProductsByCollection implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsByCollection(pageNumber int, id string, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("collection", id, pageKey(pageNumber)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsByCollection(pageNumber, id, ctx)
+	})
+}
+
+// GetProductsByPriceInterval implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsByPriceInterval(pageNumber int, maxPrice int64, minPrice int64, ctx context.Context) (*[]entity.Product, int, error) {
+	var key = p.ns.Key("price", strconv.FormatInt(minPrice, 10), strconv.FormatInt(maxPrice, 10), pageKey(pageNumber))
+	return readPageThrough(p.ns, key, func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsByPriceInterval(pageNumber, maxPrice, minPrice, ctx)
+	})
+}
+
+// GetProductsByName implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsByName(pageNumber int, name string, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("name", name, pageKey(pageNumber)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsByName(pageNumber, name, ctx)
+	})
+}
+
+// GetProductsByDescription implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsByDescription(pageNumber int, description string, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("description", description, pageKey(pageNumber)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsByDescription(pageNumber, description, ctx)
+	})
+}
+
+// GetProductsByStatus implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsByStatus(pageNumber int, status bool, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("status", strconv.FormatBool(status), pageKey(pageNumber)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsByStatus(pageNumber, status, ctx)
+	})
+}
+
+// GetProductsByKeyword implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductsByKeyword(pageNumber int, keyword string, ctx context.Context) (*[]entity.Product, int, error) {
+	return readPageThrough(p.ns, p.ns.Key("keyword", keyword, pageKey(pageNumber)), func() (*[]entity.Product, int, error) {
+		return p.repo.GetProductsByKeyword(pageNumber, keyword, ctx)
+	})
+}
+
+// GetProductById implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) GetProductById(id string, ctx context.Context) (*entity.Product, error) {
+	return cache.ReadThrough(p.ns, p.ns.Key("id", id), func() (*entity.Product, error) {
+		return p.repo.GetProductById(id, ctx)
+	})
+}
+
+// CreateProduct implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) CreateProduct(product entity.Product, ctx context.Context) error {
+	return invalidateOnSuccess(p.ns, p.repo.CreateProduct(product, ctx))
+}
+
+// UpdateProduct implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) UpdateProduct(product entity.Product, ctx context.Context) error {
+	return invalidateOnSuccess(p.ns, p.repo.UpdateProduct(product, ctx))
+}
+
+// RemoveProduct implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) RemoveProduct(id string, ctx context.Context) error {
+	return invalidateOnSuccess(p.ns, p.repo.RemoveProduct(id, ctx))
+}
+
+// ActivateProduct implements dataaccess.IProductRepo.
+func (p *cachedProductRepo) ActivateProduct(id string, ctx context.Context) error {
+	return invalidateOnSuccess(p.ns, p.repo.ActivateProduct(id, ctx))
+}
@@ -0,0 +1,80 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sonit_server/data_access/cache"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils/config"
+	"strconv"
+)
+
+// Read-through cache decorator of role repository, writes also invalidate cached permission sets of roles
+type cachedRoleRepo struct {
+	repo data_access.IRoleRepo
+	ns   *cache.Namespace
+}
+
+func InitializeCachedRoleRepo(db *sql.DB, logger *log.Logger) data_access.IRoleRepo {
+	var repo = InitializeRoleRepo(db, logger)
+
+	var ns = newCacheNamespace(entity.GetRoleTable(), config.Get().Cache.RoleTTL, logger)
+	if ns == nil {
+		return repo
+	}
+
+	return &cachedRoleRepo{
+		repo: repo,
+		ns:   ns,
+	}
+}
+
+// GetAllRoles implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) GetAllRoles(ctx context.Context) (*[]entity.Role, error) {
+	return cache.ReadThrough(r.ns, r.ns.Key("all"), func() (*[]entity.Role, error) {
+		return r.repo.GetAllRoles(ctx)
+	})
+}
+
+// GetRolesByName implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) GetRolesByName(name string, ctx context.Context) (*[]entity.Role, error) {
+	return cache.ReadThrough(r.ns, r.ns.Key("name", name), func() (*[]entity.Role, error) {
+		return r.repo.GetRolesByName(name, ctx)
+	})
+}
+
+// GetRolesByStatus implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) GetRolesByStatus(status bool, ctx context.Context) (*[]entity.Role, error) {
+	return cache.ReadThrough(r.ns, r.ns.Key("status", strconv.FormatBool(status)), func() (*[]entity.Role, error) {
+		return r.repo.GetRolesByStatus(status, ctx)
+	})
+}
+
+// GetRoleById implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) GetRoleById(id string, ctx context.Context) (*entity.Role, error) {
+	return cache.ReadThrough(r.ns, r.ns.Key("id", id), func() (*entity.Role, error) {
+		return r.repo.GetRoleById(id, ctx)
+	})
+}
+
+// CreateRole implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) CreateRole(role entity.Role, ctx context.Context) error {
+	return invalidateOnSuccess(r.ns, r.repo.CreateRole(role, ctx))
+}
+
+// UpdateRole implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) UpdateRole(role entity.Role, ctx context.Context) error {
+	return invalidateOnSuccess(r.ns, r.repo.UpdateRole(role, ctx))
+}
+
+// RemoveRole implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) RemoveRole(id string, ctx context.Context) error {
+	return invalidateOnSuccess(r.ns, r.repo.RemoveRole(id, ctx))
+}
+
+// ActivateRole implements dataaccess.IRoleRepo.
+func (r *cachedRoleRepo) ActivateRole(id string, ctx context.Context) error {
+	return invalidateOnSuccess(r.ns, r.repo.ActivateRole(id, ctx))
+}
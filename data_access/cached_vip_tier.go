@@ -0,0 +1,68 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sonit_server/data_access/cache"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils/config"
+	"strings"
+)
+
+// Read-through cache decorator of VIP tier repository, tiers are read at every checkout
+type cachedVipTierRepo struct {
+	repo data_access.IVipTierRepo
+	ns   *cache.Namespace
+}
+
+func InitializeCachedVipTierRepo(db *sql.DB, logger *log.Logger) data_access.IVipTierRepo {
+	var repo = InitializeVipTierRepo(db, logger)
+
+	var ns = newCacheNamespace(entity.GetVipTierTable(), config.Get().Cache.CatalogTTL, logger)
+	if ns == nil {
+		return repo
+	}
+
+	return &cachedVipTierRepo{
+		repo: repo,
+		ns:   ns,
+	}
+}
+
+// GetVipTiers implements dataaccess.IVipTierRepo.
+func (v *cachedVipTierRepo) GetVipTiers(ctx context.Context) (*[]entity.VipTier, error) {
+	return cache.ReadThrough(v.ns, v.ns.Key("all"), func() (*[]entity.VipTier, error) {
+		return v.repo.GetVipTiers(ctx)
+	})
+}
+
+// GetVipTierById implements dataaccess.IVipTierRepo.
+func (v *cachedVipTierRepo) GetVipTierById(id string, ctx context.Context) (*entity.VipTier, error) {
+	return cache.ReadThrough(v.ns, v.ns.Key("id", id), func() (*entity.VipTier, error) {
+		return v.repo.GetVipTierById(id, ctx)
+	})
+}
+
+// GetVipTierByName implements dataaccess.IVipTierRepo.
+func (v *cachedVipTierRepo) GetVipTierByName(name string, ctx context.Context) (*entity.VipTier, error) {
+	return cache.ReadThrough(v.ns, v.ns.Key("name", strings.ToLower(name)), func() (*entity.VipTier, error) {
+		return v.repo.GetVipTierByName(name, ctx)
+	})
+}
+
+// CreateVipTier implements dataaccess.IVipTierRepo.
+func (v *cachedVipTierRepo) CreateVipTier(tier entity.VipTier, ctx context.Context) error {
+	return invalidateOnSuccess(v.ns, v.repo.CreateVipTier(tier, ctx))
+}
+
+// UpdateVipTier implements dataaccess.IVipTierRepo.
+func (v *cachedVipTierRepo) UpdateVipTier(tier entity.VipTier, ctx context.Context) error {
+	return invalidateOnSuccess(v.ns, v.repo.UpdateVipTier(tier, ctx))
+}
+
+// RemoveVipTier implements dataaccess.IVipTierRepo.
+func (v *cachedVipTierRepo) RemoveVipTier(id string, ctx context.Context) error {
+	return invalidateOnSuccess(v.ns, v.repo.RemoveVipTier(id, ctx))
+}
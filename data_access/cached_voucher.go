@@ -0,0 +1,75 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"log"
+	"sonit_server/data_access/cache"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/entity"
+	"sonit_server/utils/config"
+
+	"golang.org/x/net/context"
+)
+
+// Read-through cache decorator of voucher repository
+type cachedVoucherRepo struct {
+	repo data_access.IVoucherRepo
+	ns   *cache.Namespace
+}
+
+func InitializeCachedVoucherRepo(db *sql.DB, logger *log.Logger) data_access.IVoucherRepo {
+	var repo = InitializeVoucherRepo(db, logger)
+
+	// Voucher amount and expiration change frequently so it is cached shorter than catalog
+	var ns = newCacheNamespace(entity.GetVoucherTable(), config.Get().Cache.VoucherTTL, logger)
+	if ns == nil {
+		return repo
+	}
+
+	return &cachedVoucherRepo{
+		repo: repo,
+		ns:   ns,
+	}
+}
+
+// GetAllVouchers implements dataaccess.IVoucherRepo.
+func (v *cachedVoucherRepo) GetAllVouchers(ctx context.Context) (*[]entity.Voucher, error) {
+	return cache.ReadThrough(v.ns, v.ns.Key("all"), func() (*[]entity.Voucher, error) {
+		return v.repo.GetAllVouchers(ctx)
+	})
+}
+
+// GetAllValidVouchers implements dataaccess.IVoucherRepo.
+// Not cached: validity depends on the current time, a cached list would keep expired vouchers and miss started ones
+func (v *cachedVoucherRepo) GetAllValidVouchers(ctx context.Context) (*[]entity.Voucher, error) {
+	return v.repo.GetAllValidVouchers(ctx)
+}
+
+// GetVoucherByID implements dataaccess.IVoucherRepo.
+func (v *cachedVoucherRepo) GetVoucherByID(id string, ctx context.Context) (*entity.Voucher, error) {
+	return cache.ReadThrough(v.ns, v.ns.Key("id", id), func() (*entity.Voucher, error) {
+		return v.repo.GetVoucherByID(id, ctx)
+	})
+}
+
+// GetVoucherByCode implements dataaccess.IVoucherRepo.
+func (v *cachedVoucherRepo) GetVoucherByCode(code string, ctx context.Context) (*entity.Voucher, error) {
+	return cache.ReadThrough(v.ns, v.ns.Key("code", code), func() (*entity.Voucher, error) {
+		return v.repo.GetVoucherByCode(code, ctx)
+	})
+}
+
+// CreateVoucher implements dataaccess.IVoucherRepo.
+func (v *cachedVoucherRepo) CreateVoucher(voucher entity.Voucher, ctx context.Context) error {
+	return invalidateOnSuccess(v.ns, v.repo.CreateVoucher(voucher, ctx))
+}
+
+// UpdateVoucher implements dataaccess.IVoucherRepo.
+func (v *cachedVoucherRepo) UpdateVoucher(voucher entity.Voucher, ctx context.Context) error {
+	return invalidateOnSuccess(v.ns, v.repo.UpdateVoucher(voucher, ctx))
+}
+
+// RemoveVoucher implements dataaccess.IVoucherRepo.
+func (v *cachedVoucherRepo) RemoveVoucher(id string, ctx context.Context) error {
+	return invalidateOnSuccess(v.ns, v.repo.RemoveVoucher(id, ctx))
+}
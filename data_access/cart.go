@@ -9,6 +9,7 @@ import (
 	"sonit_server/constant/noti"
 	data_access "sonit_server/interface/data_access"
 	entity "sonit_server/model/entity"
+	"time"
 )
 
 type cartRepo struct {
@@ -30,9 +31,9 @@ const (
 // CreateCart implements repo.ICartRepo.
 func (c *cartRepo) CreateCart(cart entity.Cart, ctx context.Context) error {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartTable()) + "CreateCart - "
-	var query string = "INSERT INTO " + entity.GetCartTable() + " (id, items, expired_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)"
+	var query string = "INSERT INTO " + entity.GetCartTable() + " (id, expired_at, created_at, updated_at) VALUES ($1, $2, $3, $4)"
 
-	if _, err := c.db.Exec(query, cart.UserId, cart.Items, cart.ExpiredAt, cart.CreatedAt, cart.UpdatedAt); err != nil {
+	if _, err := c.db.Exec(query, cart.UserId, cart.ExpiredAt, cart.CreatedAt, cart.UpdatedAt); err != nil {
 		c.logger.Println(errLogMsg + err.Error())
 		return errors.New(noti.INTERNALL_ERR_MSG)
 	}
@@ -43,10 +44,10 @@ func (c *cartRepo) CreateCart(cart entity.Cart, ctx context.Context) error {
 // GetCartById implements repo.ICartRepo.
 func (c *cartRepo) GetCart(id string, ctx context.Context) (*entity.Cart, int, error) {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartTable()) + "GetCart - "
-	var query string = "SELECT * FROM " + entity.GetCartTable() + " WHERE id = $1"
+	var query string = "SELECT id, expired_at, checked_out_at, created_at, updated_at FROM " + entity.GetCartTable() + " WHERE id = $1"
 
 	var res entity.Cart
-	if err := c.db.QueryRow(query, id).Scan(&res.UserId, &res.Items, &res.ExpiredAt, &res.CreatedAt, &res.UpdatedAt); err != nil {
+	if err := c.db.QueryRow(query, id).Scan(&res.UserId, &res.ExpiredAt, &res.CheckedOutAt, &res.CreatedAt, &res.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, items_in_cart_limit, nil
 		}
@@ -86,10 +87,10 @@ func (c *cartRepo) RemoveCart(id string, ctx context.Context) error {
 // UpdateCart implements repo.ICartRepo.
 func (c *cartRepo) UpdateCart(cart entity.Cart, ctx context.Context) error {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartTable()) + "UpdateCart - "
-	var query string = "UPDATE " + entity.GetCartTable() + " SET items = $1, expired_at = $2, updated_at = $3 WHERE id = $4"
+	var query string = "UPDATE " + entity.GetCartTable() + " SET expired_at = $1, updated_at = $2 WHERE id = $3"
 	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
 
-	res, err := c.db.Exec(query, cart.Items, cart.ExpiredAt, cart.UpdatedAt, cart.UserId)
+	res, err := c.db.Exec(query, cart.ExpiredAt, cart.UpdatedAt, cart.UserId)
 	if err != nil {
 		c.logger.Println(errLogMsg + err.Error())
 		return INTERNALL_ERR_MSG
@@ -107,3 +108,311 @@ func (c *cartRepo) UpdateCart(cart entity.Cart, ctx context.Context) error {
 
 	return nil
 }
+
+// GetCartItems implements repo.ICartRepo.
+func (c *cartRepo) GetCartItems(cartId string, ctx context.Context) (*[]entity.CartItem, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartItemTable()) + "GetCartItems - "
+	var query string = "SELECT id, cart_id, product_id, quantity, unit_price, created_at, updated_at FROM " + entity.GetCartItemTable() + " WHERE cart_id = $1 ORDER BY created_at"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := c.db.Query(query, cartId)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.CartItem
+	for rows.Next() {
+		var x entity.CartItem
+		if err := rows.Scan(&x.CartItemId, &x.CartId, &x.ProductId, &x.Quantity, &x.UnitPrice, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// AddCartItem implements repo.ICartRepo.
+// Creates the cart if needed and adds the quantity to the product line in one statement, so concurrent adds are never lost.
+// Returns false without change if the line quantity would exceed maxQuantity. Price of an existing line is kept until acknowledged
+func (c *cartRepo) AddCartItem(cart entity.Cart, item entity.CartItem, maxQuantity int, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartItemTable()) + "AddCartItem - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	if item.Quantity > maxQuantity {
+		return false, nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO "+entity.GetCartTable()+" (id, expired_at, created_at, updated_at) VALUES ($1, $2, $3, $4)"+
+		" ON CONFLICT (id) DO UPDATE SET expired_at = EXCLUDED.expired_at, updated_at = EXCLUDED.updated_at",
+		cart.UserId, cart.ExpiredAt, cart.CreatedAt, cart.UpdatedAt); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	var quantity int
+	if err := tx.QueryRow("INSERT INTO "+entity.GetCartItemTable()+" AS ci (id, cart_id, product_id, quantity, unit_price, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)"+
+		" ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = ci.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at"+
+		" WHERE ci.quantity + EXCLUDED.quantity <= $8 RETURNING ci.quantity",
+		item.CartItemId, item.CartId, item.ProductId, item.Quantity, item.UnitPrice, item.CreatedAt, item.UpdatedAt, maxQuantity).Scan(&quantity); err != nil {
+		if err == sql.ErrNoRows { // Line existed and limit exceeded
+			return false, nil
+		}
+
+		c.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	return true, nil
+}
+
+// EditCartItem implements repo.ICartRepo.
+func (c *cartRepo) EditCartItem(cart entity.Cart, item entity.CartItem, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartItemTable()) + "EditCartItem - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE "+entity.GetCartItemTable()+" SET quantity = $1, updated_at = $2 WHERE cart_id = $3 AND product_id = $4",
+		item.Quantity, item.UpdatedAt, item.CartId, item.ProductId)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetCartItemTable()))
+	}
+
+	if _, err := tx.Exec("UPDATE "+entity.GetCartTable()+" SET expired_at = $1, updated_at = $2 WHERE id = $3", cart.ExpiredAt, cart.UpdatedAt, cart.UserId); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
+
+// RemoveCartItem implements repo.ICartRepo.
+func (c *cartRepo) RemoveCartItem(cartId, productId string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartItemTable()) + "RemoveCartItem - "
+	var query string = "DELETE FROM " + entity.GetCartItemTable() + " WHERE cart_id = $1 AND product_id = $2"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := c.db.Exec(query, cartId, productId)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetCartItemTable()))
+	}
+
+	return nil
+}
+
+// DeductCartItems implements repo.ICartRepo.
+// Subtracts purchased quantities from the cart lines, lines reaching zero are removed and so is the cart once empty
+func (c *cartRepo) DeductCartItems(cart entity.Cart, items []entity.CartItem, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartItemTable()) + "DeductCartItems - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	for _, item := range items {
+		res, err := tx.Exec("DELETE FROM "+entity.GetCartItemTable()+" WHERE cart_id = $1 AND product_id = $2 AND quantity <= $3", cart.UserId, item.ProductId, item.Quantity)
+		if err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+
+		if rowsAffected > 0 {
+			continue
+		}
+
+		if _, err := tx.Exec("UPDATE "+entity.GetCartItemTable()+" SET quantity = quantity - $1, updated_at = $2 WHERE cart_id = $3 AND product_id = $4",
+			item.Quantity, cart.UpdatedAt, cart.UserId, item.ProductId); err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+	}
+
+	var statements = []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			"DELETE FROM " + entity.GetCartTable() + " WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM " + entity.GetCartItemTable() + " WHERE cart_id = $1)",
+			[]interface{}{cart.UserId},
+		},
+		{
+			"UPDATE " + entity.GetCartTable() + " SET expired_at = $1, checked_out_at = $2, updated_at = $2 WHERE id = $3",
+			[]interface{}{cart.ExpiredAt, cart.UpdatedAt, cart.UserId},
+		},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
+
+// ReviseCartItems implements repo.ICartRepo.
+// Applies acknowledged changes: quantity of each line is capped by item.Quantity and its price set to item.UnitPrice,
+// lines capped to zero are removed. Capping instead of setting keeps quantities decreased by concurrent requests
+func (c *cartRepo) ReviseCartItems(cart entity.Cart, items []entity.CartItem, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartItemTable()) + "ReviseCartItems - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	for _, item := range items {
+		var query string = "UPDATE " + entity.GetCartItemTable() + " SET quantity = LEAST(quantity, $1), unit_price = $2, updated_at = $3 WHERE cart_id = $4 AND product_id = $5"
+		var args = []interface{}{item.Quantity, item.UnitPrice, cart.UpdatedAt, cart.UserId, item.ProductId}
+		if item.Quantity <= 0 {
+			query = "DELETE FROM " + entity.GetCartItemTable() + " WHERE cart_id = $1 AND product_id = $2"
+			args = []interface{}{cart.UserId, item.ProductId}
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE "+entity.GetCartTable()+" SET expired_at = $1, updated_at = $2 WHERE id = $3", cart.ExpiredAt, cart.UpdatedAt, cart.UserId); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
+
+// GetAbandonedCarts implements repo.ICartRepo.
+// Carts of active users untouched since before, not checked out or reminded since their last change and with items.
+// Users unsubscribed from reminders are skipped
+func (c *cartRepo) GetAbandonedCarts(before time.Time, ctx context.Context) (*[]entity.Cart, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartTable()) + "GetAbandonedCarts - "
+	var query string = "SELECT c.id, c.expired_at, c.checked_out_at, c.created_at, c.updated_at FROM " + entity.GetCartTable() + " c" +
+		" JOIN " + entity.GetUserTable() + " u ON u.id = c.id AND u.is_active AND u.is_activated" +
+		" WHERE c.updated_at <= $1 AND c.expired_at > $2 AND (c.checked_out_at IS NULL OR c.checked_out_at < c.updated_at)" +
+		" AND EXISTS (SELECT 1 FROM " + entity.GetCartItemTable() + " ci WHERE ci.cart_id = c.id)" +
+		" AND NOT EXISTS (SELECT 1 FROM " + entity.GetCartReminderTable() + " r WHERE r.user_id = c.id AND r.cart_updated_at >= c.updated_at)" +
+		" AND NOT EXISTS (SELECT 1 FROM " + entity.GetCartReminderOptOutTable() + " o WHERE o.user_id = c.id)" +
+		" ORDER BY c.updated_at"
+
+	rows, err := c.db.Query(query, before, time.Now())
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	defer rows.Close()
+
+	var res []entity.Cart
+	for rows.Next() {
+		var x entity.Cart
+		if err := rows.Scan(&x.UserId, &x.ExpiredAt, &x.CheckedOutAt, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return nil, errors.New(noti.INTERNALL_ERR_MSG)
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// RemoveExpiredCarts implements repo.ICartRepo.
+func (c *cartRepo) RemoveExpiredCarts(expiredAt time.Time, ctx context.Context) (int, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartTable()) + "RemoveExpiredCarts - "
+	var query string = "DELETE FROM " + entity.GetCartTable() + " WHERE expired_at <= $1"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := c.db.Exec(query, expiredAt)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	return int(rowsAffected), nil
+}
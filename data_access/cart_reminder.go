@@ -0,0 +1,147 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type cartReminderRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeCartReminderRepo(db *sql.DB, logger *log.Logger) data_access.ICartReminderRepo {
+	return &cartReminderRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateCartReminder implements dataaccess.ICartReminderRepo.
+// Returns false if a reminder of the same cart revision existed, sent by a concurrent run
+func (c *cartReminderRepo) CreateCartReminder(reminder entity.CartReminder, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartReminderTable()) + "CreateCartReminder - "
+	var query string = "INSERT INTO " + entity.GetCartReminderTable() + " (id, user_id, cart_updated_at, token_hash, voucher_id, reminded_at)" +
+		" VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (user_id, cart_updated_at) DO NOTHING"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := c.db.Exec(query, reminder.ReminderId, reminder.UserId, reminder.CartUpdatedAt, reminder.TokenHash, reminder.VoucherId, reminder.RemindedAt)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetCartReminderByTokenHash implements dataaccess.ICartReminderRepo.
+func (c *cartReminderRepo) GetCartReminderByTokenHash(tokenHash string, ctx context.Context) (*entity.CartReminder, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartReminderTable()) + "GetCartReminderByTokenHash - "
+	var query string = "SELECT id, user_id, cart_updated_at, token_hash, voucher_id, reminded_at FROM " + entity.GetCartReminderTable() + " WHERE token_hash = $1"
+
+	var res entity.CartReminder
+	if err := c.db.QueryRow(query, tokenHash).Scan(&res.ReminderId, &res.UserId, &res.CartUpdatedAt, &res.TokenHash, &res.VoucherId, &res.RemindedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		c.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// GetCartRemindersByUser implements dataaccess.ICartReminderRepo.
+func (c *cartReminderRepo) GetCartRemindersByUser(userId string, ctx context.Context) (*[]entity.CartReminder, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartReminderTable()) + "GetCartRemindersByUser - "
+	var query string = "SELECT id, user_id, cart_updated_at, token_hash, voucher_id, reminded_at FROM " + entity.GetCartReminderTable() + " WHERE user_id = $1 ORDER BY reminded_at"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := c.db.Query(query, userId)
+	if err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res = []entity.CartReminder{}
+	for rows.Next() {
+		var x entity.CartReminder
+		if err := rows.Scan(&x.ReminderId, &x.UserId, &x.CartUpdatedAt, &x.TokenHash, &x.VoucherId, &x.RemindedAt); err != nil {
+			c.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// RemoveCartReminder implements dataaccess.ICartReminderRepo.
+func (c *cartReminderRepo) RemoveCartReminder(id string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartReminderTable()) + "RemoveCartReminder - "
+	var query string = "DELETE FROM " + entity.GetCartReminderTable() + " WHERE id = $1"
+
+	if _, err := c.db.Exec(query, id); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// IsCartReminderOptedOut implements dataaccess.ICartReminderRepo.
+func (c *cartReminderRepo) IsCartReminderOptedOut(userId string, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartReminderOptOutTable()) + "IsCartReminderOptedOut - "
+	var query string = "SELECT EXISTS (SELECT 1 FROM " + entity.GetCartReminderOptOutTable() + " WHERE user_id = $1)"
+
+	var res bool
+	if err := c.db.QueryRow(query, userId).Scan(&res); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return false, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return res, nil
+}
+
+// OptOutCartReminders implements dataaccess.ICartReminderRepo.
+func (c *cartReminderRepo) OptOutCartReminders(userId string, optedOutAt time.Time, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartReminderOptOutTable()) + "OptOutCartReminders - "
+	var query string = "INSERT INTO " + entity.GetCartReminderOptOutTable() + " (user_id, created_at) VALUES ($1, $2) ON CONFLICT (user_id) DO NOTHING"
+
+	if _, err := c.db.Exec(query, userId, optedOutAt); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// OptInCartReminders implements dataaccess.ICartReminderRepo.
+func (c *cartReminderRepo) OptInCartReminders(userId string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCartReminderOptOutTable()) + "OptInCartReminders - "
+	var query string = "DELETE FROM " + entity.GetCartReminderOptOutTable() + " WHERE user_id = $1"
+
+	if _, err := c.db.Exec(query, userId); err != nil {
+		c.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
@@ -40,7 +40,7 @@ func (c *categoryRepo) ActivateCategory(id string, ctx context.Context) error {
 // CreateCategory implements repo.ICategoryRepo.
 func (c *categoryRepo) CreateCategory(Category entity.Category, ctx context.Context) error {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCategoryTable()) + "CreateCategory - "
-	var query string = "INSERT INTO " + entity.GetCategoryTable() + "(id, Category_name, description, active_status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)"
+	var query string = "INSERT INTO " + entity.GetCategoryTable() + "(id, name, description, active_status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)"
 
 	if _, err := c.db.Exec(query, Category.CategoryId, Category.CategoryName, Category.Description, Category.ActiveStatus, Category.CreatedAt, Category.UpdatedAt); err != nil {
 		c.logger.Println(errLogMsg + err.Error())
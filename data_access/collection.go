@@ -40,7 +40,7 @@ func (c *collectionRepo) ActivateCollection(id string, ctx context.Context) erro
 // CreateCollection implements repo.ICollectionRepo.
 func (c *collectionRepo) CreateCollection(collection entity.Collection, ctx context.Context) error {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetCollectionTable()) + "CreateCollection - "
-	var query string = "INSERT INTO " + entity.GetCollectionTable() + "(id, collection_name, description, active_status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)"
+	var query string = "INSERT INTO " + entity.GetCollectionTable() + "(id, name, description, active_status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)"
 
 	if _, err := c.db.Exec(query, collection.CollectionId, collection.CollectionName, collection.Description, collection.ActiveStatus, collection.CreatedAt, collection.UpdatedAt); err != nil {
 		c.logger.Println(errLogMsg + err.Error())
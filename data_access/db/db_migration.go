@@ -8,6 +8,8 @@ import (
 	db_server "sonit_server/data_access/db_server"
 
 	"github.com/golang-migrate/migrate"
+	_ "github.com/golang-migrate/migrate/database/postgres" // Database driver for migration
+	_ "github.com/golang-migrate/migrate/source/file"       // Read migration scripts from file system
 )
 
 const (
@@ -1,8 +1,8 @@
 package dbserver
 
 import (
-	"os"
 	"sonit_server/constant/env"
+	"sonit_server/utils/config"
 )
 
 type mySQLServer struct{}
@@ -18,5 +18,5 @@ func (m mySQLServer) GetSQLServer() string {
 
 // GetCnnStr implements ISQLServer.
 func (m mySQLServer) GetCnnStr() string {
-	return os.Getenv(env.MYSQL_DB_CNNSTR)
+	return config.Get().Database.MySQLCnnStr
 }
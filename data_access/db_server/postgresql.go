@@ -1,8 +1,8 @@
 package dbserver
 
 import (
-	"os"
 	"sonit_server/constant/env"
+	"sonit_server/utils/config"
 )
 
 type postgreSQL struct{}
@@ -13,7 +13,7 @@ func InitializePostgreSQL() ISQLServer {
 
 // GetCnnStr implements ISQLServer.
 func (p postgreSQL) GetCnnStr() string {
-	return os.Getenv(env.POSTGRE_DB_CNNSTR)
+	return config.Get().Database.PostgreCnnStr
 }
 
 // GetSQLServer implements ISQLServer.
@@ -0,0 +1,275 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type guestCartRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeGuestCartRepo(db *sql.DB, logger *log.Logger) data_access.IGuestCartRepo {
+	return &guestCartRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Stock of the merged product, quantities are capped by it at merge
+var merged_stock_query string = "(SELECT current_quantity FROM " + entity.GetProductInventoryTable() + " WHERE id = ci.product_id)"
+
+// Quantity of a product in both carts for each merge rule
+var merge_quantity_exprs = map[string]string{
+	action_type.CART_MERGE_SUM:   "LEAST(ci.quantity + EXCLUDED.quantity, " + merged_stock_query + ")",
+	action_type.CART_MERGE_MAX:   "LEAST(GREATEST(ci.quantity, EXCLUDED.quantity), " + merged_stock_query + ")",
+	action_type.CART_MERGE_GUEST: "EXCLUDED.quantity",
+	action_type.CART_MERGE_USER:  "LEAST(ci.quantity, " + merged_stock_query + ")",
+}
+
+// GetGuestCart implements dataaccess.IGuestCartRepo.
+func (g *guestCartRepo) GetGuestCart(id string, ctx context.Context) (*entity.GuestCart, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetGuestCartTable()) + "GetGuestCart - "
+	var query string = "SELECT id, expired_at, created_at, updated_at FROM " + entity.GetGuestCartTable() + " WHERE id = $1"
+
+	var res entity.GuestCart
+	if err := g.db.QueryRow(query, id).Scan(&res.CartId, &res.ExpiredAt, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		g.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// GetGuestCartItems implements dataaccess.IGuestCartRepo.
+func (g *guestCartRepo) GetGuestCartItems(cartId string, ctx context.Context) (*[]entity.CartItem, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetGuestCartItemTable()) + "GetGuestCartItems - "
+	var query string = "SELECT id, cart_id, product_id, quantity, unit_price, created_at, updated_at FROM " + entity.GetGuestCartItemTable() + " WHERE cart_id = $1 ORDER BY created_at"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := g.db.Query(query, cartId)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.CartItem
+	for rows.Next() {
+		var x entity.CartItem
+		if err := rows.Scan(&x.CartItemId, &x.CartId, &x.ProductId, &x.Quantity, &x.UnitPrice, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			g.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// AddGuestCartItem implements dataaccess.IGuestCartRepo.
+// Same as cartRepo.AddCartItem, the guest cart is created if needed and its expiration is extended
+func (g *guestCartRepo) AddGuestCartItem(cart entity.GuestCart, item entity.CartItem, maxQuantity int, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetGuestCartItemTable()) + "AddGuestCartItem - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	if item.Quantity > maxQuantity {
+		return false, nil
+	}
+
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO "+entity.GetGuestCartTable()+" (id, expired_at, created_at, updated_at) VALUES ($1, $2, $3, $4)"+
+		" ON CONFLICT (id) DO UPDATE SET expired_at = EXCLUDED.expired_at, updated_at = EXCLUDED.updated_at",
+		cart.CartId, cart.ExpiredAt, cart.CreatedAt, cart.UpdatedAt); err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	var quantity int
+	if err := tx.QueryRow("INSERT INTO "+entity.GetGuestCartItemTable()+" AS ci (id, cart_id, product_id, quantity, unit_price, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)"+
+		" ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = ci.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at"+
+		" WHERE ci.quantity + EXCLUDED.quantity <= $8 RETURNING ci.quantity",
+		item.CartItemId, item.CartId, item.ProductId, item.Quantity, item.UnitPrice, item.CreatedAt, item.UpdatedAt, maxQuantity).Scan(&quantity); err != nil {
+		if err == sql.ErrNoRows { // Line existed and limit exceeded
+			return false, nil
+		}
+
+		g.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	return true, nil
+}
+
+// EditGuestCartItem implements dataaccess.IGuestCartRepo.
+func (g *guestCartRepo) EditGuestCartItem(cart entity.GuestCart, item entity.CartItem, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetGuestCartItemTable()) + "EditGuestCartItem - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE "+entity.GetGuestCartItemTable()+" SET quantity = $1, updated_at = $2 WHERE cart_id = $3 AND product_id = $4",
+		item.Quantity, item.UpdatedAt, item.CartId, item.ProductId)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetGuestCartItemTable()))
+	}
+
+	if _, err := tx.Exec("UPDATE "+entity.GetGuestCartTable()+" SET expired_at = $1, updated_at = $2 WHERE id = $3", cart.ExpiredAt, cart.UpdatedAt, cart.CartId); err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
+
+// RemoveGuestCartItem implements dataaccess.IGuestCartRepo.
+func (g *guestCartRepo) RemoveGuestCartItem(cartId, productId string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetGuestCartItemTable()) + "RemoveGuestCartItem - "
+	var query string = "DELETE FROM " + entity.GetGuestCartItemTable() + " WHERE cart_id = $1 AND product_id = $2"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := g.db.Exec(query, cartId, productId)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetGuestCartItemTable()))
+	}
+
+	return nil
+}
+
+// MergeGuestCart implements dataaccess.IGuestCartRepo.
+// Moves lines of the guest cart into the user cart in one transaction and removes the guest cart.
+// Quantities are capped by current stock and products out of stock are dropped, returns number of merged lines
+func (g *guestCartRepo) MergeGuestCart(cartId string, cart entity.Cart, strategy string, ctx context.Context) (int, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetGuestCartTable()) + "MergeGuestCart - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	quantityExpr, ok := merge_quantity_exprs[strategy]
+	if !ok {
+		quantityExpr = merge_quantity_exprs[action_type.CART_MERGE_SUM]
+	}
+
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO "+entity.GetCartTable()+" (id, expired_at, created_at, updated_at) VALUES ($1, $2, $3, $4)"+
+		" ON CONFLICT (id) DO UPDATE SET expired_at = EXCLUDED.expired_at, updated_at = EXCLUDED.updated_at",
+		cart.UserId, cart.ExpiredAt, cart.CreatedAt, cart.UpdatedAt); err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	res, err := tx.Exec("INSERT INTO "+entity.GetCartItemTable()+" AS ci (id, cart_id, product_id, quantity, unit_price, created_at, updated_at)"+
+		" SELECT gi.id, $1, gi.product_id, LEAST(gi.quantity, pi.current_quantity), gi.unit_price, $2, $2"+
+		" FROM "+entity.GetGuestCartItemTable()+" gi JOIN "+entity.GetProductInventoryTable()+" pi ON pi.id = gi.product_id"+
+		" WHERE gi.cart_id = $3 AND pi.current_quantity > 0"+
+		" ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = "+quantityExpr+", updated_at = EXCLUDED.updated_at",
+		cart.UserId, cart.UpdatedAt, cartId)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	merged, err := res.RowsAffected()
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	if _, err := tx.Exec("DELETE FROM "+entity.GetGuestCartTable()+" WHERE id = $1", cartId); err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	return int(merged), nil
+}
+
+// RemoveExpiredGuestCarts implements dataaccess.IGuestCartRepo.
+func (g *guestCartRepo) RemoveExpiredGuestCarts(expiredAt time.Time, ctx context.Context) (int, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetGuestCartTable()) + "RemoveExpiredGuestCarts - "
+	var query string = "DELETE FROM " + entity.GetGuestCartTable() + " WHERE expired_at <= $1"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := g.db.Exec(query, expiredAt)
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		g.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	return int(rowsAffected), nil
+}
@@ -0,0 +1,69 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type identityRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeIdentityRepo(db *sql.DB, logger *log.Logger) data_access.IIdentityRepo {
+	return &identityRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetIdentity implements dataaccess.IIdentityRepo.
+func (i *identityRepo) GetIdentity(provider, subject string, ctx context.Context) (*entity.Identity, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetIdentityTable()) + "GetIdentity - "
+	var query string = "SELECT id, user_id, provider, subject, email, created_at, last_login_at FROM " + entity.GetIdentityTable() + " WHERE provider = $1 AND subject = $2"
+
+	var res entity.Identity
+	if err := i.db.QueryRow(query, provider, subject).Scan(&res.IdentityId, &res.UserId, &res.Provider, &res.Subject, &res.Email, &res.CreatedAt, &res.LastLoginAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		i.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// CreateIdentity implements dataaccess.IIdentityRepo.
+func (i *identityRepo) CreateIdentity(identity entity.Identity, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetIdentityTable()) + "CreateIdentity - "
+	var query string = "INSERT INTO " + entity.GetIdentityTable() + " (id, user_id, provider, subject, email, created_at, last_login_at) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+
+	if _, err := i.db.Exec(query, identity.IdentityId, identity.UserId, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt, identity.LastLoginAt); err != nil {
+		i.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// UpdateLastLogin implements dataaccess.IIdentityRepo.
+func (i *identityRepo) UpdateLastLogin(id string, loginAt time.Time, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetIdentityTable()) + "UpdateLastLogin - "
+	var query string = "UPDATE " + entity.GetIdentityTable() + " SET last_login_at = $1 WHERE id = $2"
+
+	if _, err := i.db.Exec(query, loginAt, id); err != nil {
+		i.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+)
+
+type lockoutEventRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+const lockout_event_columns string = "id, user_id, action, actor_id, ip_address, locked_until, note, created_at"
+
+func InitializeLockoutEventRepo(db *sql.DB, logger *log.Logger) data_access.ILockoutEventRepo {
+	return &lockoutEventRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateLockoutEvent implements dataaccess.ILockoutEventRepo.
+func (l *lockoutEventRepo) CreateLockoutEvent(event entity.LockoutEvent, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetLockoutEventTable()) + "CreateLockoutEvent - "
+	var query string = "INSERT INTO " + entity.GetLockoutEventTable() + " (" + lockout_event_columns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+
+	if _, err := l.db.Exec(query, event.EventId, event.UserId, event.Action, event.ActorId, event.IpAddress, event.LockedUntil, event.Note, event.CreatedAt); err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// GetLockoutEventsByUser implements dataaccess.ILockoutEventRepo.
+func (l *lockoutEventRepo) GetLockoutEventsByUser(userId string, ctx context.Context) (*[]entity.LockoutEvent, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetLockoutEventTable()) + "GetLockoutEventsByUser - "
+	var query string = "SELECT " + lockout_event_columns + " FROM " + entity.GetLockoutEventTable() + " WHERE user_id = $1 ORDER BY created_at DESC"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := l.db.Query(query, userId)
+	if err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.LockoutEvent
+	for rows.Next() {
+		var x entity.LockoutEvent
+		if err := rows.Scan(&x.EventId, &x.UserId, &x.Action, &x.ActorId, &x.IpAddress, &x.LockedUntil, &x.Note, &x.CreatedAt); err != nil {
+			l.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// LockAccount implements dataaccess.ILockoutEventRepo.
+// Locks the account until the event lock time and records the event in one transaction. The lock only applies while
+// failed logins are at the threshold, so of concurrent requests reaching it only one locks the account and gets true
+func (l *lockoutEventRepo) LockAccount(event entity.LockoutEvent, threshold int, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetLockoutEventTable()) + "LockAccount - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE "+entity.GetUserSecurityTable()+
+		" SET fail_access = 0, lock_count = lock_count + 1, locked_until = $1 WHERE id = $2 AND fail_access >= $3",
+		event.LockedUntil, event.UserId, threshold)
+	if err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	// Locked by another request
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec("INSERT INTO "+entity.GetLockoutEventTable()+" ("+lockout_event_columns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		event.EventId, event.UserId, event.Action, event.ActorId, event.IpAddress, event.LockedUntil, event.Note, event.CreatedAt); err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	return true, nil
+}
+
+// ReleaseLock implements dataaccess.ILockoutEventRepo.
+// Clears a lock which has passed at the event time and records the event in one transaction,
+// nothing is recorded if the lock was already released by another request
+func (l *lockoutEventRepo) ReleaseLock(event entity.LockoutEvent, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetLockoutEventTable()) + "ReleaseLock - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE "+entity.GetUserSecurityTable()+
+		" SET locked_until = NULL, fail_access = 0 WHERE id = $1 AND locked_until <= $2",
+		event.UserId, event.CreatedAt)
+	if err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec("INSERT INTO "+entity.GetLockoutEventTable()+" ("+lockout_event_columns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		event.EventId, event.UserId, event.Action, event.ActorId, event.IpAddress, event.LockedUntil, event.Note, event.CreatedAt); err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		l.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
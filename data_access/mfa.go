@@ -0,0 +1,182 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type mfaRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeMfaRepo(db *sql.DB, logger *log.Logger) data_access.IMfaRepo {
+	return &mfaRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetUserTotp implements dataaccess.IMfaRepo.
+func (m *mfaRepo) GetUserTotp(userId string, ctx context.Context) (*entity.UserTotp, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTotpTable()) + "GetUserTotp - "
+	var query string = "SELECT user_id, secret, is_enabled, last_used_step, created_at, updated_at FROM " + entity.GetUserTotpTable() + " WHERE user_id = $1"
+
+	var res entity.UserTotp
+	if err := m.db.QueryRow(query, userId).Scan(&res.UserId, &res.Secret, &res.IsEnabled, &res.LastUsedStep, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		m.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// SaveUserTotp implements dataaccess.IMfaRepo.
+func (m *mfaRepo) SaveUserTotp(totp entity.UserTotp, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTotpTable()) + "SaveUserTotp - "
+	var query string = "INSERT INTO " + entity.GetUserTotpTable() + " (user_id, secret, is_enabled, last_used_step, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)" +
+		" ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, is_enabled = EXCLUDED.is_enabled, last_used_step = EXCLUDED.last_used_step, updated_at = EXCLUDED.updated_at"
+
+	if _, err := m.db.Exec(query, totp.UserId, totp.Secret, totp.IsEnabled, totp.LastUsedStep, totp.CreatedAt, totp.UpdatedAt); err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// RemoveUserTotp implements dataaccess.IMfaRepo.
+func (m *mfaRepo) RemoveUserTotp(userId string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTotpTable()) + "RemoveUserTotp - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	for _, query := range []string{
+		"DELETE FROM " + entity.GetRecoveryCodeTable() + " WHERE user_id = $1",
+		"DELETE FROM " + entity.GetUserTotpTable() + " WHERE user_id = $1",
+	} {
+		if _, err := m.db.Exec(query, userId); err != nil {
+			m.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+	}
+
+	return nil
+}
+
+// UseTotpStep implements dataaccess.IMfaRepo.
+// Returns false if a code of the same or a later step was already accepted
+func (m *mfaRepo) UseTotpStep(userId string, step int64, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTotpTable()) + "UseTotpStep - "
+	var query string = "UPDATE " + entity.GetUserTotpTable() + " SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := m.db.Exec(query, step, userId)
+	if err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetUnusedRecoveryCodes implements dataaccess.IMfaRepo.
+func (m *mfaRepo) GetUnusedRecoveryCodes(userId string, ctx context.Context) (*[]entity.RecoveryCode, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetRecoveryCodeTable()) + "GetUnusedRecoveryCodes - "
+	var query string = "SELECT id, user_id, code_hash, used_at, created_at FROM " + entity.GetRecoveryCodeTable() + " WHERE user_id = $1 AND used_at IS NULL"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := m.db.Query(query, userId)
+	if err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.RecoveryCode
+	for rows.Next() {
+		var x entity.RecoveryCode
+		if err := rows.Scan(&x.CodeId, &x.UserId, &x.CodeHash, &x.UsedAt, &x.CreatedAt); err != nil {
+			m.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// ReplaceRecoveryCodes implements dataaccess.IMfaRepo.
+// Previous codes are removed in the same transaction so old and new codes are never valid together
+func (m *mfaRepo) ReplaceRecoveryCodes(userId string, codes []entity.RecoveryCode, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetRecoveryCodeTable()) + "ReplaceRecoveryCodes - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM "+entity.GetRecoveryCodeTable()+" WHERE user_id = $1", userId); err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	var query string = "INSERT INTO " + entity.GetRecoveryCodeTable() + " (id, user_id, code_hash, used_at, created_at) VALUES ($1, $2, $3, $4, $5)"
+	for _, code := range codes {
+		if _, err := tx.Exec(query, code.CodeId, code.UserId, code.CodeHash, code.UsedAt, code.CreatedAt); err != nil {
+			m.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
+
+// UseRecoveryCode implements dataaccess.IMfaRepo.
+// Returns false if the code was already used
+func (m *mfaRepo) UseRecoveryCode(id string, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetRecoveryCodeTable()) + "UseRecoveryCode - "
+	var query string = "UPDATE " + entity.GetRecoveryCodeTable() + " SET used_at = $1 WHERE id = $2 AND used_at IS NULL"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := m.db.Exec(query, time.Now(), id)
+	if err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		m.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,86 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+)
+
+type passwordHistoryRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializePasswordHistoryRepo(db *sql.DB, logger *log.Logger) data_access.IPasswordHistoryRepo {
+	return &passwordHistoryRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetRecentPasswordHistories implements dataaccess.IPasswordHistoryRepo.
+func (p *passwordHistoryRepo) GetRecentPasswordHistories(userId string, limit int, ctx context.Context) (*[]entity.PasswordHistory, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetPasswordHistoryTable()) + "GetRecentPasswordHistories - "
+	var query string = "SELECT id, user_id, password_hash, created_at FROM " + entity.GetPasswordHistoryTable() + " WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := p.db.Query(query, userId, limit)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.PasswordHistory
+	for rows.Next() {
+		var x entity.PasswordHistory
+		if err := rows.Scan(&x.HistoryId, &x.UserId, &x.PasswordHash, &x.CreatedAt); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// CreatePasswordHistory implements dataaccess.IPasswordHistoryRepo.
+// Only the newest keep records of the user are retained
+func (p *passwordHistoryRepo) CreatePasswordHistory(history entity.PasswordHistory, keep int, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetPasswordHistoryTable()) + "CreatePasswordHistory - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO "+entity.GetPasswordHistoryTable()+" (id, user_id, password_hash, created_at) VALUES ($1, $2, $3, $4)",
+		history.HistoryId, history.UserId, history.PasswordHash, history.CreatedAt); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if _, err := tx.Exec("DELETE FROM "+entity.GetPasswordHistoryTable()+" WHERE user_id = $1 AND id NOT IN"+
+		" (SELECT id FROM "+entity.GetPasswordHistoryTable()+" WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2)", history.UserId, keep); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type permissionRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializePermissionRepo(db *sql.DB, logger *log.Logger) data_access.IPermissionRepo {
+	return &permissionRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetAllPermissions implements dataaccess.IPermissionRepo.
+func (p *permissionRepo) GetAllPermissions(ctx context.Context) (*[]entity.Permission, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetPermissionTable()) + "GetAllPermissions - "
+	var query string = "SELECT id, description, created_at FROM " + entity.GetPermissionTable() + " ORDER BY id"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := p.db.Query(query)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+	defer rows.Close()
+
+	var res []entity.Permission
+	for rows.Next() {
+		var x entity.Permission
+		if err := rows.Scan(&x.PermissionId, &x.Description, &x.CreatedAt); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// GetPermissionsByRole implements dataaccess.IPermissionRepo.
+// Inactive roles have no permission
+func (p *permissionRepo) GetPermissionsByRole(roleId string, ctx context.Context) (*[]string, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetRolePermissionTable()) + "GetPermissionsByRole - "
+	var query string = "SELECT rp.permission_id FROM " + entity.GetRolePermissionTable() + " rp JOIN " + entity.GetRoleTable() + " r ON r.id = rp.role_id" +
+		" WHERE rp.role_id = $1 AND r.active_status = true ORDER BY rp.permission_id"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := p.db.Query(query, roleId)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+	defer rows.Close()
+
+	var res = []string{}
+	for rows.Next() {
+		var x string
+		if err := rows.Scan(&x); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// SetRolePermissions implements dataaccess.IPermissionRepo.
+// Replace every permission of the role in one transaction
+func (p *permissionRepo) SetRolePermissions(roleId string, permissionIds []string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetRolePermissionTable()) + "SetRolePermissions - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM "+entity.GetRolePermissionTable()+" WHERE role_id = $1", roleId); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	var query string = "INSERT INTO " + entity.GetRolePermissionTable() + " (role_id, permission_id, created_at) VALUES ($1, $2, $3)"
+	var curTime = time.Now()
+	for _, id := range permissionIds {
+		if _, err := tx.Exec(query, roleId, id, curTime); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return INTERNALL_ERR_MSG
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
+
+// GrantAllPermissions implements dataaccess.IPermissionRepo.
+// Add every permission of the catalog the role doesn't hold yet, returns number of added permissions
+func (p *permissionRepo) GrantAllPermissions(roleId string, ctx context.Context) (int, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetRolePermissionTable()) + "GrantAllPermissions - "
+	var query string = "INSERT INTO " + entity.GetRolePermissionTable() + " (role_id, permission_id, created_at)" +
+		" SELECT $1, id, $2 FROM " + entity.GetPermissionTable() +
+		" ON CONFLICT (role_id, permission_id) DO NOTHING"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := p.db.Exec(query, roleId, time.Now())
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	return int(rowsAffected), nil
+}
@@ -349,7 +349,7 @@ func (p *productRepo) CreateProduct(product entity.Product, ctx context.Context)
 	var query string = "INSERT INTO " + entity.GetProductTable() +
 		"(id, category_id, collection_id, name, description, " +
 		"image, size, color, price, currency, " +
-		"active_status, created_at, updated at) " +
+		"active_status, created_at, updated_at) " +
 		"values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)"
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductTable()) + "CreateProduct - "
 
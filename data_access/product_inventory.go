@@ -9,6 +9,9 @@ import (
 	"sonit_server/constant/noti"
 	data_access "sonit_server/interface/data_access"
 	entity "sonit_server/model/entity"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type productInventoryRepo struct {
@@ -23,13 +26,18 @@ func InitializeProductInventoryRepo(db *sql.DB, logger *log.Logger) data_access.
 	}
 }
 
+const product_inventory_columns string = "id, current_quantity, reorder_point, safety_stock, low_stock_at"
+
+// Quantity is low at or below a reorder point, a zero reorder point disables alerts
+const low_stock_condition string = "reorder_point > 0 AND current_quantity <= reorder_point"
+
 // GetProductInventory implements dataaccess.IProductInventtory.
 func (p *productInventoryRepo) GetProductInventory(id string, ctx context.Context) (*entity.ProductInventory, error) {
-	var query string = "SELECT * FROM " + entity.GetProductInventoryTable() + " WHERE id = $1"
+	var query string = "SELECT " + product_inventory_columns + " FROM " + entity.GetProductInventoryTable() + " WHERE id = $1"
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductTable()) + "GetProductInventory - "
 
 	var res entity.ProductInventory
-	if err := p.db.QueryRow(query, id).Scan(&res.ProductId, &res.CurrentQuantity); err != nil {
+	if err := p.db.QueryRow(query, id).Scan(&res.ProductId, &res.CurrentQuantity, &res.ReorderPoint, &res.SafetyStock, &res.LowStockAt); err != nil {
 
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -42,24 +50,83 @@ func (p *productInventoryRepo) GetProductInventory(id string, ctx context.Contex
 	return &res, nil
 }
 
-// UpdateProductInventory implements dataaccess.IProductInventtory.
-func (p *productInventoryRepo) UpdateProductInventory(inventory entity.ProductInventory, ctx context.Context) error {
-	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTable()) + "UpdateProductInventory - "
-	var query string = "UPDATE " + entity.GetProductInventoryTable() + " SET current_quantity = $1 WHERE id = $2"
+// GetLowStockInventories implements dataaccess.IProductInventtory.
+// Lowest quantities first, relative to their reorder point
+func (p *productInventoryRepo) GetLowStockInventories(ctx context.Context) (*[]entity.ProductInventory, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTable()) + "GetLowStockInventories - "
+	var query string = "SELECT " + product_inventory_columns + " FROM " + entity.GetProductInventoryTable() +
+		" WHERE " + low_stock_condition + " ORDER BY current_quantity::numeric / reorder_point, id"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := p.db.Query(query)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.ProductInventory
+	for rows.Next() {
+		var x entity.ProductInventory
+		if err := rows.Scan(&x.ProductId, &x.CurrentQuantity, &x.ReorderPoint, &x.SafetyStock, &x.LowStockAt); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// GetSoldQuantities implements dataaccess.IProductInventtory.
+// Units taken out of stock per product since the given time by ledger entries of the given actions, entries bringing units back are subtracted
+func (p *productInventoryRepo) GetSoldQuantities(since time.Time, actions []string, ctx context.Context) (map[string]int64, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTable()) + "GetSoldQuantities - "
+	var query string = "SELECT product_id, -SUM(quantity_change) FROM " + entity.GetProductInventoryTransactionTable() +
+		" WHERE date >= $1 AND action = ANY($2) GROUP BY product_id"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := p.db.Query(query, since, pq.Array(actions))
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
 
-	res, err := p.db.Exec(query, inventory.CurrentQuantity, inventory.ProductId)
+	var res = map[string]int64{}
+	for rows.Next() {
+		var productId string
+		var quantity int64
+		if err := rows.Scan(&productId, &quantity); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res[productId] = quantity
+	}
 
-	var INTERNALL_ERR_MSGMsg error = errors.New(noti.INTERNALL_ERR_MSG)
+	return res, nil
+}
+
+// UpdateInventoryThreshold implements dataaccess.IProductInventtory.
+func (p *productInventoryRepo) UpdateInventoryThreshold(inventory entity.ProductInventory, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTable()) + "UpdateInventoryThreshold - "
+	var query string = "UPDATE " + entity.GetProductInventoryTable() + " SET reorder_point = $1, safety_stock = $2, low_stock_at = $3 WHERE id = $4"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
 
+	res, err := p.db.Exec(query, inventory.ReorderPoint, inventory.SafetyStock, inventory.LowStockAt, inventory.ProductId)
 	if err != nil {
 		p.logger.Println(errLogMsg + err.Error())
-		return INTERNALL_ERR_MSGMsg
+		return INTERNALL_ERR_MSG
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		p.logger.Println(errLogMsg + err.Error())
-		return INTERNALL_ERR_MSGMsg
+		return INTERNALL_ERR_MSG
 	}
 
 	if rowsAffected == 0 {
@@ -69,10 +136,38 @@ func (p *productInventoryRepo) UpdateProductInventory(inventory entity.ProductIn
 	return nil
 }
 
+// UpdateLowStockAt implements dataaccess.IProductInventtory.
+func (p *productInventoryRepo) UpdateLowStockAt(id string, lowStockAt *time.Time, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTable()) + "UpdateLowStockAt - "
+	var query string = "UPDATE " + entity.GetProductInventoryTable() + " SET low_stock_at = $1 WHERE id = $2"
+
+	if _, err := p.db.Exec(query, lowStockAt, id); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// SyncLowStockInventories implements dataaccess.IProductInventtory.
+// Catch up with quantities changed outside inventory transactions, e.g. by orders
+func (p *productInventoryRepo) SyncLowStockInventories(syncedAt time.Time, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTable()) + "SyncLowStockInventories - "
+	var query string = "UPDATE " + entity.GetProductInventoryTable() + " SET low_stock_at = CASE WHEN " + low_stock_condition + " THEN $1::timestamptz END" +
+		" WHERE (low_stock_at IS NULL) = (" + low_stock_condition + ")"
+
+	if _, err := p.db.Exec(query, syncedAt); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
 // CreateProductInventory implements dataaccess.IProductInventtory.
 func (p *productInventoryRepo) CreateProductInventory(inventory entity.ProductInventory, ctx context.Context) error {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTable()) + "CreateProductInventory - "
-	var query string = "INSERT INTO " + entity.GetRoleTable() + " (id, current_quantity) VALUES ($1, $2)"
+	var query string = "INSERT INTO " + entity.GetProductInventoryTable() + " (id, current_quantity) VALUES ($1, $2)"
 
 	if _, err := p.db.Exec(query, inventory.ProductId, inventory.CurrentQuantity); err != nil {
 		p.logger.Println(errLogMsg + err.Error())
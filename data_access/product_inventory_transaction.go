@@ -24,22 +24,244 @@ func InitializeProductInventoryTransactionRepo(db *sql.DB, logger *log.Logger) d
 	}
 }
 
-// CreateProductInventoryTransaction implements dataaccess.IProductInventoryTransactionRepo.
-func (p *productInventoryTransactionRepo) CreateProductInventoryTransaction(tx entity.ProductInventoryTransaction, ctx context.Context) error {
-	var query string = "INSERT INTO " + entity.GetProductInventoryTransactionTable() +
-		" (id, product_id, amount, action, " +
-		"note, date, created_at, updated at) " +
-		"values ($1, $2, $3, $4, $5, $6, $7, $8)"
-	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "CreateProductInventoryTransaction - "
+const product_inventory_transaction_columns string = "id, product_id, amount, action, note, date, created_at, updated_at, quantity_change, balance, corrects_id, " +
+	"warehouse_id, warehouse_balance, transfer_id, order_id"
 
-	if _, err := p.db.Exec(query, tx.TransactionId, tx.ProductId, tx.Amount, tx.Action,
-		tx.Note, tx.Date, tx.CreatedAt, tx.UpdatedAt); err != nil {
+// AppendProductInventoryTransactions implements dataaccess.IProductInventoryTransactionRepo.
+// Entries are applied in order to their product and warehouse inventories and recorded with the resulting balances in a single transaction.
+// Nothing is recorded if a quantity would drop below zero
+func (p *productInventoryTransactionRepo) AppendProductInventoryTransactions(txs []entity.ProductInventoryTransaction, ctx context.Context) (*[]entity.ProductInventoryTransaction, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "AppendProductInventoryTransactions - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
 
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer dbTx.Rollback()
+
+	var res []entity.ProductInventoryTransaction
+	for _, tx := range txs {
+		recorded, err := p.appendInventoryTransaction(dbTx, tx, errLogMsg)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, recorded)
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	return &res, nil
+}
+
+// AppendStocktakeTransactions implements dataaccess.IProductInventoryTransactionRepo.
+// Entries carry the counted quantity as warehouse balance. Their change is the difference with the warehouse stock, read and
+// locked in the same transaction so entries recorded meanwhile are counted. Counts matching the stock are not recorded
+func (p *productInventoryTransactionRepo) AppendStocktakeTransactions(txs []entity.ProductInventoryTransaction, ctx context.Context) (*[]entity.ProductInventoryTransaction, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "AppendStocktakeTransactions - "
+	var lockQuery string = "SELECT id FROM " + entity.GetProductInventoryTable() + " WHERE id = $1 FOR UPDATE"
+	var createWarehouseQuery string = "INSERT INTO " + entity.GetWarehouseInventoryTable() + " (product_id, warehouse_id, current_quantity)" +
+		" VALUES ($1, $2, 0) ON CONFLICT (product_id, warehouse_id) DO NOTHING"
+	var lockWarehouseQuery string = "SELECT current_quantity FROM " + entity.GetWarehouseInventoryTable() +
+		" WHERE product_id = $1 AND warehouse_id = $2 FOR UPDATE"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer dbTx.Rollback()
+
+	var res []entity.ProductInventoryTransaction
+	for _, tx := range txs {
+		if tx.WarehouseBalance == nil {
+			return nil, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+		}
+
+		// Rows are locked in the order other entries update them, product then warehouse
+		var productId string
+		if err := dbTx.QueryRow(lockQuery, tx.ProductId).Scan(&productId); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetProductInventoryTable()))
+			}
+
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		if _, err := dbTx.Exec(createWarehouseQuery, tx.ProductId, tx.WarehouseId); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		var stock int64
+		if err := dbTx.QueryRow(lockWarehouseQuery, tx.ProductId, tx.WarehouseId).Scan(&stock); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		tx.QuantityChange = *tx.WarehouseBalance - stock
+		if tx.QuantityChange == 0 {
+			continue
+		}
+
+		tx.Amount = max(tx.QuantityChange, -tx.QuantityChange)
+		recorded, err := p.appendInventoryTransaction(dbTx, tx, errLogMsg)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, recorded)
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	return &res, nil
+}
+
+// Apply an entry to its product and warehouse inventories and record it with the resulting balances
+func (p *productInventoryTransactionRepo) appendInventoryTransaction(dbTx *sql.Tx, tx entity.ProductInventoryTransaction, errLogMsg string) (entity.ProductInventoryTransaction, error) {
+	var updateQuery string = "UPDATE " + entity.GetProductInventoryTable() + " SET current_quantity = current_quantity + $1" +
+		" WHERE id = $2 AND current_quantity + $1 >= 0 RETURNING current_quantity"
+	var createWarehouseQuery string = "INSERT INTO " + entity.GetWarehouseInventoryTable() + " (product_id, warehouse_id, current_quantity)" +
+		" VALUES ($1, $2, 0) ON CONFLICT (product_id, warehouse_id) DO NOTHING"
+	var updateWarehouseQuery string = "UPDATE " + entity.GetWarehouseInventoryTable() + " SET current_quantity = current_quantity + $1" +
+		" WHERE product_id = $2 AND warehouse_id = $3 AND current_quantity + $1 >= 0 RETURNING current_quantity"
+	var insertQuery string = "INSERT INTO " + entity.GetProductInventoryTransactionTable() +
+		" (" + product_inventory_transaction_columns + ") " +
+		"values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	var balance int64
+	if err := dbTx.QueryRow(updateQuery, tx.QuantityChange, tx.ProductId).Scan(&balance); err != nil {
+		if err == sql.ErrNoRows { // Inventory missing or not enough
+			return tx, errors.New(noti.INVENTORY_NOT_ENOUGH_WARN_MSG)
+		}
+
+		p.logger.Println(errLogMsg + err.Error())
+		return tx, INTERNALL_ERR_MSG
+	}
+
+	// Stock of the warehouse starts at zero on its first entry
+	if _, err := dbTx.Exec(createWarehouseQuery, tx.ProductId, tx.WarehouseId); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return tx, INTERNALL_ERR_MSG
+	}
+
+	var warehouseBalance int64
+	if err := dbTx.QueryRow(updateWarehouseQuery, tx.QuantityChange, tx.ProductId, tx.WarehouseId).Scan(&warehouseBalance); err != nil {
+		if err == sql.ErrNoRows { // Not enough at the warehouse
+			return tx, errors.New(noti.INVENTORY_NOT_ENOUGH_WARN_MSG)
+		}
+
+		p.logger.Println(errLogMsg + err.Error())
+		return tx, INTERNALL_ERR_MSG
+	}
+
+	tx.Balance = &balance
+	tx.WarehouseBalance = &warehouseBalance
+	if _, err := dbTx.Exec(insertQuery, tx.TransactionId, tx.ProductId, tx.Amount, tx.Action,
+		tx.Note, tx.Date, tx.CreatedAt, tx.UpdatedAt, tx.QuantityChange, tx.Balance, tx.CorrectsId,
+		tx.WarehouseId, tx.WarehouseBalance, tx.TransferId, tx.OrderId); err != nil {
 		p.logger.Println(errLogMsg + err.Error())
-		return errors.New(noti.INTERNALL_ERR_MSG)
+		return tx, INTERNALL_ERR_MSG
 	}
 
-	return nil
+	return tx, nil
+}
+
+// IsProductInventoryTransactionCorrected implements dataaccess.IProductInventoryTransactionRepo.
+func (p *productInventoryTransactionRepo) IsProductInventoryTransactionCorrected(id string, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "IsProductInventoryTransactionCorrected - "
+	var query string = "SELECT EXISTS (SELECT 1 FROM " + entity.GetProductInventoryTransactionTable() + " WHERE corrects_id = $1)"
+
+	var res bool
+	if err := p.db.QueryRow(query, id).Scan(&res); err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return false, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return res, nil
+}
+
+// GetInventoryDrifts implements dataaccess.IProductInventoryTransactionRepo.
+// Current quantities of products and of their warehouses are compared with the sum of their ledger entries
+func (p *productInventoryTransactionRepo) GetInventoryDrifts(ctx context.Context) (*[]entity.InventoryDrift, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "GetInventoryDrifts - "
+	var query string = "SELECT i.id, '', i.current_quantity, COALESCE(l.total, 0) FROM " + entity.GetProductInventoryTable() + " i" +
+		" LEFT JOIN (SELECT product_id, SUM(quantity_change) AS total FROM " + entity.GetProductInventoryTransactionTable() + " GROUP BY product_id) l" +
+		" ON l.product_id = i.id WHERE i.current_quantity <> COALESCE(l.total, 0)" +
+		" UNION ALL" +
+		" SELECT COALESCE(w.product_id, l.product_id), COALESCE(w.warehouse_id, l.warehouse_id), COALESCE(w.current_quantity, 0), COALESCE(l.total, 0) FROM " + entity.GetWarehouseInventoryTable() + " w" +
+		" FULL JOIN (SELECT product_id, warehouse_id, SUM(quantity_change) AS total FROM " + entity.GetProductInventoryTransactionTable() + " GROUP BY product_id, warehouse_id) l" +
+		" ON l.product_id = w.product_id AND l.warehouse_id = w.warehouse_id WHERE COALESCE(w.current_quantity, 0) <> COALESCE(l.total, 0)" +
+		" ORDER BY 1, 2"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := p.db.Query(query)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.InventoryDrift
+	for rows.Next() {
+		var x entity.InventoryDrift
+		if err := rows.Scan(&x.ProductId, &x.WarehouseId, &x.CurrentQuantity, &x.LedgerQuantity); err != nil {
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// GetOrderInventoryTransactions implements dataaccess.IProductInventoryTransactionRepo.
+func (p *productInventoryTransactionRepo) GetOrderInventoryTransactions(orderId string, ctx context.Context) (*[]entity.ProductInventoryTransaction, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "GetOrderInventoryTransactions - "
+	var query string = "SELECT " + product_inventory_transaction_columns + " FROM " + entity.GetProductInventoryTransactionTable() +
+		" WHERE order_id = $1 ORDER BY created_at"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := p.db.Query(query, orderId)
+	if err != nil {
+		p.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.ProductInventoryTransaction
+	for rows.Next() {
+		var x entity.ProductInventoryTransaction
+		if err := rows.Scan(
+			&x.TransactionId, &x.ProductId, &x.Amount, &x.Action,
+			&x.Note, &x.Date, &x.CreatedAt, &x.UpdatedAt, &x.QuantityChange, &x.Balance, &x.CorrectsId,
+			&x.WarehouseId, &x.WarehouseBalance, &x.TransferId, &x.OrderId); err != nil {
+
+			p.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
 }
 
 const (
@@ -51,24 +273,42 @@ func (p *productInventoryTransactionRepo) GetAllProductInventoryTransactions(req
 	panic("unimplemented")
 }
 
+// GetInventoryTransactionsByProduct implements dataaccess.IProductInventoryTransactionRepo.
+// Action and warehouse filters are optional
 func (p *productInventoryTransactionRepo) GetInventoryTransactionsByProduct(req request.GetProductInventoryTrasactionsByProductRequest, ctx context.Context) (*[]entity.ProductInventoryTransaction, int, error) {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "GetInventoryTransactionsByProduct - "
-	var queryCondition string = fmt.Sprintf(` WHERE action = '%s' AND product_id = '%s'`, req.InventoryTransaction.Action, req.ProductId)
+	var queryCondition string = " WHERE product_id = $1"
+	var args = []interface{}{req.ProductId}
+
+	if req.InventoryTransaction.Action != "" {
+		args = append(args, req.InventoryTransaction.Action)
+		queryCondition += fmt.Sprintf(" AND action = $%d", len(args))
+	}
+
+	if req.InventoryTransaction.WarehouseId != "" {
+		args = append(args, req.InventoryTransaction.WarehouseId)
+		queryCondition += fmt.Sprintf(" AND warehouse_id = $%d", len(args))
+	}
+
 	var orderCondition string = generateOrderCondition(req.InventoryTransaction.Pagination.FilterProp, req.InventoryTransaction.Pagination.Order)
-	var query string = generateRetrieveQuery(entity.GetProductInventoryTransactionTable(), queryCondition+orderCondition, product_record_limit, req.InventoryTransaction.Pagination.PageNumber, false)
+	var query string = fmt.Sprintf("SELECT "+product_inventory_transaction_columns+" FROM "+entity.GetProductInventoryTransactionTable()+"%s LIMIT %d OFFSET %d",
+		queryCondition+orderCondition, product_inventory_transaction_records_limit, getOffSetAmount(product_inventory_transaction_records_limit, req.InventoryTransaction.Pagination.PageNumber))
 
-	rows, err := p.db.Query(query)
+	rows, err := p.db.Query(query, args...)
 	if err != nil {
 		p.logger.Println(errLogMsg + err.Error())
 		return nil, 0, errors.New(noti.INTERNALL_ERR_MSG)
 	}
 
+	defer rows.Close()
+
 	var res []entity.ProductInventoryTransaction
 	for rows.Next() {
 		var x entity.ProductInventoryTransaction
 		if err := rows.Scan(
 			&x.TransactionId, &x.ProductId, &x.Amount, &x.Action,
-			&x.Note, &x.Date, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			&x.Note, &x.Date, &x.CreatedAt, &x.UpdatedAt, &x.QuantityChange, &x.Balance, &x.CorrectsId,
+			&x.WarehouseId, &x.WarehouseBalance, &x.TransferId, &x.OrderId); err != nil {
 
 			p.logger.Println(errLogMsg + err.Error())
 			return nil, 0, errors.New(noti.INTERNALL_ERR_MSG)
@@ -79,20 +319,21 @@ func (p *productInventoryTransactionRepo) GetInventoryTransactionsByProduct(req
 
 	// Track total records in table
 	var totalRecords int
-	p.db.QueryRow(generateRetrieveQuery(entity.GetProductTable(), queryCondition, product_inventory_transaction_records_limit, req.InventoryTransaction.Pagination.PageNumber, true)).Scan(&totalRecords)
+	p.db.QueryRow(generateCountTotalRecordsQuery(entity.GetProductInventoryTransactionTable(), queryCondition), args...).Scan(&totalRecords)
 
-	return &res, caculateTotalPages(totalRecords, product_record_limit), nil
+	return &res, caculateTotalPages(totalRecords, product_inventory_transaction_records_limit), nil
 }
 
 // GetProductInventoryTransaction implements dataaccess.IProductInventoryTransactionRepo.
 func (p *productInventoryTransactionRepo) GetProductInventoryTransaction(id string, ctx context.Context) (*entity.ProductInventoryTransaction, error) {
-	var query string = "SELECT * FROM " + entity.GetProductInventoryTransactionTable() + " WHERE id = $1"
+	var query string = "SELECT " + product_inventory_transaction_columns + " FROM " + entity.GetProductInventoryTransactionTable() + " WHERE id = $1"
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "GetProductInventoryTransaction - "
 
 	var res entity.ProductInventoryTransaction
 	if err := p.db.QueryRow(query, id).Scan(
 		&res.TransactionId, &res.ProductId, &res.Amount, &res.Action,
-		&res.Note, &res.Date, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		&res.Note, &res.Date, &res.CreatedAt, &res.UpdatedAt, &res.QuantityChange, &res.Balance, &res.CorrectsId,
+		&res.WarehouseId, &res.WarehouseBalance, &res.TransferId, &res.OrderId); err != nil {
 
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -104,31 +345,3 @@ func (p *productInventoryTransactionRepo) GetProductInventoryTransaction(id stri
 
 	return &res, nil
 }
-
-// UpdateProductInventoryTransaction implements dataaccess.IProductInventoryTransactionRepo.
-func (p *productInventoryTransactionRepo) UpdateProductInventoryTransaction(tx entity.ProductInventoryTransaction, ctx context.Context) error {
-	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetProductInventoryTransactionTable()) + "UpdateProductInventoryTransaction - "
-	var query string = "UPDATE " + entity.GetProductInventoryTransactionTable() + " SET product_id = $1, amount = $2, action = $3, note = $4, date = $5, " +
-		"updated_at = $6 WHERE id = $7"
-
-	res, err := p.db.Exec(query, tx.ProductId, tx.Amount, tx.Action, tx.Note, tx.Date, tx.UpdatedAt, tx.TransactionId)
-
-	var INTERNALL_ERR_MSGMsg error = errors.New(noti.INTERNALL_ERR_MSG)
-
-	if err != nil {
-		p.logger.Println(errLogMsg + err.Error())
-		return INTERNALL_ERR_MSGMsg
-	}
-
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		p.logger.Println(errLogMsg + err.Error())
-		return INTERNALL_ERR_MSGMsg
-	}
-
-	if rowsAffected == 0 {
-		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetProductTable()))
-	}
-
-	return nil
-}
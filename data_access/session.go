@@ -0,0 +1,152 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+)
+
+type sessionRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeSessionRepo(db *sql.DB, logger *log.Logger) data_access.ISessionRepo {
+	return &sessionRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const session_columns string = "id, user_id, refresh_token_hash, access_token_id, access_expired_at, device_name, ip_address, user_agent, is_revoked, created_at, last_used_at, expired_at"
+
+// CreateSession implements dataaccess.ISessionRepo.
+func (s *sessionRepo) CreateSession(session entity.Session, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetSessionTable()) + "CreateSession - "
+	var query string = "INSERT INTO " + entity.GetSessionTable() + " (" + session_columns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)"
+
+	if _, err := s.db.Exec(query, session.SessionId, session.UserId, session.RefreshTokenHash, session.AccessTokenId, session.AccessExpiredAt,
+		session.DeviceName, session.IpAddress, session.UserAgent, session.IsRevoked, session.CreatedAt, session.LastUsedAt, session.ExpiredAt); err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// GetSession implements dataaccess.ISessionRepo.
+func (s *sessionRepo) GetSession(id string, ctx context.Context) (*entity.Session, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetSessionTable()) + "GetSession - "
+	var query string = "SELECT " + session_columns + " FROM " + entity.GetSessionTable() + " WHERE id = $1"
+
+	var res entity.Session
+	if err := s.db.QueryRow(query, id).Scan(&res.SessionId, &res.UserId, &res.RefreshTokenHash, &res.AccessTokenId, &res.AccessExpiredAt,
+		&res.DeviceName, &res.IpAddress, &res.UserAgent, &res.IsRevoked, &res.CreatedAt, &res.LastUsedAt, &res.ExpiredAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		s.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// GetActiveSessionsByUser implements dataaccess.ISessionRepo.
+func (s *sessionRepo) GetActiveSessionsByUser(userId string, ctx context.Context) (*[]entity.Session, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetSessionTable()) + "GetActiveSessionsByUser - "
+	var query string = "SELECT " + session_columns + " FROM " + entity.GetSessionTable() +
+		" WHERE user_id = $1 AND is_revoked = false AND expired_at > CURRENT_TIMESTAMP ORDER BY last_used_at DESC"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := s.db.Query(query, userId)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.Session
+	for rows.Next() {
+		var x entity.Session
+		if err := rows.Scan(&x.SessionId, &x.UserId, &x.RefreshTokenHash, &x.AccessTokenId, &x.AccessExpiredAt,
+			&x.DeviceName, &x.IpAddress, &x.UserAgent, &x.IsRevoked, &x.CreatedAt, &x.LastUsedAt, &x.ExpiredAt); err != nil {
+			s.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// RotateSession implements dataaccess.ISessionRepo.
+// The update only applies if the stored hash is still the previous one, so a refresh token can't be used twice concurrently
+func (s *sessionRepo) RotateSession(session entity.Session, prevTokenHash string, ctx context.Context) (bool, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetSessionTable()) + "RotateSession - "
+	var query string = "UPDATE " + entity.GetSessionTable() +
+		" SET refresh_token_hash = $1, access_token_id = $2, access_expired_at = $3, ip_address = $4, user_agent = $5, last_used_at = $6, expired_at = $7" +
+		" WHERE id = $8 AND refresh_token_hash = $9 AND is_revoked = false"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := s.db.Exec(query, session.RefreshTokenHash, session.AccessTokenId, session.AccessExpiredAt, session.IpAddress,
+		session.UserAgent, session.LastUsedAt, session.ExpiredAt, session.SessionId, prevTokenHash)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return false, INTERNALL_ERR_MSG
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RevokeSession implements dataaccess.ISessionRepo.
+func (s *sessionRepo) RevokeSession(id string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetSessionTable()) + "RevokeSession - "
+	var query string = "UPDATE " + entity.GetSessionTable() + " SET is_revoked = true WHERE id = $1"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := s.db.Exec(query, id)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Session"))
+	}
+
+	return nil
+}
+
+// RevokeSessionsByUser implements dataaccess.ISessionRepo.
+func (s *sessionRepo) RevokeSessionsByUser(userId string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetSessionTable()) + "RevokeSessionsByUser - "
+	var query string = "UPDATE " + entity.GetSessionTable() + " SET is_revoked = true WHERE user_id = $1 AND is_revoked = false"
+
+	if _, err := s.db.Exec(query, userId); err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
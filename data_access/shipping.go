@@ -39,7 +39,20 @@ func (s *shippingRepo) CreateShipping(ship entity.Shipping, ctx context.Context)
 
 // GetShipping implements dataaccess.IShippingRepo.
 func (s *shippingRepo) GetShipping(id string, ctx context.Context) (*entity.Shipping, int, error) {
-	panic("unimplemented")
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetShippingTable()) + "GetShipping - "
+	var query string = "SELECT id, delivery_code, shipping_unit, shipping_detail, delivered_at, created_at, updated_at FROM " + entity.GetShippingTable() + " WHERE id = $1"
+
+	var res entity.Shipping
+	if err := s.db.QueryRow(query, id).Scan(&res.OrderId, &res.DeliveryCode, &res.ShippingUnit, &res.ShippingDetail, &res.DeliveredAt, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, nil
+		}
+
+		s.logger.Println(errLogMsg + err.Error())
+		return nil, 0, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, 1, nil
 }
 
 // GetUserShipping implements dataaccess.IShippingRepo.
@@ -49,7 +62,27 @@ func (s *shippingRepo) GetShippings(req request.GetShippingsRequest, ctx context
 
 // UpdateShipping implements dataaccess.IShippingRepo.
 func (s *shippingRepo) UpdateShipping(ship entity.Shipping, ctx context.Context) error {
-	panic("unimplemented")
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetShippingTable()) + "UpdateShipping - "
+	var query string = "UPDATE " + entity.GetShippingTable() + " SET delivery_code = $1, shipping_unit = $2, shipping_detail = $3, delivered_at = $4, updated_at = $5 WHERE id = $6"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := s.db.Exec(query, ship.DeliveryCode, ship.ShippingUnit, ship.ShippingDetail, ship.DeliveredAt, ship.UpdatedAt, ship.OrderId)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetShippingTable()))
+	}
+
+	return nil
 }
 
 // RemoveShipping implements dataaccess.IShippingRepo.
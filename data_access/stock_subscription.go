@@ -0,0 +1,179 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	domain_status "sonit_server/constant/domain_status"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type stockSubscriptionRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeStockSubscriptionRepo(db *sql.DB, logger *log.Logger) data_access.IStockSubscriptionRepo {
+	return &stockSubscriptionRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const stock_subscription_columns string = "id, user_id, product_id, status, attempts, queued_at, notified_at, created_at, updated_at"
+
+// GetStockSubscriptions implements dataaccess.IStockSubscriptionRepo.
+func (s *stockSubscriptionRepo) GetStockSubscriptions(userId string, ctx context.Context) (*[]entity.StockSubscription, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetStockSubscriptionTable()) + "GetStockSubscriptions - "
+	var query string = "SELECT " + stock_subscription_columns + " FROM " + entity.GetStockSubscriptionTable() + " WHERE user_id = $1 ORDER BY created_at DESC"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := s.db.Query(query, userId)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.StockSubscription
+	for rows.Next() {
+		var x entity.StockSubscription
+		if err := rows.Scan(&x.SubscriptionId, &x.UserId, &x.ProductId, &x.Status, &x.Attempts, &x.QueuedAt, &x.NotifiedAt, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			s.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// SaveStockSubscription implements dataaccess.IStockSubscriptionRepo.
+// Subscribing again to a product restarts the subscription
+func (s *stockSubscriptionRepo) SaveStockSubscription(subscription entity.StockSubscription, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetStockSubscriptionTable()) + "SaveStockSubscription - "
+	var query string = "INSERT INTO " + entity.GetStockSubscriptionTable() + " (" + stock_subscription_columns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)" +
+		" ON CONFLICT (user_id, product_id) DO UPDATE SET status = EXCLUDED.status, attempts = EXCLUDED.attempts, queued_at = EXCLUDED.queued_at," +
+		" notified_at = EXCLUDED.notified_at, updated_at = EXCLUDED.updated_at"
+
+	if _, err := s.db.Exec(query, subscription.SubscriptionId, subscription.UserId, subscription.ProductId, subscription.Status, subscription.Attempts,
+		subscription.QueuedAt, subscription.NotifiedAt, subscription.CreatedAt, subscription.UpdatedAt); err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// RemoveStockSubscription implements dataaccess.IStockSubscriptionRepo.
+func (s *stockSubscriptionRepo) RemoveStockSubscription(userId, productId string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetStockSubscriptionTable()) + "RemoveStockSubscription - "
+	var query string = "DELETE FROM " + entity.GetStockSubscriptionTable() + " WHERE user_id = $1 AND product_id = $2"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := s.db.Exec(query, userId, productId)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetStockSubscriptionTable()))
+	}
+
+	return nil
+}
+
+// QueueStockSubscriptions implements dataaccess.IStockSubscriptionRepo.
+// Waiting subscriptions of a restocked product are queued for mailing
+func (s *stockSubscriptionRepo) QueueStockSubscriptions(productId string, queuedAt time.Time, ctx context.Context) (int, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetStockSubscriptionTable()) + "QueueStockSubscriptions - "
+	var query string = "UPDATE " + entity.GetStockSubscriptionTable() + " SET status = $1, queued_at = $2, updated_at = $2 WHERE product_id = $3 AND status = $4"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := s.db.Exec(query, domain_status.STOCK_SUBSCRIPTION_QUEUED, queuedAt, productId, domain_status.STOCK_SUBSCRIPTION_WAITING)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ClaimQueuedStockSubscriptions implements dataaccess.IStockSubscriptionRepo.
+// Oldest queued subscriptions are marked notified before mailing, rows claimed by a concurrent run are skipped
+func (s *stockSubscriptionRepo) ClaimQueuedStockSubscriptions(limit int, claimedAt time.Time, ctx context.Context) (*[]entity.StockSubscription, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetStockSubscriptionTable()) + "ClaimQueuedStockSubscriptions - "
+	var query string = "UPDATE " + entity.GetStockSubscriptionTable() + " SET status = $1, notified_at = $2, updated_at = $2" +
+		" WHERE id IN (SELECT id FROM " + entity.GetStockSubscriptionTable() + " WHERE status = $3 ORDER BY queued_at, created_at LIMIT $4 FOR UPDATE SKIP LOCKED)" +
+		" RETURNING " + stock_subscription_columns
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := s.db.Query(query, domain_status.STOCK_SUBSCRIPTION_NOTIFIED, claimedAt, domain_status.STOCK_SUBSCRIPTION_QUEUED, limit)
+	if err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.StockSubscription
+	for rows.Next() {
+		var x entity.StockSubscription
+		if err := rows.Scan(&x.SubscriptionId, &x.UserId, &x.ProductId, &x.Status, &x.Attempts, &x.QueuedAt, &x.NotifiedAt, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			s.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// UpdateStockSubscriptionStatus implements dataaccess.IStockSubscriptionRepo.
+func (s *stockSubscriptionRepo) UpdateStockSubscriptionStatus(id, status string, updatedAt time.Time, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetStockSubscriptionTable()) + "UpdateStockSubscriptionStatus - "
+	var query string = "UPDATE " + entity.GetStockSubscriptionTable() + " SET status = $1, notified_at = NULL, updated_at = $2 WHERE id = $3"
+
+	if _, err := s.db.Exec(query, status, updatedAt, id); err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// RequeueStockSubscription implements dataaccess.IStockSubscriptionRepo.
+// A failed mail is retried by a later run until maxAttempts failures
+func (s *stockSubscriptionRepo) RequeueStockSubscription(id string, maxAttempts int, updatedAt time.Time, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetStockSubscriptionTable()) + "RequeueStockSubscription - "
+	var query string = "UPDATE " + entity.GetStockSubscriptionTable() + " SET attempts = attempts + 1," +
+		" status = CASE WHEN attempts + 1 >= $1 THEN $2 ELSE $3 END, notified_at = NULL, updated_at = $4 WHERE id = $5"
+
+	if _, err := s.db.Exec(query, maxAttempts, domain_status.STOCK_SUBSCRIPTION_FAILED, domain_status.STOCK_SUBSCRIPTION_QUEUED, updatedAt, id); err != nil {
+		s.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
@@ -256,6 +256,50 @@ func (u *userRepo) GetUsersByRole(pageNumber int, id string, ctx context.Context
 	return &res, caculateTotalPages(totalRecords, user_record_limit), nil
 }
 
+// GetUsersByPermission implements repo.IUserRepo.
+// Active users whose role is granted the permission
+func (u *userRepo) GetUsersByPermission(permissionId string, ctx context.Context) (*[]entity.User, error) {
+	var query string = "SELECT u.* FROM " + entity.GetUserTable() + " u JOIN " + entity.GetRolePermissionTable() + " rp ON rp.role_id = u.role_id" +
+		" WHERE rp.permission_id = $1 AND u.is_active AND u.is_activated ORDER BY u.created_at"
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTable()) + "GetUsersByPermission - "
+
+	rows, err := u.db.Query(query, permissionId)
+	if err != nil {
+		u.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	defer rows.Close()
+
+	var res []entity.User
+	for rows.Next() {
+		var x entity.User
+		var isHaveToResetPw sql.NullBool
+		var vipCode sql.NullString
+
+		if err := rows.Scan(
+			&x.UserId, &x.RoleId, &x.FullName, &x.Email, &x.Password,
+			&x.ProfileAvatar, &x.Gender, &x.IsVip, &vipCode,
+			&x.IsActive, &x.IsActivated, &isHaveToResetPw, &x.CreatedAt, &x.UpdatedAt); err != nil {
+
+			u.logger.Println(errLogMsg + err.Error())
+			return nil, errors.New(noti.INTERNALL_ERR_MSG)
+		}
+
+		if isHaveToResetPw.Valid {
+			x.IsHaveToResetPw = &isHaveToResetPw.Bool
+		}
+
+		if vipCode.Valid {
+			x.VipCode = &vipCode.String
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
 // UpdateUser implements repo.IUserRepo.
 func (u *userRepo) UpdateUser(user entity.User, ctx context.Context) error {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTable()) + "UpdateUser - "
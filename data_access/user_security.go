@@ -10,6 +10,7 @@ import (
 	data_access "sonit_server/interface/data_access"
 	"sonit_server/model/dto/request"
 	entity "sonit_server/model/entity"
+	"time"
 )
 
 type userSecurityRepo struct {
@@ -93,11 +94,11 @@ func (u *userSecurityRepo) Logout(id string, ctx context.Context) error {
 
 // EditUserSecurity implements repo.IUserSecurityRepo.
 func (u *userSecurityRepo) EditUserSecurity(usc entity.UserSecurity, ctx context.Context) error {
-	var query string = "UPDATE " + entity.GetUserSecurityTable() + " SET access_token = $1, refresh_token = $2, action_token = $3, fail_access = $4, last_fail = $5 WHERE id = $6"
+	var query string = "UPDATE " + entity.GetUserSecurityTable() + " SET access_token = $1, refresh_token = $2, action_token = $3, fail_access = $4, last_fail = $5, locked_until = $6, lock_count = $7 WHERE id = $8"
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserSecurityTable()) + "EditUserSecurity - "
 	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
 
-	res, err := u.db.Exec(query, usc.AccessToken, usc.RefreshToken, usc.ActionToken, usc.FailAccess, &usc.LastFail, usc.UserId)
+	res, err := u.db.Exec(query, usc.AccessToken, usc.RefreshToken, usc.ActionToken, usc.FailAccess, &usc.LastFail, usc.LockedUntil, usc.LockCount, usc.UserId)
 	if err != nil {
 		u.logger.Println(errLogMsg, err.Error())
 		return INTERNALL_ERR_MSG
@@ -116,16 +117,38 @@ func (u *userSecurityRepo) EditUserSecurity(usc entity.UserSecurity, ctx context
 	return nil
 }
 
+// IncreaseFailAccess implements repo.IUserSecurityRepo.
+// Counts a failed login in one statement so concurrent failures are all counted, a count whose last failure is
+// older than the window starts over. Returns failed logins in the window and lockouts since the last successful login
+func (u *userSecurityRepo) IncreaseFailAccess(id string, windowStart, curTime time.Time, ctx context.Context) (int, int, error) {
+	var query string = "UPDATE " + entity.GetUserSecurityTable() +
+		" SET fail_access = CASE WHEN last_fail >= $1 THEN fail_access + 1 ELSE 1 END, last_fail = $2" +
+		" WHERE id = $3 RETURNING fail_access, lock_count"
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserSecurityTable()) + "IncreaseFailAccess - "
+
+	var failAccess, lockCount int
+	if err := u.db.QueryRow(query, windowStart, curTime, id).Scan(&failAccess, &lockCount); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetUserSecurityTable()))
+		}
+
+		u.logger.Println(errLogMsg, err.Error())
+		return 0, 0, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return failAccess, lockCount, nil
+}
+
 // GetUserSecurity implements repo.IUserSecurityRepo.
 func (u *userSecurityRepo) GetUserSecurity(id string, ctx context.Context) (*entity.UserSecurity, error) {
-	var query string = "SELECT * FROM " + entity.GetUserSecurityTable() + " WHERE id = $1"
+	var query string = "SELECT id, access_token, refresh_token, action_token, fail_access, last_fail, locked_until, lock_count FROM " + entity.GetUserSecurityTable() + " WHERE id = $1"
 
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserSecurityTable()) + "GetUserSecurity - "
 
 	var usc entity.UserSecurity
 	if err := u.db.QueryRow(query, id).Scan(
 		&usc.UserId, &usc.AccessToken, &usc.RefreshToken,
-		&usc.ActionToken, &usc.FailAccess, &usc.LastFail); err != nil {
+		&usc.ActionToken, &usc.FailAccess, &usc.LastFail, &usc.LockedUntil, &usc.LockCount); err != nil {
 
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -0,0 +1,127 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	domain_status "sonit_server/constant/domain_status"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type userTierRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeUserTierRepo(db *sql.DB, logger *log.Logger) data_access.IUserTierRepo {
+	return &userTierRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetUserTier implements dataaccess.IUserTierRepo.
+func (u *userTierRepo) GetUserTier(userId string, ctx context.Context) (*entity.UserTier, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTierTable()) + "GetUserTier - "
+	var query string = "SELECT user_id, tier_id, total_spend, evaluated_at FROM " + entity.GetUserTierTable() + " WHERE user_id = $1"
+
+	var res entity.UserTier
+	if err := u.db.QueryRow(query, userId).Scan(&res.UserId, &res.TierId, &res.TotalSpend, &res.EvaluatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		u.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// GetUserSpend implements dataaccess.IUserTierRepo.
+// Spend is the total of completed orders created since the given time
+func (u *userTierRepo) GetUserSpend(userId string, since time.Time, ctx context.Context) (float64, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTierTable()) + "GetUserSpend - "
+	var query string = "SELECT COALESCE(SUM(total_amount::numeric), 0) FROM " + entity.GetOrderTable() + " WHERE user_id = $1 AND status = $2 AND created_at >= $3"
+
+	var res float64
+	if err := u.db.QueryRow(query, userId, domain_status.ORDER_COMPLETED, since).Scan(&res); err != nil {
+		u.logger.Println(errLogMsg + err.Error())
+		return 0, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return res, nil
+}
+
+// GetUserTiersWithSpend implements dataaccess.IUserTierRepo.
+// Current tier of every active user with the spend of completed orders created since the given time
+func (u *userTierRepo) GetUserTiersWithSpend(since time.Time, ctx context.Context) (*[]entity.UserTier, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTierTable()) + "GetUserTiersWithSpend - "
+	var query string = "SELECT u.id, ut.tier_id, COALESCE(SUM(o.total_amount::numeric), 0), COALESCE(ut.evaluated_at, u.created_at)" +
+		" FROM " + entity.GetUserTable() + " u" +
+		" LEFT JOIN " + entity.GetUserTierTable() + " ut ON ut.user_id = u.id" +
+		" LEFT JOIN " + entity.GetOrderTable() + " o ON o.user_id = u.id AND o.status = $1 AND o.created_at >= $2" +
+		" WHERE u.is_active = TRUE" +
+		" GROUP BY u.id, ut.tier_id, ut.evaluated_at"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := u.db.Query(query, domain_status.ORDER_COMPLETED, since)
+	if err != nil {
+		u.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.UserTier
+	for rows.Next() {
+		var x entity.UserTier
+		if err := rows.Scan(&x.UserId, &x.TierId, &x.TotalSpend, &x.EvaluatedAt); err != nil {
+			u.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// SaveUserTier implements dataaccess.IUserTierRepo.
+// VIP flag of the user follows its tier
+func (u *userTierRepo) SaveUserTier(userTier entity.UserTier, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetUserTierTable()) + "SaveUserTier - "
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		u.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO "+entity.GetUserTierTable()+" (user_id, tier_id, total_spend, evaluated_at) VALUES ($1, $2, $3, $4)"+
+		" ON CONFLICT (user_id) DO UPDATE SET tier_id = EXCLUDED.tier_id, total_spend = EXCLUDED.total_spend, evaluated_at = EXCLUDED.evaluated_at",
+		userTier.UserId, userTier.TierId, userTier.TotalSpend, userTier.EvaluatedAt); err != nil {
+		u.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if _, err := tx.Exec("UPDATE "+entity.GetUserTable()+" SET is_vip = $1 WHERE id = $2", userTier.TierId != nil, userTier.UserId); err != nil {
+		u.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if err := tx.Commit(); err != nil {
+		u.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+)
+
+type vipTierRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeVipTierRepo(db *sql.DB, logger *log.Logger) data_access.IVipTierRepo {
+	return &vipTierRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetVipTiers implements dataaccess.IVipTierRepo.
+// Tiers are ordered from the lowest threshold
+func (v *vipTierRepo) GetVipTiers(ctx context.Context) (*[]entity.VipTier, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVipTierTable()) + "GetVipTiers - "
+	var query string = "SELECT id, name, min_spend, discount_percent, created_at, updated_at FROM " + entity.GetVipTierTable() + " ORDER BY min_spend"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := v.db.Query(query)
+	if err != nil {
+		v.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.VipTier
+	for rows.Next() {
+		var x entity.VipTier
+		if err := rows.Scan(&x.TierId, &x.TierName, &x.MinSpend, &x.DiscountPercent, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			v.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// GetVipTierById implements dataaccess.IVipTierRepo.
+func (v *vipTierRepo) GetVipTierById(id string, ctx context.Context) (*entity.VipTier, error) {
+	return v.getVipTier("GetVipTierById - ", "id = $1", id)
+}
+
+// GetVipTierByName implements dataaccess.IVipTierRepo.
+func (v *vipTierRepo) GetVipTierByName(name string, ctx context.Context) (*entity.VipTier, error) {
+	return v.getVipTier("GetVipTierByName - ", "LOWER(name) = LOWER($1)", name)
+}
+
+func (v *vipTierRepo) getVipTier(method, condition, value string) (*entity.VipTier, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVipTierTable()) + method
+	var query string = "SELECT id, name, min_spend, discount_percent, created_at, updated_at FROM " + entity.GetVipTierTable() + " WHERE " + condition
+
+	var res entity.VipTier
+	if err := v.db.QueryRow(query, value).Scan(&res.TierId, &res.TierName, &res.MinSpend, &res.DiscountPercent, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		v.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// CreateVipTier implements dataaccess.IVipTierRepo.
+func (v *vipTierRepo) CreateVipTier(tier entity.VipTier, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVipTierTable()) + "CreateVipTier - "
+	var query string = "INSERT INTO " + entity.GetVipTierTable() + " (id, name, min_spend, discount_percent, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)"
+
+	if _, err := v.db.Exec(query, tier.TierId, tier.TierName, tier.MinSpend, tier.DiscountPercent, tier.CreatedAt, tier.UpdatedAt); err != nil {
+		v.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// UpdateVipTier implements dataaccess.IVipTierRepo.
+func (v *vipTierRepo) UpdateVipTier(tier entity.VipTier, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVipTierTable()) + "UpdateVipTier - "
+	var query string = "UPDATE " + entity.GetVipTierTable() + " SET name = $1, min_spend = $2, discount_percent = $3, updated_at = $4 WHERE id = $5"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := v.db.Exec(query, tier.TierName, tier.MinSpend, tier.DiscountPercent, tier.UpdatedAt, tier.TierId)
+	if err != nil {
+		v.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		v.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetVipTierTable()))
+	}
+
+	return nil
+}
+
+// RemoveVipTier implements dataaccess.IVipTierRepo.
+// Members of the tier have no tier until the next evaluation
+func (v *vipTierRepo) RemoveVipTier(id string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVipTierTable()) + "RemoveVipTier - "
+	var query string = "DELETE FROM " + entity.GetVipTierTable() + " WHERE id = $1"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := v.db.Exec(query, id)
+	if err != nil {
+		v.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		v.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetVipTierTable()))
+	}
+
+	return nil
+}
@@ -42,7 +42,7 @@ func (c *voucherRepo) GetAllVouchers(ctx context.Context) (*[]entity.Voucher, er
 	for rows.Next() {
 		var x entity.Voucher
 		if err := rows.Scan(&x.VoucherId, &x.Code, &x.Discount, &x.Amount, &x.Description, &x.ActiveStatus,
-			pq.Array(&x.AllowedCategoryIDs), pq.Array(&x.AllowedProductIDs), &x.ExpiredAt, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			pq.Array(&x.AllowedCategoryIDs), pq.Array(&x.AllowedProductIDs), &x.ExpiredAt, &x.CreatedAt, &x.UpdatedAt, &x.UserId); err != nil {
 			c.logger.Println(errLogMsg + err.Error())
 			return nil, INTERNALL_ERR_MSG
 		}
@@ -70,7 +70,7 @@ func (c *voucherRepo) GetAllValidVouchers(ctx context.Context) (*[]entity.Vouche
 	for rows.Next() {
 		var x entity.Voucher
 		if err := rows.Scan(&x.VoucherId, &x.Code, &x.Discount, &x.Amount, &x.Description, &x.ActiveStatus,
-			pq.Array(&x.AllowedCategoryIDs), pq.Array(&x.AllowedProductIDs), &x.ExpiredAt, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			pq.Array(&x.AllowedCategoryIDs), pq.Array(&x.AllowedProductIDs), &x.ExpiredAt, &x.CreatedAt, &x.UpdatedAt, &x.UserId); err != nil {
 			c.logger.Println(errLogMsg + err.Error())
 			return nil, INTERNALL_ERR_MSG
 		}
@@ -85,12 +85,12 @@ func (c *voucherRepo) GetAllValidVouchers(ctx context.Context) (*[]entity.Vouche
 func (c *voucherRepo) CreateVoucher(voucher entity.Voucher, ctx context.Context) error {
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVoucherTable()) + "CreateVoucher - "
 	var query string = "INSERT INTO " + entity.GetVoucherTable() + "(id, code, discount, amount, description, " +
-		"active_status, allowed_category_ids, allowed_product_ids, expired_at, created_at, updated_at) " +
-		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)"
+		"active_status, allowed_category_ids, allowed_product_ids, expired_at, created_at, updated_at, user_id) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)"
 
 	if _, err := c.db.Exec(query, voucher.VoucherId, voucher.Code, voucher.Discount, voucher.Amount,
-		voucher.Description, voucher.ActiveStatus, voucher.AllowedCategoryIDs, voucher.AllowedProductIDs,
-		voucher.ExpiredAt, voucher.CreatedAt, voucher.UpdatedAt); err != nil {
+		voucher.Description, voucher.ActiveStatus, pq.Array(voucher.AllowedCategoryIDs), pq.Array(voucher.AllowedProductIDs),
+		voucher.ExpiredAt, voucher.CreatedAt, voucher.UpdatedAt, voucher.UserId); err != nil {
 		c.logger.Println(errLogMsg + err.Error())
 		return errors.New(noti.INTERNALL_ERR_MSG)
 	}
@@ -103,10 +103,10 @@ func (c *voucherRepo) GetVoucherByID(id string, ctx context.Context) (*entity.Vo
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVoucherTable()) + "GetVoucherByID - "
 	var query string = "SELECT * FROM " + entity.GetVoucherTable() + " WHERE id = $1"
 
-	var res *entity.Voucher
+	var res entity.Voucher
 	if err := c.db.QueryRow(query, id).Scan(&res.VoucherId, &res.Code, &res.Discount, &res.Amount,
 		&res.Description, &res.ActiveStatus, pq.Array(&res.AllowedCategoryIDs), pq.Array(&res.AllowedProductIDs),
-		&res.ExpiredAt, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		&res.ExpiredAt, &res.CreatedAt, &res.UpdatedAt, &res.UserId); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -115,7 +115,7 @@ func (c *voucherRepo) GetVoucherByID(id string, ctx context.Context) (*entity.Vo
 		return nil, errors.New(noti.INTERNALL_ERR_MSG)
 	}
 
-	return res, nil
+	return &res, nil
 }
 
 // GetVoucherByCode implements repo.IVoucherRepo.
@@ -123,10 +123,10 @@ func (c *voucherRepo) GetVoucherByCode(code string, ctx context.Context) (*entit
 	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetVoucherTable()) + "GetVoucherByCode - "
 	var query string = "SELECT * FROM " + entity.GetVoucherTable() + " WHERE LOWER(code) = LOWER($1)"
 
-	var res *entity.Voucher
+	var res entity.Voucher
 	if err := c.db.QueryRow(query, code).Scan(&res.VoucherId, &res.Code, &res.Discount, &res.Amount,
 		&res.Description, &res.ActiveStatus, pq.Array(&res.AllowedCategoryIDs), pq.Array(&res.AllowedProductIDs),
-		&res.ExpiredAt, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		&res.ExpiredAt, &res.CreatedAt, &res.UpdatedAt, &res.UserId); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -135,7 +135,7 @@ func (c *voucherRepo) GetVoucherByCode(code string, ctx context.Context) (*entit
 		return nil, errors.New(noti.INTERNALL_ERR_MSG)
 	}
 
-	return res, nil
+	return &res, nil
 }
 
 // UpdateVoucher implements repo.IVoucherRepo.
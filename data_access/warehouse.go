@@ -0,0 +1,140 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+)
+
+type warehouseRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeWarehouseRepo(db *sql.DB, logger *log.Logger) data_access.IWarehouseRepo {
+	return &warehouseRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const warehouse_columns string = "id, name, address, latitude, longitude, priority, is_active, created_at, updated_at"
+
+// GetWarehouses implements dataaccess.IWarehouseRepo.
+// Warehouses are ordered by priority
+func (w *warehouseRepo) GetWarehouses(ctx context.Context) (*[]entity.Warehouse, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWarehouseTable()) + "GetWarehouses - "
+	var query string = "SELECT " + warehouse_columns + " FROM " + entity.GetWarehouseTable() + " ORDER BY priority, id"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := w.db.Query(query)
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.Warehouse
+	for rows.Next() {
+		var x entity.Warehouse
+		var address sql.NullString
+		if err := rows.Scan(&x.WarehouseId, &x.Name, &address, &x.Latitude, &x.Longitude, &x.Priority, &x.IsActive, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			w.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		x.Address = address.String
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// GetWarehouseById implements dataaccess.IWarehouseRepo.
+func (w *warehouseRepo) GetWarehouseById(id string, ctx context.Context) (*entity.Warehouse, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWarehouseTable()) + "GetWarehouseById - "
+	var query string = "SELECT " + warehouse_columns + " FROM " + entity.GetWarehouseTable() + " WHERE id = $1"
+
+	var res entity.Warehouse
+	var address sql.NullString
+	if err := w.db.QueryRow(query, id).Scan(&res.WarehouseId, &res.Name, &address, &res.Latitude, &res.Longitude, &res.Priority, &res.IsActive, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		w.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	res.Address = address.String
+	return &res, nil
+}
+
+// CreateWarehouse implements dataaccess.IWarehouseRepo.
+func (w *warehouseRepo) CreateWarehouse(warehouse entity.Warehouse, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWarehouseTable()) + "CreateWarehouse - "
+	var query string = "INSERT INTO " + entity.GetWarehouseTable() + " (" + warehouse_columns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+
+	if _, err := w.db.Exec(query, warehouse.WarehouseId, warehouse.Name, warehouse.Address, warehouse.Latitude, warehouse.Longitude,
+		warehouse.Priority, warehouse.IsActive, warehouse.CreatedAt, warehouse.UpdatedAt); err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// UpdateWarehouse implements dataaccess.IWarehouseRepo.
+func (w *warehouseRepo) UpdateWarehouse(warehouse entity.Warehouse, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWarehouseTable()) + "UpdateWarehouse - "
+	var query string = "UPDATE " + entity.GetWarehouseTable() + " SET name = $1, address = $2, latitude = $3, longitude = $4, priority = $5, is_active = $6, updated_at = $7 WHERE id = $8"
+
+	res, err := w.db.Exec(query, warehouse.Name, warehouse.Address, warehouse.Latitude, warehouse.Longitude,
+		warehouse.Priority, warehouse.IsActive, warehouse.UpdatedAt, warehouse.WarehouseId)
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetWarehouseTable()))
+	}
+
+	return nil
+}
+
+// GetWarehouseInventories implements dataaccess.IWarehouseRepo.
+// Product and warehouse filters are optional
+func (w *warehouseRepo) GetWarehouseInventories(productId, warehouseId string, ctx context.Context) (*[]entity.WarehouseInventory, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWarehouseInventoryTable()) + "GetWarehouseInventories - "
+	var query string = "SELECT product_id, warehouse_id, current_quantity FROM " + entity.GetWarehouseInventoryTable() +
+		" WHERE ($1::text = '' OR product_id = $1::text) AND ($2::text = '' OR warehouse_id = $2::text) ORDER BY product_id, warehouse_id"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := w.db.Query(query, productId, warehouseId)
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.WarehouseInventory
+	for rows.Next() {
+		var x entity.WarehouseInventory
+		if err := rows.Scan(&x.ProductId, &x.WarehouseId, &x.CurrentQuantity); err != nil {
+			w.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,196 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type wishlistRepo struct {
+	db     *sql.DB
+	logger *log.Logger
+}
+
+func InitializeWishlistRepo(db *sql.DB, logger *log.Logger) data_access.IWishlistRepo {
+	return &wishlistRepo{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const wishlist_columns string = "id, user_id, product_id, list_type, notify_back_in_stock, notify_price_drop, notified_price, is_in_stock, created_at, updated_at"
+
+// GetWishlistItems implements dataaccess.IWishlistRepo.
+// Items of every list if list type is empty, newest first
+func (w *wishlistRepo) GetWishlistItems(userId, listType string, ctx context.Context) (*[]entity.WishlistItem, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWishlistTable()) + "GetWishlistItems - "
+	var query string = "SELECT " + wishlist_columns + " FROM " + entity.GetWishlistTable() + " WHERE user_id = $1"
+	var args = []interface{}{userId}
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	if listType != "" {
+		query += " AND list_type = $2"
+		args = append(args, listType)
+	}
+
+	rows, err := w.db.Query(query+" ORDER BY created_at DESC", args...)
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.WishlistItem
+	for rows.Next() {
+		var x entity.WishlistItem
+		if err := rows.Scan(&x.WishlistItemId, &x.UserId, &x.ProductId, &x.ListType, &x.NotifyBackInStock, &x.NotifyPriceDrop,
+			&x.NotifiedPrice, &x.IsInStock, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			w.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// GetWishlistItem implements dataaccess.IWishlistRepo.
+func (w *wishlistRepo) GetWishlistItem(userId, productId string, ctx context.Context) (*entity.WishlistItem, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWishlistTable()) + "GetWishlistItem - "
+	var query string = "SELECT " + wishlist_columns + " FROM " + entity.GetWishlistTable() + " WHERE user_id = $1 AND product_id = $2"
+
+	var res entity.WishlistItem
+	if err := w.db.QueryRow(query, userId, productId).Scan(&res.WishlistItemId, &res.UserId, &res.ProductId, &res.ListType, &res.NotifyBackInStock,
+		&res.NotifyPriceDrop, &res.NotifiedPrice, &res.IsInStock, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		w.logger.Println(errLogMsg + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return &res, nil
+}
+
+// SaveWishlistItem implements dataaccess.IWishlistRepo.
+// A product already saved is moved to the list of the item with its notification settings and snapshot replaced
+func (w *wishlistRepo) SaveWishlistItem(item entity.WishlistItem, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWishlistTable()) + "SaveWishlistItem - "
+	var query string = "INSERT INTO " + entity.GetWishlistTable() + " (" + wishlist_columns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)" +
+		" ON CONFLICT (user_id, product_id) DO UPDATE SET list_type = EXCLUDED.list_type, notify_back_in_stock = EXCLUDED.notify_back_in_stock," +
+		" notify_price_drop = EXCLUDED.notify_price_drop, notified_price = EXCLUDED.notified_price, is_in_stock = EXCLUDED.is_in_stock, updated_at = EXCLUDED.updated_at"
+
+	if _, err := w.db.Exec(query, item.WishlistItemId, item.UserId, item.ProductId, item.ListType, item.NotifyBackInStock, item.NotifyPriceDrop,
+		item.NotifiedPrice, item.IsInStock, item.CreatedAt, item.UpdatedAt); err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
+
+// RemoveWishlistItem implements dataaccess.IWishlistRepo.
+func (w *wishlistRepo) RemoveWishlistItem(userId, productId string, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWishlistTable()) + "RemoveWishlistItem - "
+	var query string = "DELETE FROM " + entity.GetWishlistTable() + " WHERE user_id = $1 AND product_id = $2"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := w.db.Exec(query, userId, productId)
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return INTERNALL_ERR_MSG
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetWishlistTable()))
+	}
+
+	return nil
+}
+
+// MarkOutOfStockWishlistItems implements dataaccess.IWishlistRepo.
+// Records items waiting for a restock so the next restock is notified
+func (w *wishlistRepo) MarkOutOfStockWishlistItems(updatedAt time.Time, ctx context.Context) (int, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWishlistTable()) + "MarkOutOfStockWishlistItems - "
+	var query string = "UPDATE " + entity.GetWishlistTable() + " w SET is_in_stock = FALSE, updated_at = $1" +
+		" WHERE w.notify_back_in_stock AND w.is_in_stock AND NOT EXISTS (SELECT 1 FROM " + entity.GetProductInventoryTable() + " i" +
+		" WHERE i.id = w.product_id AND i.current_quantity > 0)"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	res, err := w.db.Exec(query, updatedAt)
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return 0, INTERNALL_ERR_MSG
+	}
+
+	return int(rowsAffected), nil
+}
+
+// GetDueWishlistItems implements dataaccess.IWishlistRepo.
+// Items of active products in stock which were restocked or dropped in price since notified, grouped by user
+func (w *wishlistRepo) GetDueWishlistItems(ctx context.Context) (*[]entity.WishlistItem, error) {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWishlistTable()) + "GetDueWishlistItems - "
+	var query string = "SELECT w.id, w.user_id, w.product_id, w.list_type, w.notify_back_in_stock, w.notify_price_drop, w.notified_price, w.is_in_stock," +
+		" w.created_at, w.updated_at FROM " + entity.GetWishlistTable() + " w" +
+		" JOIN " + entity.GetProductTable() + " p ON p.id = w.product_id AND p.active_status" +
+		" JOIN " + entity.GetProductInventoryTable() + " i ON i.id = w.product_id AND i.current_quantity > 0" +
+		" WHERE (w.notify_back_in_stock AND NOT w.is_in_stock) OR (w.notify_price_drop AND p.price::numeric < w.notified_price)" +
+		" ORDER BY w.user_id, w.created_at DESC"
+	var INTERNALL_ERR_MSG error = errors.New(noti.INTERNALL_ERR_MSG)
+
+	rows, err := w.db.Query(query)
+	if err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return nil, INTERNALL_ERR_MSG
+	}
+
+	defer rows.Close()
+
+	var res []entity.WishlistItem
+	for rows.Next() {
+		var x entity.WishlistItem
+		if err := rows.Scan(&x.WishlistItemId, &x.UserId, &x.ProductId, &x.ListType, &x.NotifyBackInStock, &x.NotifyPriceDrop,
+			&x.NotifiedPrice, &x.IsInStock, &x.CreatedAt, &x.UpdatedAt); err != nil {
+			w.logger.Println(errLogMsg + err.Error())
+			return nil, INTERNALL_ERR_MSG
+		}
+
+		res = append(res, x)
+	}
+
+	return &res, nil
+}
+
+// UpdateWishlistItemSnapshot implements dataaccess.IWishlistRepo.
+func (w *wishlistRepo) UpdateWishlistItemSnapshot(id string, price float64, isInStock bool, updatedAt time.Time, ctx context.Context) error {
+	var errLogMsg string = fmt.Sprintf(noti.REPO_ERR_MSG, entity.GetWishlistTable()) + "UpdateWishlistItemSnapshot - "
+	var query string = "UPDATE " + entity.GetWishlistTable() + " SET notified_price = $1, is_in_stock = $2, updated_at = $3 WHERE id = $4"
+
+	if _, err := w.db.Exec(query, price, isInStock, updatedAt, id); err != nil {
+		w.logger.Println(errLogMsg + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return nil
+}
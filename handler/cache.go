@@ -0,0 +1,26 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/data_access/cache"
+	"sonit_server/model/dto/response"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get cache metrics
+// @Description Get cache hit / miss metrics of each namespace since the server started
+// @Tags cache
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]cache.Metric
+// @Failure 403 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Router /cache/metrics [get]
+func GetCacheMetrics(ctx *gin.Context) {
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    cache.GetMetrics(),
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
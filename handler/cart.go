@@ -7,10 +7,26 @@ import (
 	business_logic "sonit_server/usecase/business_logic"
 	"sonit_server/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Guest cart token is read from the header first, then from the cookie
+const (
+	cart_token_header string = "X-Cart-Token"
+	cart_token_cookie string = "cart_token"
+)
+
+func getCartToken(ctx *gin.Context) string {
+	if token := ctx.GetHeader(cart_token_header); token != "" {
+		return token
+	}
+
+	token, _ := ctx.Cookie(cart_token_cookie)
+	return token
+}
+
 // ViewCartDetail godoc
 // @Summary      View cart detail
 // @Description  Retrieves the details of a user's cart
@@ -142,3 +158,232 @@ func EditItemInCart(ctx *gin.Context) {
 		PostType: action_type.INFORM,
 	})
 }
+
+// AcknowledgeCartChanges godoc
+// @Summary      Acknowledge cart changes
+// @Description  Accepts current prices and stock of a user's cart: repriced lines take the new price, lines are capped by stock and unavailable lines are removed. Returns the lines which were revised
+// @Tags         carts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200 {array} response.CartLineResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /carts/{id}/acknowledge [put]
+func AcknowledgeCartChanges(ctx *gin.Context) {
+	service, err := business_logic.GenerateCartService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.AcknowledgeCartChanges(ctx.Param("id"), ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// EditCartReminderSubscription godoc
+// @Summary      Edit cart reminder subscription
+// @Description  Opts a user in or out of reminder mails of abandoned carts
+// @Tags         carts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.CartReminderSubscriptionRequest true "Subscription"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /carts/reminders [put]
+func EditCartReminderSubscription(ctx *gin.Context) {
+	var request request.CartReminderSubscriptionRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateCartService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.EditCartReminderSubscription(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// UnsubscribeCartReminders godoc
+// @Summary      Unsubscribe from cart reminders
+// @Description  Opts out of reminder mails of abandoned carts with the token of the unsubscribe link
+// @Tags         carts
+// @Produce      json
+// @Param        token query     string  true  "Unsubscribe token"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /carts/reminders/unsubscribe [get]
+func UnsubscribeCartReminders(ctx *gin.Context) {
+	service, err := business_logic.GenerateCartService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.UnsubscribeCartReminders(ctx.Query("token"), ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// ViewGuestCart godoc
+// @Summary      View guest cart
+// @Description  Retrieves the guest cart identified by the X-Cart-Token header or cart_token cookie
+// @Tags         carts
+// @Accept       json
+// @Produce      json
+// @Param        X-Cart-Token header    string  false "Guest cart token"
+// @Param        pageNumber   query     int     false "Page number for pagination"
+// @Success      200 {object} response.PaginationDataResponse
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /carts/guest [get]
+func ViewGuestCart(ctx *gin.Context) {
+	service, err := business_logic.GenerateCartService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	pageNumber, _ := strconv.Atoi(ctx.Query("pageNumber"))
+
+	res, err := service.ViewGuestCart(getCartToken(ctx), pageNumber, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// AddItemToGuestCart godoc
+// @Summary      Add item to guest cart
+// @Description  Adds an item to the guest cart, a new cart is created without a valid token. The token is returned and set as cart_token cookie
+// @Tags         carts
+// @Accept       json
+// @Produce      json
+// @Param        X-Cart-Token header    string  false "Guest cart token"
+// @Param        request body     request.GuestCartItemRequest true "Item to add"
+// @Success      200 {object} response.GuestCartTokenResponse
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /carts/guest/item/add [post]
+func AddItemToGuestCart(ctx *gin.Context) {
+	var request request.GuestCartItemRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateCartService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	request.CartToken = getCartToken(ctx)
+
+	res, err := service.AddItemToGuestCart(request, ctx)
+	if err == nil {
+		ctx.SetCookie(cart_token_cookie, res.CartToken, int(time.Until(res.ExpiredAt).Seconds()), "/", "", ctx.Request.TLS != nil, true)
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// EditItemInGuestCart godoc
+// @Summary      Edit item in guest cart
+// @Description  Updates the quantity of an item in the guest cart
+// @Tags         carts
+// @Accept       json
+// @Produce      json
+// @Param        X-Cart-Token header    string  false "Guest cart token"
+// @Param        request body     request.GuestCartItemRequest true "Item to edit"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /carts/guest/item/edit [put]
+func EditItemInGuestCart(ctx *gin.Context) {
+	var request request.GuestCartItemRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateCartService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	request.CartToken = getCartToken(ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.EditItemInGuestCart(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// RemoveItemFromGuestCart godoc
+// @Summary      Remove item from guest cart
+// @Description  Removes an item from the guest cart
+// @Tags         carts
+// @Accept       json
+// @Produce      json
+// @Param        X-Cart-Token header    string  false "Guest cart token"
+// @Param        request body     request.GuestCartItemRequest true "Item to remove"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /carts/guest/item/remove [delete]
+func RemoveItemFromGuestCart(ctx *gin.Context) {
+	var request request.GuestCartItemRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateCartService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	request.CartToken = getCartToken(ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.RemoveItemFromGuestCart(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLowStockInventories godoc
+// @Summary      Get low stock products
+// @Description  Lists products at or below their reorder point with days of cover estimated from recent sales
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        warehouse_id query string false "Warehouse ID, only products stocked there"
+// @Success      200 {array} response.LowStockInventoryResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /inventory/low-stock [get]
+func GetLowStockInventories(ctx *gin.Context) {
+	service, err := business_logic.GenerateInventoryService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetLowStockInventories(ctx.Query("warehouse_id"), ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// UpdateInventoryThreshold godoc
+// @Summary      Update reorder thresholds
+// @Description  Sets reorder point and safety stock of a product, a zero reorder point disables low stock alerts
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.UpdateInventoryThresholdRequest true "Reorder thresholds"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 404 {object} response.MessageAPIResponse "Object not found."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /inventory/threshold [put]
+func UpdateInventoryThreshold(ctx *gin.Context) {
+	var request request.UpdateInventoryThresholdRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateInventoryService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.UpdateInventoryThreshold(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/model/dto/response"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS godoc
+// @Summary      Get token verification keys
+// @Description  Public keys as JSON Web Key Set for verifying RS256 / EdDSA tokens, HMAC keys are never published
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} response.JWKSResponse
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /.well-known/jwks.json [get]
+func GetJWKS(ctx *gin.Context) {
+	res, err := utils.GetJWKS()
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
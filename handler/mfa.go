@@ -0,0 +1,196 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnrollTotp godoc
+// @Summary      Enroll TOTP
+// @Description  Generates a TOTP secret and its otpauth URI as QR code payload, enabled after confirmed with a code
+// @Tags         mfa
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Success      200 {object} response.TotpEnrollResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/mfa/totp/enroll [post]
+func EnrollTotp(ctx *gin.Context) {
+	service, err := business_logic.GenerateMfaService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.EnrollTotp(request.TotpEnrollRequest{
+		UserId:  ctx.Param("id"),
+		ActorId: ctx.GetString("userId"),
+	}, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// ConfirmTotp godoc
+// @Summary      Confirm TOTP
+// @Description  Enables TOTP with a code from the authenticator app and returns recovery codes, which are shown once
+// @Tags         mfa
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path string                 true "User ID"
+// @Param        request body request.MfaCodeRequest true "TOTP code"
+// @Success      200 {object} response.RecoveryCodesResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/mfa/totp/confirm [post]
+func ConfirmTotp(ctx *gin.Context) {
+	var request request.MfaCodeRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	request.UserId = ctx.Param("id")
+	request.ActorId = ctx.GetString("userId")
+
+	service, err := business_logic.GenerateMfaService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.ConfirmTotp(request, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// DisableTotp godoc
+// @Summary      Disable TOTP
+// @Description  Disables two-factor authentication with a TOTP code or a recovery code
+// @Tags         mfa
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path string                 true "User ID"
+// @Param        request body request.MfaCodeRequest true "TOTP code or recovery code"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/mfa/totp [delete]
+func DisableTotp(ctx *gin.Context) {
+	var request request.MfaCodeRequest
+	ctx.ShouldBindJSON(&request) // Pending enrollment is removed without a code
+
+	request.UserId = ctx.Param("id")
+	request.ActorId = ctx.GetString("userId")
+
+	service, err := business_logic.GenerateMfaService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.DisableTotp(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replaces recovery codes with new ones, requires a TOTP code
+// @Tags         mfa
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path string                 true "User ID"
+// @Param        request body request.MfaCodeRequest true "TOTP code"
+// @Success      200 {object} response.RecoveryCodesResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/mfa/recovery-codes [post]
+func RegenerateRecoveryCodes(ctx *gin.Context) {
+	var request request.MfaCodeRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	request.UserId = ctx.Param("id")
+	request.ActorId = ctx.GetString("userId")
+
+	service, err := business_logic.GenerateMfaService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.RegenerateRecoveryCodes(request, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// VerifyMfaLogin godoc
+// @Summary      Login second step
+// @Description  Verifies TOTP code or recovery code with the challenge token of login and returns tokens
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.MfaLoginRequest true "Challenge token and code"
+// @Success      200 {object} response.LoginSuccessResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /auth/login/mfa [post]
+func VerifyMfaLogin(ctx *gin.Context) {
+	var request request.MfaLoginRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	request.IpAddress = ctx.ClientIP()
+	request.UserAgent = ctx.Request.UserAgent()
+	request.CartToken = getCartToken(ctx)
+
+	service, err := business_logic.GenerateMfaService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res1, res2, err := service.VerifyMfaLogin(request, ctx)
+
+	utils.ProcessLoginResponse(response.APIResponse{
+		Data1:   res1,
+		Data2:   res2,
+		ErrMsg:  err,
+		Context: ctx,
+	})
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"io"
 	action_type "sonit_server/constant/action_type"
 	"sonit_server/model/dto/request"
 	"sonit_server/model/dto/response"
 	business_logic "sonit_server/usecase/business_logic"
 	"sonit_server/utils"
+	"sonit_server/utils/file"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,6 +24,7 @@ import (
 // @Param        filter_prop query string false "Filter property (e.g. date, price)"
 // @Param        order       query string false "Sort order (ASC or DESC)"
 // @Param        action      query string false "Inventory action (e.g. import, export, sale, return)"
+// @Param        warehouse_id query string false "Warehouse ID"
 // @Success 200 {object} response.PaginationDataResponse
 // @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
 // @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
@@ -116,20 +119,20 @@ func CreateProductInventoryTransaction(ctx *gin.Context) {
 	})
 }
 
-// @Summary Update product inventory transaction
-// @Description Updates an existing product inventory transaction
+// @Summary Correct product inventory transaction
+// @Description Records a reversing entry linked to a transaction and optionally a replacement entry, recorded transactions are never changed
 // @Tags product-inventory-transactions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body request.UpdateProductInventoryTransactionRequest true "Update product inventory transaction request"
+// @Param request body request.CorrectProductInventoryTransactionRequest true "Correct product inventory transaction request"
 // @Success 200 {object} response.MessageAPIResponse "success"
 // @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
 // @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
 // @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
-// @Router /product-inventory-transactions/update [put]
-func UpdateProductInventoryTransaction(ctx *gin.Context) {
-	var request request.UpdateProductInventoryTransactionRequest
+// @Router /product-inventory-transactions/correct [post]
+func CorrectProductInventoryTransaction(ctx *gin.Context) {
+	var request request.CorrectProductInventoryTransactionRequest
 	if ctx.ShouldBindJSON(&request) != nil {
 		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
 		return
@@ -142,8 +145,102 @@ func UpdateProductInventoryTransaction(ctx *gin.Context) {
 	}
 
 	utils.ProcessResponse(response.APIResponse{
-		ErrMsg:   service.UpdateProductInventoryTransaction(request, ctx),
+		ErrMsg:   service.CorrectProductInventoryTransaction(request, ctx),
 		Context:  ctx,
 		PostType: action_type.INFORM,
 	})
 }
+
+// @Summary Transfer inventory between warehouses
+// @Description Moves stock of a product from a warehouse to another in a single transaction, the product total is unchanged
+// @Tags product-inventory-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TransferInventoryRequest true "Transfer inventory request"
+// @Success 200 {object} response.MessageAPIResponse "success"
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 404 {object} response.MessageAPIResponse "Object not found."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Router /product-inventory-transactions/transfer [post]
+func TransferInventory(ctx *gin.Context) {
+	var request request.TransferInventoryRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateProductInventoryTransactionService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.TransferInventory(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// @Summary Import inventory transactions
+// @Description Records the rows of a CSV or XLSX file of sku (product ID), quantity, action and note at once. Every row is validated first: if one is invalid, row errors are returned and nothing is recorded.
+// @Description Stocktake mode takes counted quantities and records the difference with recorded quantities of the warehouse.
+// @Tags product-inventory-transactions
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file         formData file   true  "CSV or XLSX file, first row names the columns"
+// @Param mode         formData string false "transaction (default) or stocktake"
+// @Param warehouse_id formData string false "Warehouse ID, default warehouse if empty"
+// @Success 200 {object} response.InventoryImportResponse
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Router /product-inventory-transactions/import [post]
+func ImportInventoryTransactions(ctx *gin.Context) {
+	var request request.ImportInventoryRequest
+	if ctx.ShouldBind(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	upload, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	f, err := upload.Open()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+	defer f.Close()
+
+	// Oversized files are refused when read
+	data, err := io.ReadAll(io.LimitReader(f, file.Spreadsheet_max_size+1))
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	request.FileName = upload.Filename
+	request.Data = data
+
+	service, err := business_logic.GenerateProductInventoryTransactionService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.ImportInventoryTransactions(request, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
@@ -247,3 +247,97 @@ func UpdateRole(ctx *gin.Context) {
 		Context: ctx,
 	})
 }
+
+// GetAllPermissions retrieves the permission catalog
+// @Summary Get all permissions
+// @Description Retrieve every permission which can be granted to roles
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []entity.Permission
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /roles/permissions [get]
+func GetAllPermissions(ctx *gin.Context) {
+	service, err := business_logic.GenerateRoleService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetAllPermissions(ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		PostType: action_type.NON_POST,
+		Context:  ctx,
+	})
+}
+
+// GetRolePermissions retrieves permissions granted to a role
+// @Summary Get role permissions
+// @Description Retrieve permission ids granted to a role, an inactive role has none
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} []string
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 404 {object} response.MessageAPIResponse "roles not found."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /roles/{id}/permissions [get]
+func GetRolePermissions(ctx *gin.Context) {
+	service, err := business_logic.GenerateRoleService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetRolePermissions(ctx.Param("id"), ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		PostType: action_type.NON_POST,
+		Context:  ctx,
+	})
+}
+
+// SetRolePermissions replaces permissions granted to a role
+// @Summary Set role permissions
+// @Description Replace every permission granted to a role with permissions of the catalog
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Param request body request.SetRolePermissionsRequest true "Permission ids"
+// @Success 200 {object} response.MessageAPIResponse "success"
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 404 {object} response.MessageAPIResponse "roles not found."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /roles/{id}/permissions [put]
+func SetRolePermissions(ctx *gin.Context) {
+	var request request.SetRolePermissionsRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateRoleService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	request.RoleId = ctx.Param("id")
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:  service.SetRolePermissions(request, ctx),
+		Context: ctx,
+	})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockSubscriptions godoc
+// @Summary      Get back in stock subscriptions
+// @Description  Retrieves back in stock subscriptions of a user with their status
+// @Tags         stock-subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200 {array} entity.StockSubscription
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /stock-subscriptions/{id} [get]
+func GetStockSubscriptions(ctx *gin.Context) {
+	service, err := business_logic.GenerateStockSubscriptionService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetStockSubscriptions(ctx.Param("id"), ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// SubscribeToStock godoc
+// @Summary      Subscribe to a product restock
+// @Description  Asks for a mail when an out-of-stock product is imported again, subscribing again restarts a finished subscription
+// @Tags         stock-subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.StockSubscriptionRequest true "Product to watch"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "This product is in stock. You can only subscribe to out-of-stock products."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /stock-subscriptions [post]
+func SubscribeToStock(ctx *gin.Context) {
+	var request request.StockSubscriptionRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateStockSubscriptionService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.SubscribeToStock(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// UnsubscribeFromStock godoc
+// @Summary      Unsubscribe from a product restock
+// @Description  Removes a back in stock subscription
+// @Tags         stock-subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.StockSubscriptionRequest true "Product to stop watching"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 404 {object} response.MessageAPIResponse "Object not found."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /stock-subscriptions [delete]
+func UnsubscribeFromStock(ctx *gin.Context) {
+	var request request.StockSubscriptionRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateStockSubscriptionService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.UnsubscribeFromStock(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
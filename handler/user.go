@@ -1,24 +1,32 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
 	action_type "sonit_server/constant/action_type"
+	"sonit_server/constant/noti"
 	"sonit_server/model/dto/request"
 	"sonit_server/model/dto/response"
 	business_logic "sonit_server/usecase/business_logic"
 	"sonit_server/utils"
+	"sonit_server/utils/file"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Format query value of data export as ZIP archive
+const export_zip_format string = "zip"
+
 // Login godoc
 // @Summary      User login
-// @Description  Authenticates user credentials and returns access token
+// @Description  Authenticates user credentials and returns access and refresh tokens of a new session
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body request.LoginRequest true "Login credentials"
 // @Success      200 {object} response.LoginSuccessResponse
+// @Success      202 {object} response.MfaChallengeResponse "Second factor required, continue with /auth/login/mfa"
 // @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
 // @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
 // @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
@@ -36,6 +44,10 @@ func Login(ctx *gin.Context) {
 		return
 	}
 
+	request.IpAddress = ctx.ClientIP()
+	request.UserAgent = ctx.Request.UserAgent()
+	request.CartToken = getCartToken(ctx)
+
 	res1, res2, err := service.Login(request, ctx)
 
 	utils.ProcessLoginResponse(response.APIResponse{
@@ -46,9 +58,51 @@ func Login(ctx *gin.Context) {
 	})
 }
 
+// LoginWithOidc godoc
+// @Summary      Social login
+// @Description  Authenticates with an ID token of Google or Facebook, the account is linked by verified email or created
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        provider path string                   true "Provider: google, facebook"
+// @Param        request  body request.OidcLoginRequest true "ID token"
+// @Success      200 {object} response.LoginSuccessResponse
+// @Success      202 {object} response.MfaChallengeResponse "Second factor required, continue with /auth/login/mfa"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /auth/oidc/{provider} [post]
+func LoginWithOidc(ctx *gin.Context) {
+	var request request.OidcLoginRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	request.Provider = ctx.Param("provider")
+	request.IpAddress = ctx.ClientIP()
+	request.UserAgent = ctx.Request.UserAgent()
+	request.CartToken = getCartToken(ctx)
+
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res1, res2, err := service.LoginWithOidc(request, ctx)
+
+	utils.ProcessLoginResponse(response.APIResponse{
+		Data1:   res1,
+		Data2:   res2,
+		ErrMsg:  err,
+		Context: ctx,
+	})
+}
+
 // Logout godoc
 // @Summary      Logout user
-// @Description  Logs the user out and invalidates the session
+// @Description  Logs the user out and revokes the current session
 // @Tags         auth
 // @Produce      json
 // @Security     BearerAuth
@@ -66,7 +120,13 @@ func Logout(ctx *gin.Context) {
 	}
 
 	utils.ProcessResponse(response.APIResponse{
-		ErrMsg:   service.Logout(ctx.Param("id"), ctx),
+		ErrMsg: service.Logout(request.LogoutRequest{
+			UserId:    ctx.Param("id"),
+			ActorId:   ctx.GetString("userId"),
+			SessionId: ctx.GetString("sid"),
+			TokenId:   ctx.GetString("jti"),
+			TokenExp:  ctx.GetTime("tokenExp"),
+		}, ctx),
 		PostType: action_type.INFORM,
 		Context:  ctx,
 	})
@@ -229,6 +289,9 @@ func UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	// Actor is the logged in user, never the one in body
+	request.ActorId = ctx.GetString("userId")
+
 	res, err := service.UpdateUser(request, ctx)
 
 	utils.ProcessResponse(response.APIResponse{
@@ -272,25 +335,30 @@ func VerifyAction(ctx *gin.Context) {
 
 // ResetPassword godoc
 // @Summary      Reset password
-// @Description  Resets user password using a token
+// @Description  Resets forgotten password using the action token sent by mail, the password is never sent in the URL
 // @Tags         users
+// @Accept       json
 // @Produce      json
-// @Param        password        path string true "New password"
-// @Param        confirmPassword path string true "Confirm password"
-// @Param        token         path string true "Reset token"
+// @Param        request body request.ResetPasswordRequest true "Reset token and new password"
 // @Success      200 {object} interface{}
 // @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
 // @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
 // @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
-// @Router       /users/reset-password/{token}/{password}/{confirmPassword} [post]
+// @Router       /users/reset-password [post]
 func ResetPassword(ctx *gin.Context) {
+	var request request.ResetPasswordRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
 	service, err := business_logic.GenerateUserService()
 	if err != nil {
 		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
 		return
 	}
 
-	res, err := service.ResetPassword(ctx.Param("password"), ctx.Param("confirmPassword"), ctx.Param("token"), ctx)
+	res, err := service.ResetPassword(request, ctx)
 
 	utils.ProcessResponse(response.APIResponse{
 		Data1:    res,
@@ -301,6 +369,43 @@ func ResetPassword(ctx *gin.Context) {
 	})
 }
 
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Changes password of the logged in user with the current password, other sessions are logged out
+// @Tags         users
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path string                        true "User ID"
+// @Param        request body request.ChangePasswordRequest true "Current and new password"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/password [put]
+func ChangePassword(ctx *gin.Context) {
+	var request request.ChangePasswordRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	request.UserId = ctx.Param("id")
+	request.ActorId = ctx.GetString("userId")
+	request.SessionId = ctx.GetString("sid")
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:  service.ChangePassword(request, ctx),
+		Context: ctx,
+	})
+}
+
 // CreateUser godoc
 // @Summary      Create new user
 // @Description  Registers a new user account
@@ -326,6 +431,8 @@ func CreateUser(ctx *gin.Context) {
 		return
 	}
 
+	request.CartToken = getCartToken(ctx)
+
 	res, err := service.CreateUser(request, ctx)
 
 	utils.ProcessResponse(response.APIResponse{
@@ -372,7 +479,7 @@ func CreateVipCode(ctx *gin.Context) {
 
 // RefreshToken godoc
 // @Summary      Refresh access token
-// @Description  Refreshes the access token using a valid refresh token
+// @Description  Rotates tokens using a valid refresh token, the refresh token can only be used once
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -393,11 +500,252 @@ func RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	res, err := service.RefreshToken(request, ctx)
+	request.IpAddress = ctx.ClientIP()
+	request.UserAgent = ctx.Request.UserAgent()
+
+	res1, res2, err := service.RefreshToken(request, ctx)
 
 	utils.ProcessRefreshTokenResponse(response.APIResponse{
-		Data1:   res,
+		Data1:   res1,
+		Data2:   res2,
 		ErrMsg:  err,
 		Context: ctx,
 	})
 }
+
+// GetUserSessions godoc
+// @Summary      Get user sessions
+// @Description  Lists active sessions of a user on each device
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Success      200 {array} response.SessionResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/sessions [get]
+func GetUserSessions(ctx *gin.Context) {
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetUserSessions(request.GetSessionsRequest{
+		UserId:           ctx.Param("id"),
+		ActorId:          ctx.GetString("userId"),
+		CurrentSessionId: ctx.GetString("sid"),
+	}, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// RevokeUserSessions godoc
+// @Summary      Revoke user sessions
+// @Description  Logs out a session of a user, or every session if no session ID is given
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id        path string true  "User ID"
+// @Param        sessionId path string false "Session ID"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/sessions/{sessionId} [delete]
+func RevokeUserSessions(ctx *gin.Context) {
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg: service.RevokeUserSessions(request.RevokeSessionRequest{
+			UserId:    ctx.Param("id"),
+			ActorId:   ctx.GetString("userId"),
+			SessionId: ctx.Param("sessionId"),
+		}, ctx),
+		PostType: action_type.INFORM,
+		Context:  ctx,
+	})
+}
+
+// UnlockAccount godoc
+// @Summary      Unlock account
+// @Description  Releases a locked account and resets its failed login attempts
+// @Tags         users
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path string                       true  "User ID"
+// @Param        request body request.UnlockAccountRequest false "Unlock note"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/unlock [post]
+func UnlockAccount(ctx *gin.Context) {
+	var request request.UnlockAccountRequest
+	ctx.ShouldBindJSON(&request) // Note is optional
+
+	request.UserId = ctx.Param("id")
+	request.ActorId = ctx.GetString("userId")
+
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.UnlockAccount(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// GetLockoutEvents godoc
+// @Summary      Get lockout events
+// @Description  Audit history of lockouts and unlocks of an account
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Success      200 {array} entity.LockoutEvent
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/lockout-events [get]
+func GetLockoutEvents(ctx *gin.Context) {
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetLockoutEvents(ctx.Param("id"), ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// ExportUserData godoc
+// @Summary      Export personal data
+// @Description  Profile, orders, payments, addresses and cart of a user as JSON, or a ZIP of JSON files with format=zip
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Produce      application/zip
+// @Param        id     path  string true  "User ID"
+// @Param        format query string false "json (default) or zip"
+// @Success      200 {object} response.UserDataExportResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/export [get]
+func ExportUserData(ctx *gin.Context) {
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.ExportUserData(ctx.Param("id"), ctx)
+	if err != nil || ctx.Query("format") != export_zip_format {
+		utils.ProcessResponse(response.APIResponse{
+			Data1:    res,
+			ErrMsg:   err,
+			Context:  ctx,
+			PostType: action_type.NON_POST,
+		})
+		return
+	}
+
+	data, err := file.ToJsonZipArchive(map[string]interface{}{
+		"profile.json":   res.Profile,
+		"orders.json":    res.Orders,
+		"payments.json":  res.Payments,
+		"addresses.json": res.Addresses,
+		"cart.json":      res.Cart,
+		"deletion.json":  res.Deletion,
+	})
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, errors.New(noti.INTERNALL_ERR_MSG)))
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=user-data-"+res.Profile.UserId+".zip")
+	ctx.Data(http.StatusOK, "application/zip", data)
+}
+
+// RequestAccountDeletion godoc
+// @Summary      Request account deletion
+// @Description  Sends a confirmation mail, the account is anonymized after the grace period once confirmed. Orders and payments are kept
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Success      200 {object} response.MessageAPIResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id} [delete]
+func RequestAccountDeletion(ctx *gin.Context) {
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.RequestAccountDeletion(request.AccountDeletionRequest{
+		UserId:  ctx.Param("id"),
+		ActorId: ctx.GetString("userId"),
+	}, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		Data2:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// CancelAccountDeletion godoc
+// @Summary      Cancel account deletion
+// @Description  Cancels a confirmed account deletion during its grace period
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /users/{id}/deletion [delete]
+func CancelAccountDeletion(ctx *gin.Context) {
+	service, err := business_logic.GenerateUserService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg: service.CancelAccountDeletion(request.AccountDeletionRequest{
+			UserId:  ctx.Param("id"),
+			ActorId: ctx.GetString("userId"),
+		}, ctx),
+		Context: ctx,
+	})
+}
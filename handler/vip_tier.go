@@ -0,0 +1,158 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	request "sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetVipTiers godoc
+// @Summary Get VIP tiers
+// @Description Retrieve VIP tiers sorted by spend threshold
+// @Tags vip-tiers
+// @Accept json
+// @Produce json
+// @Success 200 {object} []entity.VipTier
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /vip-tiers [get]
+func GetVipTiers(ctx *gin.Context) {
+	service, err := business_logic.GenerateVipTierService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetVipTiers(ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		PostType: action_type.NON_POST,
+		Context:  ctx,
+	})
+}
+
+// CreateVipTier godoc
+// @Summary Create a VIP tier
+// @Description Create a VIP tier with its spend threshold and checkout discount
+// @Tags vip-tiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tier body request.CreateVipTierRequest true "VIP tier creation request"
+// @Success 201 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /vip-tiers [post]
+func CreateVipTier(ctx *gin.Context) {
+	var request request.CreateVipTierRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateVipTierService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:  service.CreateVipTier(request, ctx),
+		Context: ctx,
+	})
+}
+
+// UpdateVipTier godoc
+// @Summary Update a VIP tier
+// @Description Update name, spend threshold or checkout discount of a VIP tier
+// @Tags vip-tiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tier body request.UpdateVipTierRequest true "VIP tier update request"
+// @Success 200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /vip-tiers/update [put]
+func UpdateVipTier(ctx *gin.Context) {
+	var request request.UpdateVipTierRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateVipTierService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:  service.UpdateVipTier(request, ctx),
+		Context: ctx,
+	})
+}
+
+// RemoveVipTier godoc
+// @Summary Remove a VIP tier
+// @Description Remove a VIP tier by ID, its users lose the tier until next evaluation
+// @Tags vip-tiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "VIP tier ID"
+// @Success 200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /vip-tiers/remove/{id} [delete]
+func RemoveVipTier(ctx *gin.Context) {
+	service, err := business_logic.GenerateVipTierService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:  service.RemoveVipTier(ctx.Param("id"), ctx),
+		Context: ctx,
+	})
+}
+
+// GetVipTierProgress godoc
+// @Summary Get VIP tier progress of a user
+// @Description Current tier, tier matching the spend in the window and amount left to the next tier
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.VipTierProgressResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /users/{id}/vip-tier [get]
+func GetVipTierProgress(ctx *gin.Context) {
+	service, err := business_logic.GenerateVipTierService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetVipTierProgress(ctx.Param("id"), ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		PostType: action_type.NON_POST,
+		Context:  ctx,
+	})
+}
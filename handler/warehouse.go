@@ -0,0 +1,142 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	request "sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWarehouses godoc
+// @Summary Get warehouses
+// @Description Retrieve warehouses sorted by priority
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []entity.Warehouse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /warehouses [get]
+func GetWarehouses(ctx *gin.Context) {
+	service, err := business_logic.GenerateWarehouseService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetWarehouses(ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		PostType: action_type.NON_POST,
+		Context:  ctx,
+	})
+}
+
+// CreateWarehouse godoc
+// @Summary Create a warehouse
+// @Description Create an active warehouse, orders are shipped from the nearest one with stock
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param warehouse body request.CreateWarehouseRequest true "Warehouse creation request"
+// @Success 201 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /warehouses [post]
+func CreateWarehouse(ctx *gin.Context) {
+	var request request.CreateWarehouseRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateWarehouseService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:  service.CreateWarehouse(request, ctx),
+		Context: ctx,
+	})
+}
+
+// UpdateWarehouse godoc
+// @Summary Update a warehouse
+// @Description Update name, address, coordinates, priority or activation of a warehouse
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param warehouse body request.UpdateWarehouseRequest true "Warehouse update request"
+// @Success 200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 404 {object} response.MessageAPIResponse "Object not found."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /warehouses [put]
+func UpdateWarehouse(ctx *gin.Context) {
+	var request request.UpdateWarehouseRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateWarehouseService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.UpdateWarehouse(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// GetWarehouseInventories godoc
+// @Summary Get stock per warehouse
+// @Description Lists stock of products per warehouse, filtered by product and warehouse
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param product_id   query string false "Product ID"
+// @Param warehouse_id query string false "Warehouse ID"
+// @Success 200 {object} []entity.WarehouseInventory
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router /inventory/stock [get]
+func GetWarehouseInventories(ctx *gin.Context) {
+	var request request.GetWarehouseInventoriesRequest
+	if ctx.ShouldBindQuery(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateWarehouseService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	res, err := service.GetWarehouseInventories(request, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		PostType: action_type.NON_POST,
+		Context:  ctx,
+	})
+}
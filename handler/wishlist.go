@@ -0,0 +1,178 @@
+package handler
+
+import (
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ViewWishlist godoc
+// @Summary      View wishlist
+// @Description  Retrieves saved products of a user with current details
+// @Tags         wishlists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      string  true  "User ID"
+// @Param        type       query     string  false "WISHLIST or SAVED_FOR_LATER, every list if empty"
+// @Param        pageNumber query     int     false "Page number for pagination"
+// @Success      200 {object} response.PaginationDataResponse
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /wishlists/{id} [get]
+func ViewWishlist(ctx *gin.Context) {
+	service, err := business_logic.GenerateWishlistService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	pageNumber, _ := strconv.Atoi(ctx.Query("pageNumber"))
+
+	res, err := service.ViewWishlist(ctx.Param("id"), ctx.Query("type"), pageNumber, ctx)
+
+	utils.ProcessResponse(response.APIResponse{
+		Data1:    res,
+		ErrMsg:   err,
+		Context:  ctx,
+		PostType: action_type.NON_POST,
+	})
+}
+
+// AddItemToWishlist godoc
+// @Summary      Add item to wishlist
+// @Description  Saves a product to the wishlist or saved for later list, with optional back in stock and price drop mails
+// @Tags         wishlists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.AddWishlistItemRequest true "Item to save"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /wishlists/item/add [post]
+func AddItemToWishlist(ctx *gin.Context) {
+	var request request.AddWishlistItemRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateWishlistService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.AddItemToWishlist(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// RemoveItemFromWishlist godoc
+// @Summary      Remove item from wishlist
+// @Description  Removes a saved product
+// @Tags         wishlists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.WishlistItemRequest true "Item to remove"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /wishlists/item/remove [delete]
+func RemoveItemFromWishlist(ctx *gin.Context) {
+	var request request.WishlistItemRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateWishlistService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.RemoveItemFromWishlist(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// MoveWishlistItemToCart godoc
+// @Summary      Move wishlist item to cart
+// @Description  Adds a saved product to the cart, checked against stock, and removes it from the wishlist
+// @Tags         wishlists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.MoveWishlistItemToCartRequest true "Item to move"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /wishlists/item/move-to-cart [post]
+func MoveWishlistItemToCart(ctx *gin.Context) {
+	var request request.MoveWishlistItemToCartRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateWishlistService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.MoveItemToCart(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
+
+// SaveCartItemForLater godoc
+// @Summary      Save cart item for later
+// @Description  Moves a cart line to the saved for later list
+// @Tags         wishlists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     request.WishlistItemRequest true "Cart line to save"
+// @Success      200 {object} response.MessageAPIResponse "success"
+// @Failure 401 {object} response.MessageAPIResponse "You have no rights to access this action."
+// @Failure 400 {object} response.MessageAPIResponse "Invalid data. Please try again."
+// @Failure 500 {object} response.MessageAPIResponse "There is something wrong in the system during the process. Please try again later."
+// @Router       /wishlists/item/save-for-later [post]
+func SaveCartItemForLater(ctx *gin.Context) {
+	var request request.WishlistItemRequest
+	if ctx.ShouldBindJSON(&request) != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, nil))
+		return
+	}
+
+	service, err := business_logic.GenerateWishlistService()
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return
+	}
+
+	utils.ProcessResponse(response.APIResponse{
+		ErrMsg:   service.SaveCartItemForLater(request, ctx),
+		Context:  ctx,
+		PostType: action_type.INFORM,
+	})
+}
@@ -11,4 +11,14 @@ type ICartService interface {
 	AddItemToCart(req request.AddItemToCartRequest, ctx context.Context) error
 	RemoveItem(req request.RemoveItemFromCartRequest, ctx context.Context) error
 	EditItemInCart(req request.EditItemInCartRequest, ctx context.Context) error
+	AcknowledgeCartChanges(id string, ctx context.Context) ([]response.CartLineResponse, error)
+	ViewGuestCart(cartToken string, pageNumber int, ctx context.Context) (response.PaginationDataResponse, error)
+	AddItemToGuestCart(req request.GuestCartItemRequest, ctx context.Context) (*response.GuestCartTokenResponse, error)
+	EditItemInGuestCart(req request.GuestCartItemRequest, ctx context.Context) error
+	RemoveItemFromGuestCart(req request.GuestCartItemRequest, ctx context.Context) error
+	RemoveExpiredGuestCarts(ctx context.Context) (int, error)
+	SendAbandonedCartReminders(ctx context.Context) (int, error)
+	RemoveExpiredCarts(ctx context.Context) (int, error)
+	UnsubscribeCartReminders(token string, ctx context.Context) (string, error)
+	EditCartReminderSubscription(req request.CartReminderSubscriptionRequest, ctx context.Context) error
 }
@@ -0,0 +1,15 @@
+package businesslogic
+
+import (
+	"context"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	"sonit_server/model/entity"
+)
+
+type IInventoryService interface {
+	GetLowStockInventories(warehouseId string, ctx context.Context) ([]response.LowStockInventoryResponse, error)
+	UpdateInventoryThreshold(req request.UpdateInventoryThresholdRequest, ctx context.Context) error
+	SendLowStockDigest(ctx context.Context) (int, error)
+	VerifyInventoryLedger(ctx context.Context) ([]entity.InventoryDrift, error)
+}
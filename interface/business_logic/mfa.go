@@ -0,0 +1,15 @@
+package businesslogic
+
+import (
+	"context"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+)
+
+type IMfaService interface {
+	EnrollTotp(req request.TotpEnrollRequest, ctx context.Context) (*response.TotpEnrollResponse, error)
+	ConfirmTotp(req request.MfaCodeRequest, ctx context.Context) (*response.RecoveryCodesResponse, error)
+	DisableTotp(req request.MfaCodeRequest, ctx context.Context) error
+	RegenerateRecoveryCodes(req request.MfaCodeRequest, ctx context.Context) (*response.RecoveryCodesResponse, error)
+	VerifyMfaLogin(req request.MfaLoginRequest, ctx context.Context) (string, string, error)
+}
@@ -12,5 +12,7 @@ type IProductInventoryTransactionService interface {
 	GetInventoryTransactionsByProduct(req request.GetProductInventoryTrasactionsByProductRequest, ctx context.Context) (response.PaginationDataResponse, error)
 	GetProductInventoryTransaction(id string, ctx context.Context) (*entity.ProductInventoryTransaction, error)
 	CreateProductInventoryTransaction(req request.CreateProductInventoryTransactionRequest, ctx context.Context) error
-	UpdateProductInventoryTransaction(req request.UpdateProductInventoryTransactionRequest, ctx context.Context) error
+	CorrectProductInventoryTransaction(req request.CorrectProductInventoryTransactionRequest, ctx context.Context) error
+	TransferInventory(req request.TransferInventoryRequest, ctx context.Context) error
+	ImportInventoryTransactions(req request.ImportInventoryRequest, ctx context.Context) (response.InventoryImportResponse, error)
 }
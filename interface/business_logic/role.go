@@ -15,4 +15,9 @@ type IRoleService interface {
 	UpdateRole(req request.UpdateRoleRequest, ctx context.Context) error
 	RemoveRole(id string, ctx context.Context) error
 	ActivateRole(id string, ctx context.Context) error
+	GetAllPermissions(ctx context.Context) (*[]entity.Permission, error)
+	GetRolePermissions(id string, ctx context.Context) (*[]string, error)
+	SetRolePermissions(req request.SetRolePermissionsRequest, ctx context.Context) error
+	HasPermissions(roleId string, permissions []string, ctx context.Context) (bool, error)
+	GrantAdminPermissions(ctx context.Context) (int, error)
 }
@@ -0,0 +1,14 @@
+package businesslogic
+
+import (
+	"context"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/entity"
+)
+
+type IStockSubscriptionService interface {
+	GetStockSubscriptions(userId string, ctx context.Context) (*[]entity.StockSubscription, error)
+	SubscribeToStock(req request.StockSubscriptionRequest, ctx context.Context) error
+	UnsubscribeFromStock(req request.StockSubscriptionRequest, ctx context.Context) error
+	SendBackInStockNotifications(ctx context.Context) (int, error)
+}
@@ -14,13 +14,28 @@ type IUserService interface {
 	GetUsersByKeyword(pageNumber int, keyword string, ctx context.Context) (response.PaginationDataResponse, error)
 	GetUser(id string, ctx context.Context) (*entity.User, error)
 	CreateUser(req request.CreateUserRequest, ctx context.Context) (string, error)
+	CreateAdminAccount(req request.CreateUserRequest, ctx context.Context) (string, error)
 	CreateVipCode(req request.CreateVipCodeRequest, ctx context.Context) error
 	UpdateUser(req request.UpdateUserRequest, ctx context.Context) (string, error)
 	ChangeUserStatus(req request.ChangeUserStatusRequest, ctx context.Context) (string, error)
 	Login(req request.LoginRequest, ctx context.Context) (string, string, error)
-	Logout(id string, ctx context.Context) error
+	LoginWithOidc(req request.OidcLoginRequest, ctx context.Context) (string, string, error)
+	Logout(req request.LogoutRequest, ctx context.Context) error
 
 	VerifyAction(rawToken string, ctx context.Context) (string, error)
-	ResetPassword(newPass, re_newPass, token string, ctx context.Context) (string, error)
-	RefreshToken(req request.RefreshTokenRequest, ctx context.Context) (string, error)
+	ResetPassword(req request.ResetPasswordRequest, ctx context.Context) (string, error)
+	ChangePassword(req request.ChangePasswordRequest, ctx context.Context) error
+	ForceResetPassword(req request.ForceResetPasswordRequest, ctx context.Context) error
+	RefreshToken(req request.RefreshTokenRequest, ctx context.Context) (string, string, error)
+
+	GetUserSessions(req request.GetSessionsRequest, ctx context.Context) (*[]response.SessionResponse, error)
+	RevokeUserSessions(req request.RevokeSessionRequest, ctx context.Context) error
+
+	UnlockAccount(req request.UnlockAccountRequest, ctx context.Context) error
+	GetLockoutEvents(userId string, ctx context.Context) (*[]entity.LockoutEvent, error)
+
+	ExportUserData(userId string, ctx context.Context) (*response.UserDataExportResponse, error)
+	RequestAccountDeletion(req request.AccountDeletionRequest, ctx context.Context) (string, error)
+	CancelAccountDeletion(req request.AccountDeletionRequest, ctx context.Context) error
+	ProcessAccountDeletions(ctx context.Context) (int, error)
 }
@@ -0,0 +1,17 @@
+package businesslogic
+
+import (
+	"context"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	"sonit_server/model/entity"
+)
+
+type IVipTierService interface {
+	GetVipTiers(ctx context.Context) (*[]entity.VipTier, error)
+	CreateVipTier(req request.CreateVipTierRequest, ctx context.Context) error
+	UpdateVipTier(req request.UpdateVipTierRequest, ctx context.Context) error
+	RemoveVipTier(id string, ctx context.Context) error
+	GetVipTierProgress(userId string, ctx context.Context) (*response.VipTierProgressResponse, error)
+	EvaluateVipTiers(ctx context.Context) (int, error)
+}
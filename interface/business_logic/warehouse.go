@@ -0,0 +1,14 @@
+package businesslogic
+
+import (
+	"context"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/entity"
+)
+
+type IWarehouseService interface {
+	GetWarehouses(ctx context.Context) (*[]entity.Warehouse, error)
+	CreateWarehouse(req request.CreateWarehouseRequest, ctx context.Context) error
+	UpdateWarehouse(req request.UpdateWarehouseRequest, ctx context.Context) error
+	GetWarehouseInventories(req request.GetWarehouseInventoriesRequest, ctx context.Context) (*[]entity.WarehouseInventory, error)
+}
@@ -0,0 +1,16 @@
+package businesslogic
+
+import (
+	"context"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+)
+
+type IWishlistService interface {
+	ViewWishlist(userId, listType string, pageNumber int, ctx context.Context) (response.PaginationDataResponse, error)
+	AddItemToWishlist(req request.AddWishlistItemRequest, ctx context.Context) error
+	RemoveItemFromWishlist(req request.WishlistItemRequest, ctx context.Context) error
+	MoveItemToCart(req request.MoveWishlistItemToCartRequest, ctx context.Context) error
+	SaveCartItemForLater(req request.WishlistItemRequest, ctx context.Context) error
+	NotifyWishlistChanges(ctx context.Context) (int, error)
+}
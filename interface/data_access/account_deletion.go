@@ -0,0 +1,15 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type IAccountDeletionRepo interface {
+	GetScheduledAccountDeletion(userId string, ctx context.Context) (*entity.AccountDeletion, error)
+	GetDueAccountDeletions(dueAt time.Time, ctx context.Context) (*[]entity.AccountDeletion, error)
+	CreateAccountDeletion(deletion entity.AccountDeletion, ctx context.Context) error
+	UpdateAccountDeletion(deletion entity.AccountDeletion, ctx context.Context) error
+	AnonymizeAccount(deletion entity.AccountDeletion, user entity.User, shippingDetail string, ctx context.Context) error
+}
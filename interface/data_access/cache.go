@@ -0,0 +1,10 @@
+package dataaccess
+
+import "time"
+
+type ICache interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(keys ...string) error
+	Incr(key string) (int64, error)
+}
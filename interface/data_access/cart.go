@@ -3,6 +3,7 @@ package dataaccess
 import (
 	"context"
 	"sonit_server/model/entity"
+	"time"
 )
 
 type ICartRepo interface {
@@ -10,4 +11,12 @@ type ICartRepo interface {
 	UpdateCart(cart entity.Cart, ctx context.Context) error
 	CreateCart(cart entity.Cart, ctx context.Context) error
 	RemoveCart(id string, ctx context.Context) error
+	GetCartItems(cartId string, ctx context.Context) (*[]entity.CartItem, error)
+	AddCartItem(cart entity.Cart, item entity.CartItem, maxQuantity int, ctx context.Context) (bool, error)
+	EditCartItem(cart entity.Cart, item entity.CartItem, ctx context.Context) error
+	RemoveCartItem(cartId, productId string, ctx context.Context) error
+	DeductCartItems(cart entity.Cart, items []entity.CartItem, ctx context.Context) error
+	ReviseCartItems(cart entity.Cart, items []entity.CartItem, ctx context.Context) error
+	GetAbandonedCarts(before time.Time, ctx context.Context) (*[]entity.Cart, error)
+	RemoveExpiredCarts(expiredAt time.Time, ctx context.Context) (int, error)
 }
@@ -0,0 +1,17 @@
+package dataaccess
+
+import (
+	"context"
+	"sonit_server/model/entity"
+	"time"
+)
+
+type ICartReminderRepo interface {
+	CreateCartReminder(reminder entity.CartReminder, ctx context.Context) (bool, error)
+	GetCartReminderByTokenHash(tokenHash string, ctx context.Context) (*entity.CartReminder, error)
+	GetCartRemindersByUser(userId string, ctx context.Context) (*[]entity.CartReminder, error)
+	RemoveCartReminder(id string, ctx context.Context) error
+	IsCartReminderOptedOut(userId string, ctx context.Context) (bool, error)
+	OptOutCartReminders(userId string, optedOutAt time.Time, ctx context.Context) error
+	OptInCartReminders(userId string, ctx context.Context) error
+}
@@ -0,0 +1,17 @@
+package dataaccess
+
+import (
+	"context"
+	"sonit_server/model/entity"
+	"time"
+)
+
+type IGuestCartRepo interface {
+	GetGuestCart(id string, ctx context.Context) (*entity.GuestCart, error)
+	GetGuestCartItems(cartId string, ctx context.Context) (*[]entity.CartItem, error)
+	AddGuestCartItem(cart entity.GuestCart, item entity.CartItem, maxQuantity int, ctx context.Context) (bool, error)
+	EditGuestCartItem(cart entity.GuestCart, item entity.CartItem, ctx context.Context) error
+	RemoveGuestCartItem(cartId, productId string, ctx context.Context) error
+	MergeGuestCart(cartId string, cart entity.Cart, strategy string, ctx context.Context) (int, error)
+	RemoveExpiredGuestCarts(expiredAt time.Time, ctx context.Context) (int, error)
+}
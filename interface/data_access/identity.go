@@ -0,0 +1,13 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type IIdentityRepo interface {
+	GetIdentity(provider, subject string, ctx context.Context) (*entity.Identity, error)
+	CreateIdentity(identity entity.Identity, ctx context.Context) error
+	UpdateLastLogin(id string, loginAt time.Time, ctx context.Context) error
+}
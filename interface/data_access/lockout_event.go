@@ -0,0 +1,13 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+)
+
+type ILockoutEventRepo interface {
+	GetLockoutEventsByUser(userId string, ctx context.Context) (*[]entity.LockoutEvent, error)
+	CreateLockoutEvent(event entity.LockoutEvent, ctx context.Context) error
+	LockAccount(event entity.LockoutEvent, threshold int, ctx context.Context) (bool, error)
+	ReleaseLock(event entity.LockoutEvent, ctx context.Context) error
+}
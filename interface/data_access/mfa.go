@@ -0,0 +1,17 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+)
+
+type IMfaRepo interface {
+	GetUserTotp(userId string, ctx context.Context) (*entity.UserTotp, error)
+	SaveUserTotp(totp entity.UserTotp, ctx context.Context) error
+	RemoveUserTotp(userId string, ctx context.Context) error
+	UseTotpStep(userId string, step int64, ctx context.Context) (bool, error)
+
+	GetUnusedRecoveryCodes(userId string, ctx context.Context) (*[]entity.RecoveryCode, error)
+	ReplaceRecoveryCodes(userId string, codes []entity.RecoveryCode, ctx context.Context) error
+	UseRecoveryCode(id string, ctx context.Context) (bool, error)
+}
@@ -0,0 +1,11 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+)
+
+type IPasswordHistoryRepo interface {
+	GetRecentPasswordHistories(userId string, limit int, ctx context.Context) (*[]entity.PasswordHistory, error)
+	CreatePasswordHistory(history entity.PasswordHistory, keep int, ctx context.Context) error
+}
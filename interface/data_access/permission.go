@@ -0,0 +1,13 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+)
+
+type IPermissionRepo interface {
+	GetAllPermissions(ctx context.Context) (*[]entity.Permission, error)
+	GetPermissionsByRole(roleId string, ctx context.Context) (*[]string, error)
+	SetRolePermissions(roleId string, permissionIds []string, ctx context.Context) error
+	GrantAllPermissions(roleId string, ctx context.Context) (int, error)
+}
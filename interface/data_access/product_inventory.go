@@ -3,10 +3,15 @@ package dataaccess
 import (
 	"context"
 	"sonit_server/model/entity"
+	"time"
 )
 
 type IProductInventoryRepo interface {
 	GetProductInventory(id string, ctx context.Context) (*entity.ProductInventory, error)
-	UpdateProductInventory(inventory entity.ProductInventory, ctx context.Context) error
+	GetLowStockInventories(ctx context.Context) (*[]entity.ProductInventory, error)
+	GetSoldQuantities(since time.Time, actions []string, ctx context.Context) (map[string]int64, error)
+	UpdateInventoryThreshold(inventory entity.ProductInventory, ctx context.Context) error
+	UpdateLowStockAt(id string, lowStockAt *time.Time, ctx context.Context) error
+	SyncLowStockInventories(syncedAt time.Time, ctx context.Context) error
 	CreateProductInventory(inventory entity.ProductInventory, ctx context.Context) error
 }
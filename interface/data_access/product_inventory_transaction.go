@@ -10,6 +10,9 @@ type IProductInventoryTransactionRepo interface {
 	GetAllProductInventoryTransactions(req request.GetProductInventoryTrasactionsRequest, ctx context.Context) (*[]entity.ProductInventoryTransaction, int, error)
 	GetInventoryTransactionsByProduct(req request.GetProductInventoryTrasactionsByProductRequest, ctx context.Context) (*[]entity.ProductInventoryTransaction, int, error)
 	GetProductInventoryTransaction(id string, ctx context.Context) (*entity.ProductInventoryTransaction, error)
-	CreateProductInventoryTransaction(tx entity.ProductInventoryTransaction, ctx context.Context) error
-	UpdateProductInventoryTransaction(tx entity.ProductInventoryTransaction, ctx context.Context) error
+	IsProductInventoryTransactionCorrected(id string, ctx context.Context) (bool, error)
+	GetOrderInventoryTransactions(orderId string, ctx context.Context) (*[]entity.ProductInventoryTransaction, error)
+	GetInventoryDrifts(ctx context.Context) (*[]entity.InventoryDrift, error)
+	AppendProductInventoryTransactions(txs []entity.ProductInventoryTransaction, ctx context.Context) (*[]entity.ProductInventoryTransaction, error)
+	AppendStocktakeTransactions(txs []entity.ProductInventoryTransaction, ctx context.Context) (*[]entity.ProductInventoryTransaction, error)
 }
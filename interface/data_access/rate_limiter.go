@@ -0,0 +1,8 @@
+package dataaccess
+
+import "time"
+
+type IRateLimiter interface {
+	// Record a hit in the sliding window, returns false with the time to wait when the limit is reached
+	Allow(key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
@@ -0,0 +1,15 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+)
+
+type ISessionRepo interface {
+	GetSession(id string, ctx context.Context) (*entity.Session, error)
+	GetActiveSessionsByUser(userId string, ctx context.Context) (*[]entity.Session, error)
+	CreateSession(session entity.Session, ctx context.Context) error
+	RotateSession(session entity.Session, prevTokenHash string, ctx context.Context) (bool, error)
+	RevokeSession(id string, ctx context.Context) error
+	RevokeSessionsByUser(userId string, ctx context.Context) error
+}
@@ -0,0 +1,17 @@
+package dataaccess
+
+import (
+	"context"
+	"sonit_server/model/entity"
+	"time"
+)
+
+type IStockSubscriptionRepo interface {
+	GetStockSubscriptions(userId string, ctx context.Context) (*[]entity.StockSubscription, error)
+	SaveStockSubscription(subscription entity.StockSubscription, ctx context.Context) error
+	RemoveStockSubscription(userId, productId string, ctx context.Context) error
+	QueueStockSubscriptions(productId string, queuedAt time.Time, ctx context.Context) (int, error)
+	ClaimQueuedStockSubscriptions(limit int, claimedAt time.Time, ctx context.Context) (*[]entity.StockSubscription, error)
+	UpdateStockSubscriptionStatus(id, status string, updatedAt time.Time, ctx context.Context) error
+	RequeueStockSubscription(id string, maxAttempts int, updatedAt time.Time, ctx context.Context) error
+}
@@ -11,6 +11,7 @@ type IUserRepo interface {
 	GetUsersByStatus(pageNumber int, status bool, ctx context.Context) (*[]entity.User, int, error)
 	GetUser(id string, ctx context.Context) (*entity.User, error)
 	GetUserByEmail(email string, ctx context.Context) (*entity.User, error)
+	GetUsersByPermission(permissionId string, ctx context.Context) (*[]entity.User, error)
 	GetUsersByKeyword(pageNumber int, keyword string, ctx context.Context) (*[]entity.User, int, error)
 	CreateUser(user entity.User, ctx context.Context) error
 	UpdateUser(user entity.User, ctx context.Context) error
@@ -4,12 +4,14 @@ import (
 	"context"
 	"sonit_server/model/dto/request"
 	entity "sonit_server/model/entity"
+	"time"
 )
 
 type IUserSecurityRepo interface {
 	GetUserSecurity(id string, ctx context.Context) (*entity.UserSecurity, error)
 	CreateUserSecurity(usc entity.UserSecurity, ctx context.Context) error
 	EditUserSecurity(usc entity.UserSecurity, ctx context.Context) error
+	IncreaseFailAccess(id string, windowStart, curTime time.Time, ctx context.Context) (int, int, error)
 	Login(req request.LoginSecurityRequest, ctx context.Context) error
 	Logout(id string, ctx context.Context) error
 }
@@ -0,0 +1,23 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+type IVipTierRepo interface {
+	GetVipTiers(ctx context.Context) (*[]entity.VipTier, error)
+	GetVipTierById(id string, ctx context.Context) (*entity.VipTier, error)
+	GetVipTierByName(name string, ctx context.Context) (*entity.VipTier, error)
+	CreateVipTier(tier entity.VipTier, ctx context.Context) error
+	UpdateVipTier(tier entity.VipTier, ctx context.Context) error
+	RemoveVipTier(id string, ctx context.Context) error
+}
+
+type IUserTierRepo interface {
+	GetUserTier(userId string, ctx context.Context) (*entity.UserTier, error)
+	GetUserSpend(userId string, since time.Time, ctx context.Context) (float64, error)
+	GetUserTiersWithSpend(since time.Time, ctx context.Context) (*[]entity.UserTier, error)
+	SaveUserTier(userTier entity.UserTier, ctx context.Context) error
+}
@@ -0,0 +1,14 @@
+package dataaccess
+
+import (
+	"context"
+	entity "sonit_server/model/entity"
+)
+
+type IWarehouseRepo interface {
+	GetWarehouses(ctx context.Context) (*[]entity.Warehouse, error)
+	GetWarehouseById(id string, ctx context.Context) (*entity.Warehouse, error)
+	CreateWarehouse(warehouse entity.Warehouse, ctx context.Context) error
+	UpdateWarehouse(warehouse entity.Warehouse, ctx context.Context) error
+	GetWarehouseInventories(productId, warehouseId string, ctx context.Context) (*[]entity.WarehouseInventory, error)
+}
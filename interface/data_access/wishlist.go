@@ -0,0 +1,17 @@
+package dataaccess
+
+import (
+	"context"
+	"sonit_server/model/entity"
+	"time"
+)
+
+type IWishlistRepo interface {
+	GetWishlistItems(userId, listType string, ctx context.Context) (*[]entity.WishlistItem, error)
+	GetWishlistItem(userId, productId string, ctx context.Context) (*entity.WishlistItem, error)
+	SaveWishlistItem(item entity.WishlistItem, ctx context.Context) error
+	RemoveWishlistItem(userId, productId string, ctx context.Context) error
+	MarkOutOfStockWishlistItems(updatedAt time.Time, ctx context.Context) (int, error)
+	GetDueWishlistItems(ctx context.Context) (*[]entity.WishlistItem, error)
+	UpdateWishlistItemSnapshot(id string, price float64, isInStock bool, updatedAt time.Time, ctx context.Context) error
+}
@@ -14,3 +14,16 @@ type EditItemInCartRequest struct {
 	Request  RemoveItemFromCartRequest `json:"request"`
 	Quantity int                       `json:"quantity" validate:"required, min=1"`
 }
+
+// Line of a guest cart, add creates the cart if the token is empty or unknown
+type GuestCartItemRequest struct {
+	CartToken string `json:"-"` // Set from header or cookie
+	ProductId string `json:"product_id" validate:"required"`
+	Quantity  int    `json:"quantity"`
+}
+
+// Opt in or out of abandoned cart reminders
+type CartReminderSubscriptionRequest struct {
+	UserId       string `json:"user_id" validate:"required"`
+	IsSubscribed bool   `json:"is_subscribed"`
+}
@@ -0,0 +1,8 @@
+package request
+
+// Reorder thresholds of a product, both 0 disable low stock alerts
+type UpdateInventoryThresholdRequest struct {
+	ProductId    string `json:"product_id" validate:"required"`
+	ReorderPoint int64  `json:"reorder_point" validate:"min=0"`
+	SafetyStock  int64  `json:"safety_stock" validate:"min=0"` // Not above reorder point
+}
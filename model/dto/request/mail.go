@@ -1,6 +1,9 @@
 package request
 
-import "log"
+import (
+	"log"
+	"sonit_server/model/dto/response"
+)
 
 type MailBody struct {
 	Email    string
@@ -9,6 +12,21 @@ type MailBody struct {
 	Username string
 	Url      string
 	OrderId  string
+
+	LockedUntil string
+	GracePeriod string
+
+	CartItems        []response.CartItem
+	VoucherCode      string
+	VoucherDiscount  string
+	VoucherExpiredAt string
+	UnsubscribeUrl   string
+
+	WishlistItems []response.WishlistNoticeResponse
+
+	ProductName string
+
+	LowStockItems []response.LowStockInventoryResponse
 }
 
 type SendMailRequest struct {
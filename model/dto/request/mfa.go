@@ -0,0 +1,24 @@
+package request
+
+type TotpEnrollRequest struct {
+	UserId  string `json:"user_id" validate:"required"`
+	ActorId string `json:"actor_id"`
+}
+
+// Verify second factor with a TOTP code or a recovery code
+type MfaCodeRequest struct {
+	UserId       string `json:"user_id"`
+	ActorId      string `json:"actor_id"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MfaLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+	DeviceName     string `json:"device_name"`
+	IpAddress      string `json:"-"` // Set from request
+	UserAgent      string `json:"-"` // Set from request
+	CartToken      string `json:"-"` // Set from request
+}
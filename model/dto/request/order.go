@@ -14,6 +14,8 @@ type CreateOrderRequest struct {
 	Items        []response.CartItem `json:"items" validate:"required, min=1"`
 	Status       string              `json:"status"`
 	Note         string              `json:"note"`
+	Latitude     *float64            `json:"latitude"` // Delivery location, items ship from the nearest warehouses
+	Longitude    *float64            `json:"longitude"`
 }
 
 type UpdateOrderRequest struct {
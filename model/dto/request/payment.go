@@ -20,6 +20,8 @@ type CreatePaymentDirectRequest struct {
 	Product     ItemSelected `json:"product" validate:"required"`
 	Address     string       `jsosn:"address" validate:"required"`
 	PhoneNumber string       `json:"phone_number" validate:"required"`
+	Latitude    *float64     `json:"latitude"` // Delivery location, items ship from the nearest warehouses
+	Longitude   *float64     `json:"longitude"`
 }
 
 type CreatePaymentThroughCartRequest struct {
@@ -28,4 +30,6 @@ type CreatePaymentThroughCartRequest struct {
 	Items       []ItemSelected `json:"items" validate:"required"`
 	Address     string         `jsosn:"address" validate:"required"`
 	PhoneNumber string         `json:"phone_number" validate:"required"`
+	Latitude    *float64       `json:"latitude"` // Delivery location, items ship from the nearest warehouses
+	Longitude   *float64       `json:"longitude"`
 }
@@ -3,8 +3,9 @@ package request
 import "time"
 
 type GetProductInventoryTrasactionsRequest struct {
-	Pagination SearchPaginatioRequest `json:"pagination" validate:"required"`
-	Action     string                 `json:"action" form:"action"`
+	Pagination  SearchPaginatioRequest `json:"pagination" validate:"required"`
+	Action      string                 `json:"action" form:"action"`
+	WarehouseId string                 `json:"warehouse_id" form:"warehouse_id"`
 }
 
 type GetProductInventoryTrasactionsByProductRequest struct {
@@ -13,14 +14,34 @@ type GetProductInventoryTrasactionsByProductRequest struct {
 }
 
 type CreateProductInventoryTransactionRequest struct {
-	ProductId string    `json:"product_id" validate:"required"`
-	Amount    int64     `json:"amount" validate:"required, min=1"`
-	Action    string    `json:"action" validate:"required"` // Import, Export, Sale
-	Note      string    `json:"note"`
-	Date      time.Time `json:"date" validate:"required"`
+	ProductId   string    `json:"product_id" validate:"required"`
+	Amount      int64     `json:"amount" validate:"required, min=1"`
+	Action      string    `json:"action" validate:"required"` // Import, Export, Sale
+	WarehouseId string    `json:"warehouse_id"`               // Default warehouse if empty
+	Note        string    `json:"note"`
+	Date        time.Time `json:"date" validate:"required"`
 }
 
-type UpdateProductInventoryTransactionRequest struct {
-	TransactionId string                                   `json:"transaction_id" validate:"required"`
-	UpdateData    CreateProductInventoryTransactionRequest `json:"update_data"`
+// Correction reverses the transaction, replacement is recorded in its place if not empty
+type CorrectProductInventoryTransactionRequest struct {
+	TransactionId string                                    `json:"transaction_id" validate:"required"`
+	Note          string                                    `json:"note"`
+	Replacement   *CreateProductInventoryTransactionRequest `json:"replacement"`
+}
+
+// Stock moved between warehouses, the product total is unchanged
+type TransferInventoryRequest struct {
+	ProductId       string `json:"product_id" validate:"required"`
+	FromWarehouseId string `json:"from_warehouse_id" validate:"required"`
+	ToWarehouseId   string `json:"to_warehouse_id" validate:"required"`
+	Amount          int64  `json:"amount" validate:"required, min=1"`
+	Note            string `json:"note"`
+}
+
+// Spreadsheet of sku, quantity, action and note rows. Stocktake mode records the difference between counted and recorded quantities
+type ImportInventoryRequest struct {
+	Mode        string `json:"mode" form:"mode"`                 // transaction (default) or stocktake
+	WarehouseId string `json:"warehouse_id" form:"warehouse_id"` // Default warehouse if empty
+	FileName    string `json:"-" form:"-"`
+	Data        []byte `json:"-" form:"-"`
 }
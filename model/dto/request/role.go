@@ -4,3 +4,8 @@ type UpdateRoleRequest struct {
 	RoleId   string `json:"role_id" validate:"required"`
 	RoleName string `json:"role_name"`
 }
+
+type SetRolePermissionsRequest struct {
+	RoleId      string   `json:"-"` // From path
+	Permissions []string `json:"permissions"`
+}
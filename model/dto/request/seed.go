@@ -0,0 +1,65 @@
+package request
+
+// Fixture file content for seeding database with demo / test data
+type SeedFixture struct {
+	Roles       []SeedRole       `json:"roles" yaml:"roles"`
+	Users       []SeedUser       `json:"users" yaml:"users"`
+	Categories  []SeedCategory   `json:"categories" yaml:"categories"`
+	Collections []SeedCollection `json:"collections" yaml:"collections"`
+	Products    []SeedProduct    `json:"products" yaml:"products"`
+	Vouchers    []SeedVoucher    `json:"vouchers" yaml:"vouchers"`
+}
+
+type SeedRole struct {
+	RoleId      string   `json:"role_id" yaml:"role_id"`
+	RoleName    string   `json:"role_name" yaml:"role_name"`
+	Permissions []string `json:"permissions" yaml:"permissions"` // Granted when the role is created
+}
+
+type SeedUser struct {
+	UserId        string `json:"user_id" yaml:"user_id"`
+	RoleId        string `json:"role_id" yaml:"role_id"`
+	FullName      string `json:"full_name" yaml:"full_name"`
+	Email         string `json:"email" yaml:"email"`
+	Password      string `json:"password" yaml:"password"` // Raw password, hashed before saving
+	ProfileAvatar string `json:"profile_avatar" yaml:"profile_avatar"`
+	Gender        string `json:"gender" yaml:"gender"`
+	IsVip         bool   `json:"is_vip" yaml:"is_vip"`
+}
+
+type SeedCategory struct {
+	CategoryId   string `json:"category_id" yaml:"category_id"`
+	CategoryName string `json:"category_name" yaml:"category_name"`
+	Description  string `json:"description" yaml:"description"`
+}
+
+type SeedCollection struct {
+	CollectionId   string `json:"collection_id" yaml:"collection_id"`
+	CollectionName string `json:"collection_name" yaml:"collection_name"`
+	Description    string `json:"description" yaml:"description"`
+}
+
+type SeedProduct struct {
+	ProductId    string  `json:"product_id" yaml:"product_id"`
+	CategoryId   string  `json:"category_id" yaml:"category_id"`
+	CollectionId string  `json:"collection_id" yaml:"collection_id"`
+	ProductName  string  `json:"product_name" yaml:"product_name"`
+	Description  string  `json:"description" yaml:"description"`
+	Image        string  `json:"image" yaml:"image"`
+	Size         string  `json:"size" yaml:"size"`
+	Color        string  `json:"color" yaml:"color"`
+	Price        float64 `json:"price" yaml:"price"`
+	Currency     string  `json:"currency" yaml:"currency"`
+	Quantity     int64   `json:"quantity" yaml:"quantity"` // Initial inventory quantity
+}
+
+type SeedVoucher struct {
+	VoucherId          string   `json:"voucher_id" yaml:"voucher_id"`
+	Code               string   `json:"code" yaml:"code"`
+	Discount           float64  `json:"discount" yaml:"discount"` // e.g. 10.0 for 10%
+	Amount             int64    `json:"amount" yaml:"amount"`
+	Description        string   `json:"description" yaml:"description"`
+	AllowedCategoryIDs []string `json:"allowed_category_ids" yaml:"allowed_category_ids"`
+	AllowedProductIDs  []string `json:"allowed_product_ids" yaml:"allowed_product_ids"`
+	ExpiresInDays      int      `json:"expires_in_days" yaml:"expires_in_days"`
+}
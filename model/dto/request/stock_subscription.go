@@ -0,0 +1,6 @@
+package request
+
+type StockSubscriptionRequest struct {
+	UserId    string `json:"user_id" validate:"required"`
+	ProductId string `json:"product_id" validate:"required"`
+}
@@ -1,10 +1,27 @@
 package request
 
+import "time"
+
 // Security request
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Email      string `json:"email" validate:"required"`
+	Password   string `json:"password" validate:"required"`
+	DeviceName string `json:"device_name"`
+	IpAddress  string `json:"-"` // Set from request
+	UserAgent  string `json:"-"` // Set from request
+	CartToken  string `json:"-"` // Guest cart merged into the user cart once logged in
+}
+
+// Social login with ID token issued to the client by the provider
+type OidcLoginRequest struct {
+	Provider   string `json:"provider"`
+	IdToken    string `json:"id_token" validate:"required"`
+	Nonce      string `json:"nonce"` // Checked against nonce claim if given
+	DeviceName string `json:"device_name"`
+	IpAddress  string `json:"-"` // Set from request
+	UserAgent  string `json:"-"` // Set from request
+	CartToken  string `json:"-"` // Set from request
 }
 
 type LoginSecurityRequest struct {
@@ -13,8 +30,37 @@ type LoginSecurityRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type LogoutRequest struct {
+	UserId    string    `json:"user_id" validate:"required"`
+	ActorId   string    `json:"actor_id"`
+	SessionId string    `json:"session_id"`
+	TokenId   string    `json:"token_id"` // jti of the access token in use
+	TokenExp  time.Time `json:"token_exp"`
+}
+
+type UnlockAccountRequest struct {
+	UserId  string `json:"user_id" validate:"required"`
+	ActorId string `json:"actor_id"`
+	Note    string `json:"note"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
+	IpAddress    string `json:"-"` // Set from request
+	UserAgent    string `json:"-"` // Set from request
+}
+
+type GetSessionsRequest struct {
+	UserId           string `json:"user_id" validate:"required"`
+	ActorId          string `json:"actor_id"`
+	CurrentSessionId string `json:"current_session_id"`
+}
+
+// Revoke a session of user, all sessions if session id is empty
+type RevokeSessionRequest struct {
+	UserId    string `json:"user_id" validate:"required"`
+	ActorId   string `json:"actor_id"`
+	SessionId string `json:"session_id"`
 }
 
 // ---------------------------------------
@@ -27,6 +73,30 @@ type CreateUserRequest struct {
 	Password      string `json:"password" validate:"required,min=8"`
 	ProfileAvatar string `json:"profile_avatar"`
 	Gender        string `json:"gender"`
+	CartToken     string `json:"-"` // Guest cart merged into the new account's cart on self registration
+}
+
+// Reset password by operator without mail verification
+type ForceResetPasswordRequest struct {
+	Email         string `json:"email" validate:"required"`
+	Password      string `json:"password" validate:"required,min=8"`
+	IsHaveToReset bool   `json:"is_have_to_reset"` // Force user to change password at next login
+}
+
+// Reset forgotten password with the action token sent by mail
+type ResetPasswordRequest struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	UserId          string `json:"-"` // From path
+	ActorId         string `json:"-"` // From token
+	SessionId       string `json:"-"` // Current session stays logged in
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
 }
 
 type UpdateUserRequest struct {
@@ -53,3 +123,9 @@ type CreateVipCodeRequest struct {
 	UserId  string `json:"user_id" validate:"required"`
 	VipCode string `json:"vip_code" validate:"required, max=4, min=4"`
 }
+
+// Request or cancel deletion of own account
+type AccountDeletionRequest struct {
+	UserId  string `json:"-"` // From path
+	ActorId string `json:"-"` // From token
+}
@@ -0,0 +1,14 @@
+package request
+
+type CreateVipTierRequest struct {
+	TierName        string  `json:"tier_name" validate:"required"`
+	MinSpend        float64 `json:"min_spend"`
+	DiscountPercent float64 `json:"discount_percent"` // e.g. 5.0 for 5%
+}
+
+type UpdateVipTierRequest struct {
+	TierId          string   `json:"tier_id" validate:"required"`
+	TierName        string   `json:"tier_name"`
+	MinSpend        *float64 `json:"min_spend"`
+	DiscountPercent *float64 `json:"discount_percent"`
+}
@@ -0,0 +1,25 @@
+package request
+
+type CreateWarehouseRequest struct {
+	Name      string   `json:"name" validate:"required"`
+	Address   string   `json:"address"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Priority  int      `json:"priority"` // Lower is picked first when the delivery location is unknown
+}
+
+type UpdateWarehouseRequest struct {
+	WarehouseId string   `json:"warehouse_id" validate:"required"`
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Priority    *int     `json:"priority"`
+	IsActive    *bool    `json:"is_active"` // Inactive warehouses are skipped by orders and transfers to them
+}
+
+// Stock per product and warehouse, both filters are optional
+type GetWarehouseInventoriesRequest struct {
+	ProductId   string `json:"product_id" form:"product_id"`
+	WarehouseId string `json:"warehouse_id" form:"warehouse_id"`
+}
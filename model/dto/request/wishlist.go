@@ -0,0 +1,21 @@
+package request
+
+// Product to save, a product already saved is moved to the list with the new notification settings
+type AddWishlistItemRequest struct {
+	UserId            string `json:"user_id" validate:"required"`
+	ProductId         string `json:"product_id" validate:"required"`
+	ListType          string `json:"list_type"` // WISHLIST if empty, see constant/wishlist_type
+	NotifyBackInStock bool   `json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool   `json:"notify_price_drop"`
+}
+
+type WishlistItemRequest struct {
+	UserId    string `json:"user_id" validate:"required"`
+	ProductId string `json:"product_id" validate:"required"`
+}
+
+type MoveWishlistItemToCartRequest struct {
+	UserId    string `json:"user_id" validate:"required"`
+	ProductId string `json:"product_id" validate:"required"`
+	Quantity  int    `json:"quantity"` // 1 if empty
+}
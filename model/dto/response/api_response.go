@@ -15,8 +15,15 @@ type LoginSuccessResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// Login needs a second factor, tokens are issued by verifying the code with the challenge token
+type MfaChallengeResponse struct {
+	MfaRequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
 type RefreshTokenSuccessResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"` // Previous refresh token can't be used anymore
 }
 
 type MessageAPIResponse struct {
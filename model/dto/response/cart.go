@@ -18,7 +18,22 @@ type ViewGeneralCartUIResponse struct {
 }
 
 type ViewCartResponse struct {
-	UserId    string     `json:"user_id"`
-	Items     []CartItem `json:"items"`
-	ExpiredAt time.Time  `json:"expired_at"`
+	UserId    string             `json:"user_id"`
+	Items     []CartLineResponse `json:"items"`
+	ExpiredAt time.Time          `json:"expired_at"`
+}
+
+// Cart line revalidated against current product and stock, Price is the current price.
+// Status is one of CART_LINE_* in constant/domain_status
+type CartLineResponse struct {
+	CartItem
+	Status            string  `json:"status"`
+	AcknowledgedPrice float64 `json:"acknowledged_price"` // Price when added or last acknowledged
+	AvailableQuantity int64   `json:"available_quantity"`
+}
+
+// Token identifying a guest cart, sent back in the X-Cart-Token header or cart_token cookie
+type GuestCartTokenResponse struct {
+	CartToken string    `json:"cart_token"`
+	ExpiredAt time.Time `json:"expired_at"`
 }
@@ -0,0 +1,35 @@
+package response
+
+import (
+	"sonit_server/model/entity"
+	"time"
+)
+
+// Product at or below its reorder point. Days of cover is the current quantity divided by the average units sold per day, nil without recent sales
+type LowStockInventoryResponse struct {
+	ProductId          string                      `json:"product_id"`
+	Name               string                      `json:"name"`
+	CurrentQuantity    int64                       `json:"current_quantity"`
+	ReorderPoint       int64                       `json:"reorder_point"`
+	SafetyStock        int64                       `json:"safety_stock"`
+	IsBelowSafetyStock bool                        `json:"is_below_safety_stock"`
+	DailySales         float64                     `json:"daily_sales"`
+	DaysOfCover        *float64                    `json:"days_of_cover"`
+	LowStockAt         *time.Time                  `json:"low_stock_at"`
+	Warehouses         []entity.WarehouseInventory `json:"warehouses"` // Stock per warehouse, only the filtered warehouse if any
+}
+
+// Result of a spreadsheet import, nothing is applied if a row is invalid
+type InventoryImportResponse struct {
+	Mode         string                 `json:"mode"`
+	Rows         int                    `json:"rows"`
+	Transactions int                    `json:"transactions"` // Entries recorded, rows without difference are skipped by a stocktake
+	IsApplied    bool                   `json:"is_applied"`
+	Errors       []InventoryImportError `json:"errors"`
+}
+
+type InventoryImportError struct {
+	Line    int    `json:"line"`
+	Sku     string `json:"sku"`
+	Message string `json:"message"`
+}
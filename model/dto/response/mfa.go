@@ -0,0 +1,11 @@
+package response
+
+type TotpEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauth_uri"` // QR code payload for authenticator apps
+}
+
+// Recovery codes are shown once, only their hashes are kept
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,8 @@
+package response
+
+import entity "sonit_server/model/entity"
+
+type SessionResponse struct {
+	entity.Session
+	IsCurrent bool `json:"is_current"` // Session of the token making the request
+}
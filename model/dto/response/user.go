@@ -0,0 +1,38 @@
+package response
+
+import (
+	entity "sonit_server/model/entity"
+	"time"
+)
+
+// Profile of a user without credentials
+type UserProfileResponse struct {
+	UserId        string    `json:"user_id"`
+	RoleId        string    `json:"role_id"`
+	FullName      string    `json:"full_name"`
+	Email         string    `json:"email"`
+	ProfileAvatar string    `json:"profile_avatar"`
+	Gender        string    `json:"gender"`
+	IsVip         bool      `json:"is_vip"`
+	VipCode       *string   `json:"vip_code"`
+	IsActive      bool      `json:"is_active"`
+	IsActivated   bool      `json:"is_activated"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Personal data held about a user
+type UserDataExportResponse struct {
+	Profile                UserProfileResponse        `json:"profile"`
+	Orders                 []ViewOrderResponse        `json:"orders"`
+	Payments               []entity.Payment           `json:"payments"`
+	Addresses              []ShippingResponse         `json:"addresses"` // Shipping of each order
+	Cart                   *ViewCartResponse          `json:"cart"`
+	CartReminders          []entity.CartReminder      `json:"cart_reminders"` // Abandoned cart mails sent
+	IsCartReminderOptedOut bool                       `json:"is_cart_reminder_opted_out"`
+	Wishlist               []WishlistItemResponse     `json:"wishlist"`
+	StockSubscriptions     []entity.StockSubscription `json:"stock_subscriptions"` // Back in stock alerts
+	VipTier                *entity.UserTier           `json:"vip_tier"`            // Tier evaluation of the spend if any
+	Deletion               *entity.AccountDeletion    `json:"deletion"`            // Scheduled deletion if any
+	ExportedAt             time.Time                  `json:"exported_at"`
+}
@@ -0,0 +1,18 @@
+package response
+
+import (
+	"sonit_server/model/entity"
+	"time"
+)
+
+// Tier progress of a customer, CurrentTier is the stored tier while EligibleTier follows the current spend and is applied at next evaluation
+type VipTierProgressResponse struct {
+	UserId           string          `json:"user_id"`
+	CurrentTier      *entity.VipTier `json:"current_tier"`
+	EligibleTier     *entity.VipTier `json:"eligible_tier"`
+	NextTier         *entity.VipTier `json:"next_tier"`
+	TotalSpend       float64         `json:"total_spend"`
+	AmountToNextTier float64         `json:"amount_to_next_tier"`
+	WindowStart      time.Time       `json:"window_start"`
+	EvaluatedAt      *time.Time      `json:"evaluated_at"`
+}
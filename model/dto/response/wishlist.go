@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+// Saved product with current details, IsAvailable is false if the product is deactivated or out of stock
+type WishlistItemResponse struct {
+	ProductId         string    `json:"product_id"`
+	Name              string    `json:"name"`
+	ImageUrl          string    `json:"image_url"`
+	Price             float64   `json:"price"`
+	Currency          string    `json:"currency"`
+	ListType          string    `json:"list_type"`
+	IsAvailable       bool      `json:"is_available"`
+	NotifyBackInStock bool      `json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool      `json:"notify_price_drop"`
+	AddedAt           time.Time `json:"added_at"`
+}
+
+// Saved product of a notification mail
+type WishlistNoticeResponse struct {
+	WishlistItemResponse
+	PreviousPrice  float64 `json:"previous_price"`
+	IsRestocked    bool    `json:"is_restocked"`
+	IsPriceDropped bool    `json:"is_price_dropped"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Account deletion confirmed by the user, the account is anonymized once the grace period passes
+type AccountDeletion struct {
+	DeletionId  string     `json:"deletion_id"`
+	UserId      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func GetAccountDeletionTable() string {
+	return "account_deletions"
+}
@@ -3,13 +3,28 @@ package entity
 import "time"
 
 type Cart struct {
-	UserId    string    `json:"user_id"`
-	Items     string    `json:"items"`
-	ExpiredAt time.Time `json:"expired_at" validate:"required"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UserId       string     `json:"user_id"`
+	ExpiredAt    time.Time  `json:"expired_at" validate:"required"`
+	CheckedOutAt *time.Time `json:"checked_out_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Line of a cart, one per product
+type CartItem struct {
+	CartItemId string    `json:"cart_item_id"`
+	CartId     string    `json:"cart_id"`
+	ProductId  string    `json:"product_id"`
+	Quantity   int       `json:"quantity"`
+	UnitPrice  float64   `json:"unit_price"` // Price acknowledged by the shopper
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 func GetCartTable() string {
 	return "carts"
 }
+
+func GetCartItemTable() string {
+	return "cart_items"
+}
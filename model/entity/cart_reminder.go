@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// Reminder mail of an abandoned cart, one per cart revision
+type CartReminder struct {
+	ReminderId    string    `json:"reminder_id"`
+	UserId        string    `json:"user_id"`
+	CartUpdatedAt time.Time `json:"cart_updated_at"`
+	TokenHash     string    `json:"-"` // SHA-256 of the unsubscribe token
+	VoucherId     *string   `json:"voucher_id"`
+	RemindedAt    time.Time `json:"reminded_at"`
+}
+
+func GetCartReminderTable() string {
+	return "cart_reminders"
+}
+
+func GetCartReminderOptOutTable() string {
+	return "cart_reminder_opt_outs"
+}
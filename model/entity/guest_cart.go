@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// Cart of an anonymous shopper, identified by hash of its cart token. Lines are stored as CartItem
+type GuestCart struct {
+	CartId    string    `json:"cart_id"`
+	ExpiredAt time.Time `json:"expired_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func GetGuestCartTable() string {
+	return "guest_carts"
+}
+
+func GetGuestCartItemTable() string {
+	return "guest_cart_items"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Account of a social login provider linked to a user
+type Identity struct {
+	IdentityId  string    `json:"identity_id"`
+	UserId      string    `json:"user_id"`
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"` // sub claim of ID token, unique per provider
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+func GetIdentityTable() string {
+	return "identities"
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// Audit record of an account lockout or unlock
+type LockoutEvent struct {
+	EventId     string     `json:"event_id"`
+	UserId      string     `json:"user_id"`
+	Action      string     `json:"action"`
+	ActorId     *string    `json:"actor_id"` // Admin unlocking the account
+	IpAddress   *string    `json:"ip_address"`
+	LockedUntil *time.Time `json:"locked_until"`
+	Note        *string    `json:"note"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func GetLockoutEventTable() string {
+	return "account_lockout_events"
+}
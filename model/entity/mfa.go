@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// TOTP second factor of an account, pending until confirmed with a code
+type UserTotp struct {
+	UserId       string    `json:"user_id"`
+	Secret       string    `json:"-"`
+	IsEnabled    bool      `json:"is_enabled"`
+	LastUsedStep int64     `json:"-"` // Time step of the last accepted code, codes can't be replayed
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// One-time code to log in when the authenticator is lost, only its hash is stored
+type RecoveryCode struct {
+	CodeId    string     `json:"code_id"`
+	UserId    string     `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func GetUserTotpTable() string {
+	return "user_totps"
+}
+
+func GetRecoveryCodeTable() string {
+	return "mfa_recovery_codes"
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Previous password hash of a user, used to prevent password reuse
+type PasswordHistory struct {
+	HistoryId    string    `json:"history_id"`
+	UserId       string    `json:"user_id"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func GetPasswordHistoryTable() string {
+	return "password_histories"
+}
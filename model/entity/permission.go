@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Action which can be granted to roles, the id is its code such as product:write
+type Permission struct {
+	PermissionId string    `json:"permission_id"`
+	Description  string    `json:"description"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func GetPermissionTable() string {
+	return "permissions"
+}
+
+func GetRolePermissionTable() string {
+	return "role_permissions"
+}
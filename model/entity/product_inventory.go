@@ -1,8 +1,13 @@
 package entity
 
+import "time"
+
 type ProductInventory struct {
-	ProductId       string `json:"product_id"`
-	CurrentQuantity int64  `json:"current_quantity"`
+	ProductId       string     `json:"product_id"`
+	CurrentQuantity int64      `json:"current_quantity"`
+	ReorderPoint    int64      `json:"reorder_point"` // Low stock at or below this quantity, 0 disables alerts
+	SafetyStock     int64      `json:"safety_stock"`
+	LowStockAt      *time.Time `json:"low_stock_at"`
 }
 
 func GetProductInventoryTable() string {
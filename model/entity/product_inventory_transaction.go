@@ -2,15 +2,31 @@ package entity
 
 import "time"
 
+// Entry of the inventory ledger, never updated once recorded. A correction reverses the entry it corrects
 type ProductInventoryTransaction struct {
-	TransactionId string    `json:"transaction_id"`
-	ProductId     string    `json:"product_id"`
-	Amount        int64     `json:"amount"`
-	Action        string    `json:"action"` // Import, Export, Sale
-	Note          string    `json:"note"`
-	Date          time.Time `json:"date"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	TransactionId    string    `json:"transaction_id"`
+	ProductId        string    `json:"product_id"`
+	Amount           int64     `json:"amount"`
+	Action           string    `json:"action"` // Import, Export, Sale, Return, Cancel, Correction, Transfer out, Transfer in, Stocktake
+	Note             string    `json:"note"`
+	Date             time.Time `json:"date"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	QuantityChange   int64     `json:"quantity_change"` // Signed effect on the product inventory
+	Balance          *int64    `json:"balance"`         // Quantity after the entry, nil for entries recorded before the ledger
+	CorrectsId       *string   `json:"corrects_id"`
+	WarehouseId      string    `json:"warehouse_id"`
+	WarehouseBalance *int64    `json:"warehouse_balance"` // Quantity at the warehouse after the entry
+	TransferId       *string   `json:"transfer_id"`       // Shared by both entries of a transfer between warehouses
+	OrderId          *string   `json:"order_id"`
+}
+
+// Product whose current quantity differs from the sum of its ledger, at a warehouse if warehouse is not empty
+type InventoryDrift struct {
+	ProductId       string `json:"product_id"`
+	WarehouseId     string `json:"warehouse_id"`
+	CurrentQuantity int64  `json:"current_quantity"`
+	LedgerQuantity  int64  `json:"ledger_quantity"`
 }
 
 func GetProductInventoryTransactionTable() string {
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Login session of a device, the refresh token is rotated on every use and only its hash is stored
+type Session struct {
+	SessionId        string    `json:"session_id"`
+	UserId           string    `json:"user_id"`
+	RefreshTokenHash string    `json:"-"`
+	AccessTokenId    string    `json:"-"` // jti of the last issued access token, revoked along with the session
+	AccessExpiredAt  time.Time `json:"-"`
+	DeviceName       string    `json:"device_name"`
+	IpAddress        string    `json:"ip_address"`
+	UserAgent        string    `json:"user_agent"`
+	IsRevoked        bool      `json:"is_revoked"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastUsedAt       time.Time `json:"last_used_at"`
+	ExpiredAt        time.Time `json:"expired_at"`
+}
+
+func GetSessionTable() string {
+	return "sessions"
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Request of a user to be mailed once an out of stock product is restocked
+type StockSubscription struct {
+	SubscriptionId string     `json:"subscription_id"`
+	UserId         string     `json:"user_id"`
+	ProductId      string     `json:"product_id"`
+	Status         string     `json:"status"`   // See constant/domain_status
+	Attempts       int        `json:"attempts"` // Failed mails
+	QueuedAt       *time.Time `json:"queued_at"`
+	NotifiedAt     *time.Time `json:"notified_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func GetStockSubscriptionTable() string {
+	return "stock_subscriptions"
+}
@@ -9,6 +9,8 @@ type UserSecurity struct {
 	ActionToken  *string    `json:"action_token"` // Lưu trữ token cho thay đổi mail, reset pass
 	FailAccess   int        `json:"fail_access"`
 	LastFail     *time.Time `json:"last_fail"`
+	LockedUntil  *time.Time `json:"locked_until"` // Login is rejected until this time
+	LockCount    int        `json:"lock_count"`   // Lockouts since the last successful login, used for backoff
 }
 
 func GetUserSecurityTable() string {
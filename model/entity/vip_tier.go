@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// VIP membership tier, reached when spend of completed orders in the spend window hits MinSpend
+type VipTier struct {
+	TierId          string    `json:"tier_id"`
+	TierName        string    `json:"tier_name"`
+	MinSpend        float64   `json:"min_spend"`
+	DiscountPercent float64   `json:"discount_percent"` // e.g. 5.0 for 5% off at checkout
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Tier of a user at its last evaluation
+type UserTier struct {
+	UserId      string    `json:"user_id"`
+	TierId      *string   `json:"tier_id"` // No tier if spend is below every threshold
+	TotalSpend  float64   `json:"total_spend"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+}
+
+func GetVipTierTable() string {
+	return "vip_tiers"
+}
+
+func GetUserTierTable() string {
+	return "user_tiers"
+}
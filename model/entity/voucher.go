@@ -11,6 +11,7 @@ type Voucher struct {
 	ActiveStatus       bool      `json:"active_status"`
 	AllowedCategoryIDs []string  `json:"allowed_category_ids"`
 	AllowedProductIDs  []string  `json:"allowed_product_ids"`
+	UserId             *string   `json:"user_id"` // Only this user redeems the voucher, nil for every user
 	ExpiredAt          time.Time `json:"expired_at"`
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// Location products are shipped from. Orders are allocated to the nearest active warehouse with stock
+type Warehouse struct {
+	WarehouseId string    `json:"warehouse_id"`
+	Name        string    `json:"name"`
+	Address     string    `json:"address"`
+	Latitude    *float64  `json:"latitude"`
+	Longitude   *float64  `json:"longitude"`
+	Priority    int       `json:"priority"` // Lower is picked first when the delivery location is unknown
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Stock of a product at a warehouse, product inventory holds the total of every warehouse
+type WarehouseInventory struct {
+	ProductId       string `json:"product_id"`
+	WarehouseId     string `json:"warehouse_id"`
+	CurrentQuantity int64  `json:"current_quantity"`
+}
+
+func GetWarehouseTable() string {
+	return "warehouses"
+}
+
+func GetWarehouseInventoryTable() string {
+	return "warehouse_inventories"
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Product saved by a user, one per product across lists.
+// NotifiedPrice and IsInStock are the state when added or last notified, changes are compared to them
+type WishlistItem struct {
+	WishlistItemId    string    `json:"wishlist_item_id"`
+	UserId            string    `json:"user_id"`
+	ProductId         string    `json:"product_id"`
+	ListType          string    `json:"list_type"` // See constant/wishlist_type
+	NotifyBackInStock bool      `json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool      `json:"notify_price_drop"`
+	NotifiedPrice     float64   `json:"notified_price"`
+	IsInStock         bool      `json:"is_in_stock"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func GetWishlistTable() string {
+	return "wishlists"
+}
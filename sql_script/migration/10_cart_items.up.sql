@@ -0,0 +1,31 @@
+-- Move cart items from the JSON column of carts to cart_items --
+CREATE TABLE IF NOT EXISTS cart_items (
+    id character varying(100) PRIMARY KEY,
+    cart_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    quantity integer NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_cart_item_cart FOREIGN KEY (cart_id) REFERENCES carts(id) ON DELETE CASCADE,
+	CONSTRAINT fk_cart_item_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cart_items_cart_product ON cart_items (cart_id, product_id);
+
+-- Duplicated products of a cart are merged, removed products and empty lines are dropped.
+-- Skipped on databases created from script.sql, whose carts have no JSON column
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'carts' AND column_name = 'items') THEN
+		INSERT INTO cart_items (id, cart_id, product_id, quantity, created_at, updated_at)
+		SELECT md5(c.id || ':' || (item ->> 'product_id'))::uuid::text, c.id, item ->> 'product_id', SUM((item ->> 'quantity')::integer), c.created_at, c.updated_at
+		FROM (SELECT * FROM carts WHERE items LIKE '[%') c
+		CROSS JOIN LATERAL json_array_elements(c.items::json) AS item
+		WHERE EXISTS (SELECT 1 FROM products p WHERE p.id = item ->> 'product_id')
+		GROUP BY c.id, item ->> 'product_id', c.created_at, c.updated_at
+		HAVING SUM((item ->> 'quantity')::integer) > 0
+		ON CONFLICT (cart_id, product_id) DO NOTHING;
+
+		ALTER TABLE carts DROP COLUMN items;
+	END IF;
+END $$;
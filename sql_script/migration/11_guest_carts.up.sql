@@ -0,0 +1,24 @@
+-- Carts of guests kept by a cart token and merged into the cart of the user on login --
+-- Guest Carts --
+CREATE TABLE IF NOT EXISTS guest_carts (
+    id character varying(100) PRIMARY KEY, -- SHA-256 of the cart token
+	expired_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guest_carts_expired_at ON guest_carts (expired_at);
+
+-- Guest Cart Items --
+CREATE TABLE IF NOT EXISTS guest_cart_items (
+    id character varying(100) PRIMARY KEY,
+    cart_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    quantity integer NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_guest_cart_item_cart FOREIGN KEY (cart_id) REFERENCES guest_carts(id) ON DELETE CASCADE,
+	CONSTRAINT fk_guest_cart_item_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_guest_cart_items_cart_product ON guest_cart_items (cart_id, product_id);
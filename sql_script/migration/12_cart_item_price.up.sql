@@ -0,0 +1,14 @@
+-- Price of cart lines acknowledged by the shopper, existing lines take the current product price --
+-- Lines are only backfilled when the column is added, prices already acknowledged are kept
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'cart_items' AND column_name = 'unit_price') THEN
+		ALTER TABLE cart_items ADD COLUMN unit_price numeric(15, 2) NOT NULL DEFAULT 0;
+		UPDATE cart_items ci SET unit_price = p.price::numeric FROM products p WHERE p.id = ci.product_id AND p.price IS NOT NULL;
+	END IF;
+
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'guest_cart_items' AND column_name = 'unit_price') THEN
+		ALTER TABLE guest_cart_items ADD COLUMN unit_price numeric(15, 2) NOT NULL DEFAULT 0;
+		UPDATE guest_cart_items gi SET unit_price = p.price::numeric FROM products p WHERE p.id = gi.product_id AND p.price IS NOT NULL;
+	END IF;
+END $$;
@@ -0,0 +1,32 @@
+-- Last checkout of carts, voucher owners and reminders of abandoned carts, reminders skip carts unchanged since the last checkout --
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS checked_out_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_carts_updated_at ON carts (updated_at);
+
+-- Owner of personal vouchers, e.g. the voucher of a cart reminder, NULL for vouchers of every user --
+ALTER TABLE vouchers ADD COLUMN IF NOT EXISTS user_id character varying(100) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_vouchers_user_id ON vouchers (user_id);
+
+-- Cart Reminders --
+CREATE TABLE IF NOT EXISTS cart_reminders (
+    id character varying(100) PRIMARY KEY,
+    user_id character varying(100) NOT NULL,
+    cart_updated_at TIMESTAMPTZ NOT NULL, -- Cart revision the reminder was sent for
+    token_hash character varying(100) NOT NULL, -- SHA-256 of the unsubscribe token
+    voucher_id character varying(100),
+    reminded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_cart_reminder_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_cart_reminder_voucher FOREIGN KEY (voucher_id) REFERENCES vouchers(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cart_reminders_user_revision ON cart_reminders (user_id, cart_updated_at);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cart_reminders_token_hash ON cart_reminders (token_hash);
+
+-- Users unsubscribed from cart reminders --
+CREATE TABLE IF NOT EXISTS cart_reminder_opt_outs (
+    user_id character varying(100) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_cart_reminder_opt_out_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
@@ -0,0 +1,18 @@
+-- Wishlists and saved for later lists of users with their restock and price drop alerts --
+CREATE TABLE IF NOT EXISTS wishlists (
+    id character varying(100) PRIMARY KEY,
+    user_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    list_type character varying(100) NOT NULL DEFAULT 'WISHLIST', -- WISHLIST or SAVED_FOR_LATER
+    notify_back_in_stock boolean NOT NULL DEFAULT FALSE,
+    notify_price_drop boolean NOT NULL DEFAULT FALSE,
+    notified_price numeric(15, 2) NOT NULL DEFAULT 0, -- Price when added or last notified
+    is_in_stock boolean NOT NULL DEFAULT TRUE, -- Stock state when added or last notified
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_wishlist_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_wishlist_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_wishlists_user_product ON wishlists (user_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_wishlists_product_id ON wishlists (product_id);
@@ -0,0 +1,18 @@
+-- Back in stock subscriptions of users, queued when the product is restocked --
+CREATE TABLE IF NOT EXISTS stock_subscriptions (
+    id character varying(100) PRIMARY KEY,
+    user_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    status character varying(100) NOT NULL DEFAULT 'WAITING', -- WAITING, QUEUED, NOTIFIED or FAILED
+    attempts integer NOT NULL DEFAULT 0,
+    queued_at TIMESTAMPTZ,
+    notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_stock_subscription_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_stock_subscription_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_stock_subscriptions_user_product ON stock_subscriptions (user_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_product_status ON stock_subscriptions (product_id, status);
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_queued ON stock_subscriptions (queued_at) WHERE status = 'QUEUED';
@@ -0,0 +1,4 @@
+-- Reorder point and safety stock of products, low_stock_at is set while the quantity is at or below the reorder point --
+ALTER TABLE product_inventories ADD COLUMN IF NOT EXISTS reorder_point BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE product_inventories ADD COLUMN IF NOT EXISTS safety_stock BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE product_inventories ADD COLUMN IF NOT EXISTS low_stock_at TIMESTAMPTZ;
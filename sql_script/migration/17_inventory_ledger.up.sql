@@ -0,0 +1,23 @@
+-- Append-only inventory ledger: signed quantity change and resulting balance of each entry, corrections are reversing entries linked to the original --
+ALTER TABLE product_inventory_transactions ADD COLUMN IF NOT EXISTS quantity_change BIGINT;
+ALTER TABLE product_inventory_transactions ADD COLUMN IF NOT EXISTS balance BIGINT;
+ALTER TABLE product_inventory_transactions ADD COLUMN IF NOT EXISTS corrects_id character varying(100) REFERENCES product_inventory_transactions(id);
+
+-- Orders recorded sales as "Sale"
+UPDATE product_inventory_transactions SET action = lower(action) WHERE action <> lower(action);
+
+UPDATE product_inventory_transactions SET quantity_change = CASE WHEN action IN ('sale', 'export') THEN -amount ELSE amount END WHERE quantity_change IS NULL;
+
+ALTER TABLE product_inventory_transactions ALTER COLUMN quantity_change SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_inventory_tx_corrects_id ON product_inventory_transactions (corrects_id) WHERE corrects_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_tx_product_id ON product_inventory_transactions (product_id, created_at);
+
+-- Opening entry for quantities changed outside the ledger, so that the ledger sums up to current quantities
+INSERT INTO product_inventory_transactions (id, product_id, amount, action, note, date, quantity_change, balance)
+SELECT 'opening-' || i.id, i.id, abs(i.current_quantity - l.total), CASE WHEN i.current_quantity > l.total THEN 'import' ELSE 'export' END,
+    'Opening balance of ledger', CURRENT_TIMESTAMP, i.current_quantity - l.total, i.current_quantity
+FROM product_inventories i
+CROSS JOIN LATERAL (SELECT COALESCE(SUM(t.quantity_change), 0) AS total FROM product_inventory_transactions t WHERE t.product_id = i.id) l
+WHERE i.current_quantity <> l.total
+ON CONFLICT (id) DO NOTHING;
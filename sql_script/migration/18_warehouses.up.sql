@@ -0,0 +1,47 @@
+-- Warehouses and stock per product and warehouse, product inventories keep the total of every warehouse --
+CREATE TABLE IF NOT EXISTS warehouses (
+    id character varying(100) PRIMARY KEY,
+	name character varying(100) NOT NULL,
+	address character varying(255),
+	latitude DOUBLE PRECISION,
+	longitude DOUBLE PRECISION,
+	priority INT NOT NULL DEFAULT 0,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Default warehouse, holds stock recorded before warehouses --
+INSERT INTO warehouses (id, name) VALUES ('main', 'Main warehouse') ON CONFLICT (id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS warehouse_inventories (
+    product_id character varying(100) NOT NULL,
+	warehouse_id character varying(100) NOT NULL,
+	current_quantity BIGINT NOT NULL DEFAULT 0 CHECK (current_quantity >= 0),
+	PRIMARY KEY (product_id, warehouse_id),
+	CONSTRAINT fk_warehouseInventory_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+	CONSTRAINT fk_warehouseInventory_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses(id)
+);
+
+-- Current quantities move to the default warehouse, only on databases recorded before warehouses.
+-- Stock of databases created from script.sql is already split by warehouse and would be counted twice
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'product_inventory_transactions' AND column_name = 'warehouse_id') THEN
+		INSERT INTO warehouse_inventories (product_id, warehouse_id, current_quantity)
+		SELECT id, 'main', GREATEST(current_quantity, 0) FROM product_inventories
+		ON CONFLICT (product_id, warehouse_id) DO NOTHING;
+	END IF;
+END $$;
+
+ALTER TABLE product_inventory_transactions ADD COLUMN IF NOT EXISTS warehouse_id character varying(100) REFERENCES warehouses(id);
+ALTER TABLE product_inventory_transactions ADD COLUMN IF NOT EXISTS warehouse_balance BIGINT;
+ALTER TABLE product_inventory_transactions ADD COLUMN IF NOT EXISTS transfer_id character varying(100);
+ALTER TABLE product_inventory_transactions ADD COLUMN IF NOT EXISTS order_id character varying(100);
+
+UPDATE product_inventory_transactions SET warehouse_id = 'main' WHERE warehouse_id IS NULL;
+
+ALTER TABLE product_inventory_transactions ALTER COLUMN warehouse_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_tx_warehouse_id ON product_inventory_transactions (warehouse_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_inventory_tx_order_id ON product_inventory_transactions (order_id) WHERE order_id IS NOT NULL;
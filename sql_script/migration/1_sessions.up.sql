@@ -0,0 +1,19 @@
+-- Per-device sessions holding the rotating refresh token --
+CREATE TABLE IF NOT EXISTS sessions
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	refresh_token_hash character varying(100) NOT NULL,
+	access_token_id character varying(100) NOT NULL,
+	access_expired_at timestamp without time zone NOT NULL,
+	device_name character varying(100),
+	ip_address character varying(100),
+	user_agent text,
+	is_revoked bool NOT NULL DEFAULT FALSE,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	last_used_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	expired_at timestamp without time zone NOT NULL,
+	CONSTRAINT fk_session_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
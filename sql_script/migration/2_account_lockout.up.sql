@@ -0,0 +1,16 @@
+-- Account lockout: lock end and lock count of users, audit of locks and unlocks --
+ALTER TABLE user_securities ADD COLUMN IF NOT EXISTS locked_until timestamp without time zone NULL;
+ALTER TABLE user_securities ADD COLUMN IF NOT EXISTS lock_count int DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS account_lockout_events
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	action character varying(30) NOT NULL,
+	actor_id character varying(100),
+	ip_address character varying(100),
+	locked_until timestamp without time zone NULL,
+	note text,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_lockout_event_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,21 @@
+-- TOTP second factor of users and their one-time recovery codes --
+CREATE TABLE IF NOT EXISTS user_totps
+(
+	user_id character varying(100) PRIMARY KEY,
+	secret character varying(100) NOT NULL,
+	is_enabled bool NOT NULL DEFAULT FALSE,
+	last_used_step bigint NOT NULL DEFAULT 0,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_totp_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	code_hash character varying(100) NOT NULL,
+	used_at timestamp without time zone NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_recovery_code_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
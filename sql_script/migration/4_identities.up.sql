@@ -0,0 +1,13 @@
+-- Identities of users logged in with an OpenID Connect provider --
+CREATE TABLE IF NOT EXISTS identities
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	provider character varying(30) NOT NULL,
+	subject character varying(255) NOT NULL,
+	email character varying(100),
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	last_login_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT uq_identity_provider_subject UNIQUE (provider, subject),
+	CONSTRAINT fk_identity_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,33 @@
+-- Permission catalog and role permissions replacing the single admin role, the migrate command grants every permission to ADMIN_ROLE --
+-- Permissions --
+CREATE TABLE IF NOT EXISTS permissions (
+	id character varying(100) PRIMARY KEY, -- Code such as product:write
+	description character varying(255) NOT NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Role Permissions --
+CREATE TABLE IF NOT EXISTS role_permissions (
+	role_id character varying(100) NOT NULL,
+	permission_id character varying(100) NOT NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (role_id, permission_id),
+	CONSTRAINT fk_role_permission_role FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+	CONSTRAINT fk_role_permission_permission FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+-- Catalog, existing permissions are kept --
+INSERT INTO permissions (id, description) VALUES 
+('user:manage', 'View, unlock and edit other accounts'),
+('role:manage', 'Manage roles and their permissions'),
+('category:write', 'Manage categories'),
+('collection:write', 'Manage collections'),
+('product:write', 'Manage products, including inactive ones'),
+('inventory:adjust', 'Manage product inventory transactions'),
+('order:read', 'View orders of every user'),
+('order:refund', 'Set orders and payments as refunded'),
+('payment:read', 'View payments of every user'),
+('payment:write', 'Update payment status, e.g. refunds'),
+('voucher:write', 'Manage vouchers'),
+('system:monitor', 'View cache metrics')
+ON CONFLICT (id) DO NOTHING;
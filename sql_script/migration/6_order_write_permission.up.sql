@@ -0,0 +1,3 @@
+-- Permission to create and update orders of every user, granted to ADMIN_ROLE by the migrate command like every permission --
+INSERT INTO permissions (id, description) VALUES ('order:write', 'Create and update orders of every user')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,11 @@
+-- Password histories preventing reuse of the last passwords of users --
+CREATE TABLE IF NOT EXISTS password_histories
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	password_hash character varying(255) NOT NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_password_history_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_histories_user ON password_histories (user_id, created_at DESC);
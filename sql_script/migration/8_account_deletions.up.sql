@@ -0,0 +1,14 @@
+-- Account deletions scheduled by users, personal data is anonymized once the grace period passes --
+CREATE TABLE IF NOT EXISTS account_deletions
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	status character varying(30) NOT NULL,
+	scheduled_at timestamp without time zone NOT NULL,
+	completed_at timestamp without time zone NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_account_deletion_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletions_status ON account_deletions (status, scheduled_at);
@@ -0,0 +1,32 @@
+-- VIP tiers ranking customers by their spend and the tier of each user --
+CREATE TABLE IF NOT EXISTS vip_tiers
+(
+	id character varying(100) PRIMARY KEY,
+	name character varying(100) NOT NULL UNIQUE,
+	min_spend numeric(15, 2) NOT NULL,
+	discount_percent numeric(5, 2) NOT NULL DEFAULT 0,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+-- User Tiers --
+CREATE TABLE IF NOT EXISTS user_tiers
+(
+	user_id character varying(100) PRIMARY KEY,
+	tier_id character varying(100) NULL,
+	total_spend numeric(15, 2) NOT NULL DEFAULT 0,
+	evaluated_at timestamp without time zone NOT NULL,
+	CONSTRAINT fk_user_tier_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_user_tier_tier FOREIGN KEY (tier_id) REFERENCES vip_tiers(id) ON DELETE SET NULL
+);
+
+-- Default tiers, tiers already managed through /vip-tiers are kept --
+INSERT INTO vip_tiers (id, name, min_spend, discount_percent) VALUES 
+('tier1', 'Silver', 5000000, 3),
+('tier2', 'Gold', 20000000, 5),
+('tier3', 'Platinum', 50000000, 10)
+ON CONFLICT DO NOTHING;
+
+-- Permission to manage tiers, granted to ADMIN_ROLE by the migrate command like every permission --
+INSERT INTO permissions (id, description) VALUES ('vip_tier:write', 'Manage VIP tiers')
+ON CONFLICT (id) DO NOTHING;
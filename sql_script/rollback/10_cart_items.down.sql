@@ -0,0 +1,20 @@
+-- Rebuild the JSON column of carts from cart_items --
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS items text NOT NULL DEFAULT '[]';
+
+UPDATE carts c SET items = COALESCE((
+	SELECT json_agg(json_build_object(
+		'product_id', ci.product_id,
+		'name', p.name,
+		'image_url', p.image,
+		'quantity', ci.quantity,
+		'price', p.price::numeric,
+		'currency', p.currency
+	) ORDER BY ci.created_at)::text
+	FROM cart_items ci
+	JOIN products p ON p.id = ci.product_id
+	WHERE ci.cart_id = c.id
+), '[]');
+
+ALTER TABLE carts ALTER COLUMN items DROP DEFAULT;
+
+DROP TABLE IF EXISTS cart_items;
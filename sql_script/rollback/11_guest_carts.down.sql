@@ -0,0 +1,6 @@
+-- Remove guest carts, guests have to log in before adding items --
+DROP TABLE IF EXISTS guest_cart_items;
+
+DROP INDEX IF EXISTS idx_guest_carts_expired_at;
+
+DROP TABLE IF EXISTS guest_carts;
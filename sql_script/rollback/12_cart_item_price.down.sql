@@ -0,0 +1,4 @@
+-- Remove acknowledged price of cart lines --
+ALTER TABLE cart_items DROP COLUMN IF EXISTS unit_price;
+
+ALTER TABLE guest_cart_items DROP COLUMN IF EXISTS unit_price;
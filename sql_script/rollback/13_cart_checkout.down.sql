@@ -0,0 +1,15 @@
+-- Remove last checkout of carts, voucher owners and abandoned cart reminders --
+DROP TABLE IF EXISTS cart_reminder_opt_outs;
+
+DROP INDEX IF EXISTS ux_cart_reminders_token_hash;
+DROP INDEX IF EXISTS ux_cart_reminders_user_revision;
+
+DROP TABLE IF EXISTS cart_reminders;
+
+DROP INDEX IF EXISTS idx_vouchers_user_id;
+
+ALTER TABLE vouchers DROP COLUMN IF EXISTS user_id;
+
+DROP INDEX IF EXISTS idx_carts_updated_at;
+
+ALTER TABLE carts DROP COLUMN IF EXISTS checked_out_at;
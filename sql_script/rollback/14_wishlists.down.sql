@@ -0,0 +1,5 @@
+-- Remove wishlists and saved for later lists --
+DROP INDEX IF EXISTS idx_wishlists_product_id;
+DROP INDEX IF EXISTS ux_wishlists_user_product;
+
+DROP TABLE IF EXISTS wishlists;
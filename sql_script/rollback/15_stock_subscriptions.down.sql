@@ -0,0 +1,6 @@
+-- Remove back in stock subscriptions --
+DROP INDEX IF EXISTS idx_stock_subscriptions_queued;
+DROP INDEX IF EXISTS idx_stock_subscriptions_product_status;
+DROP INDEX IF EXISTS ux_stock_subscriptions_user_product;
+
+DROP TABLE IF EXISTS stock_subscriptions;
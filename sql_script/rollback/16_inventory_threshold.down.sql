@@ -0,0 +1,4 @@
+-- Remove reorder thresholds of products --
+ALTER TABLE product_inventories DROP COLUMN IF EXISTS low_stock_at;
+ALTER TABLE product_inventories DROP COLUMN IF EXISTS safety_stock;
+ALTER TABLE product_inventories DROP COLUMN IF EXISTS reorder_point;
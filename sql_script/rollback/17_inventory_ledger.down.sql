@@ -0,0 +1,9 @@
+-- Remove ledger columns, correction entries are kept as transactions of action correction --
+DELETE FROM product_inventory_transactions WHERE id LIKE 'opening-%';
+
+DROP INDEX IF EXISTS idx_inventory_tx_product_id;
+DROP INDEX IF EXISTS ux_inventory_tx_corrects_id;
+
+ALTER TABLE product_inventory_transactions DROP COLUMN IF EXISTS corrects_id;
+ALTER TABLE product_inventory_transactions DROP COLUMN IF EXISTS balance;
+ALTER TABLE product_inventory_transactions DROP COLUMN IF EXISTS quantity_change;
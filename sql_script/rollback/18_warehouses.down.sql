@@ -0,0 +1,11 @@
+-- Remove warehouses, transfer entries are kept as they sum up to zero for a product --
+DROP INDEX IF EXISTS idx_inventory_tx_order_id;
+DROP INDEX IF EXISTS idx_inventory_tx_warehouse_id;
+
+ALTER TABLE product_inventory_transactions DROP COLUMN IF EXISTS order_id;
+ALTER TABLE product_inventory_transactions DROP COLUMN IF EXISTS transfer_id;
+ALTER TABLE product_inventory_transactions DROP COLUMN IF EXISTS warehouse_balance;
+ALTER TABLE product_inventory_transactions DROP COLUMN IF EXISTS warehouse_id;
+
+DROP TABLE IF EXISTS warehouse_inventories;
+DROP TABLE IF EXISTS warehouses;
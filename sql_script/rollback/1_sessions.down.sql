@@ -0,0 +1,4 @@
+-- Remove sessions, users have to log in again --
+DROP INDEX IF EXISTS idx_sessions_user_id;
+
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,5 @@
+-- Remove account lockout, locked accounts are unlocked --
+DROP TABLE IF EXISTS account_lockout_events;
+
+ALTER TABLE user_securities DROP COLUMN IF EXISTS lock_count;
+ALTER TABLE user_securities DROP COLUMN IF EXISTS locked_until;
@@ -0,0 +1,3 @@
+-- Remove TOTP second factor, users log in with password only --
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_totps;
@@ -0,0 +1,2 @@
+-- Remove provider identities, social login users keep their accounts --
+DROP TABLE IF EXISTS identities;
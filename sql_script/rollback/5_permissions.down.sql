@@ -0,0 +1,3 @@
+-- Remove permission catalog, staff routes are denied until it is restored --
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
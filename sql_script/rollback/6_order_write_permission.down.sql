@@ -0,0 +1,2 @@
+-- Remove order:write, staff can't create or update orders of other users until it is restored --
+DELETE FROM permissions WHERE id = 'order:write';
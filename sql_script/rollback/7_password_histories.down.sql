@@ -0,0 +1,4 @@
+-- Remove password histories, previous passwords can be reused --
+DROP INDEX IF EXISTS idx_password_histories_user;
+
+DROP TABLE IF EXISTS password_histories;
@@ -0,0 +1,4 @@
+-- Remove account deletion requests, pending deletions are dropped --
+DROP INDEX IF EXISTS idx_account_deletions_status;
+
+DROP TABLE IF EXISTS account_deletions;
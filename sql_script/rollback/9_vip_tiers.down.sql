@@ -0,0 +1,5 @@
+-- Remove VIP tiers, checkout is no longer discounted by tier --
+DELETE FROM permissions WHERE id = 'vip_tier:write';
+
+DROP TABLE IF EXISTS user_tiers;
+DROP TABLE IF EXISTS vip_tiers;
@@ -46,6 +46,23 @@ CREATE TABLE IF NOT EXISTS roles (
     updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
 ); 
 
+-- Permissions --
+CREATE TABLE IF NOT EXISTS permissions (
+	id character varying(100) PRIMARY KEY, -- Code such as product:write
+	description character varying(255) NOT NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Role Permissions --
+CREATE TABLE IF NOT EXISTS role_permissions (
+	role_id character varying(100) NOT NULL,
+	permission_id character varying(100) NOT NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (role_id, permission_id),
+	CONSTRAINT fk_role_permission_role FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+	CONSTRAINT fk_role_permission_permission FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
 -- Users --
 CREATE TABLE IF NOT EXISTS users
 (
@@ -75,9 +92,131 @@ CREATE TABLE IF NOT EXISTS user_securities
 	action_token character varying(100) NULL,
 	fail_access int DEFAULT 0,
 	last_fail timestamp without time zone NULL,
+	locked_until timestamp without time zone NULL,
+	lock_count int DEFAULT 0,
 	CONSTRAINT fk_security_user FOREIGN KEY (id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+-- Identities --
+CREATE TABLE IF NOT EXISTS identities
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	provider character varying(30) NOT NULL,
+	subject character varying(255) NOT NULL,
+	email character varying(100),
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	last_login_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT uq_identity_provider_subject UNIQUE (provider, subject),
+	CONSTRAINT fk_identity_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- User TOTPs --
+CREATE TABLE IF NOT EXISTS user_totps
+(
+	user_id character varying(100) PRIMARY KEY,
+	secret character varying(100) NOT NULL,
+	is_enabled bool NOT NULL DEFAULT FALSE,
+	last_used_step bigint NOT NULL DEFAULT 0,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_totp_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- MFA Recovery Codes --
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	code_hash character varying(100) NOT NULL,
+	used_at timestamp without time zone NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_recovery_code_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Password Histories --
+CREATE TABLE IF NOT EXISTS password_histories
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	password_hash character varying(255) NOT NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_password_history_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_histories_user ON password_histories (user_id, created_at DESC);
+
+-- Account Lockout Events --
+CREATE TABLE IF NOT EXISTS account_lockout_events
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	action character varying(30) NOT NULL,
+	actor_id character varying(100),
+	ip_address character varying(100),
+	locked_until timestamp without time zone NULL,
+	note text,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_lockout_event_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Sessions --
+CREATE TABLE IF NOT EXISTS sessions
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	refresh_token_hash character varying(100) NOT NULL,
+	access_token_id character varying(100) NOT NULL,
+	access_expired_at timestamp without time zone NOT NULL,
+	device_name character varying(100),
+	ip_address character varying(100),
+	user_agent text,
+	is_revoked bool NOT NULL DEFAULT FALSE,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	last_used_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	expired_at timestamp without time zone NOT NULL,
+	CONSTRAINT fk_session_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+
+-- Account Deletions --
+CREATE TABLE IF NOT EXISTS account_deletions
+(
+	id character varying(100) PRIMARY KEY,
+	user_id character varying(100) NOT NULL,
+	status character varying(30) NOT NULL,
+	scheduled_at timestamp without time zone NOT NULL,
+	completed_at timestamp without time zone NULL,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_account_deletion_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletions_status ON account_deletions (status, scheduled_at);
+
+-- VIP Tiers --
+CREATE TABLE IF NOT EXISTS vip_tiers
+(
+	id character varying(100) PRIMARY KEY,
+	name character varying(100) NOT NULL UNIQUE,
+	min_spend numeric(15, 2) NOT NULL,
+	discount_percent numeric(5, 2) NOT NULL DEFAULT 0,
+	created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+-- User Tiers --
+CREATE TABLE IF NOT EXISTS user_tiers
+(
+	user_id character varying(100) PRIMARY KEY,
+	tier_id character varying(100) NULL,
+	total_spend numeric(15, 2) NOT NULL DEFAULT 0,
+	evaluated_at timestamp without time zone NOT NULL,
+	CONSTRAINT fk_user_tier_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_user_tier_tier FOREIGN KEY (tier_id) REFERENCES vip_tiers(id) ON DELETE SET NULL
+);
+
 
 -- Orders -- 
 CREATE TABLE IF NOT EXISTS orders 
@@ -123,9 +262,12 @@ CREATE TABLE IF NOT EXISTS vouchers (
     allowed_product_ids TEXT[],
     expired_at TIMESTAMPTZ NOT NULL,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id character varying(100), -- Owner of a personal voucher, e.g. of a cart reminder, NULL for every user
+    CONSTRAINT fk_voucher_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 CREATE INDEX idx_vouchers_code ON vouchers (code);
+CREATE INDEX idx_vouchers_user_id ON vouchers (user_id);
 CREATE INDEX idx_vouchers_expired_at ON vouchers (expired_at);
 CREATE INDEX idx_vouchers_active_status ON vouchers (active_status);
 CREATE INDEX idx_vouchers_allowed_category_ids ON vouchers USING GIN (allowed_category_ids);
@@ -148,20 +290,148 @@ CREATE TABLE IF NOT EXISTS feedbacks (
 -- Carts --
 CREATE TABLE IF NOT EXISTS carts (
     id character varying(100) PRIMARY KEY,
-    items character varying(500) NOT NULL,
 	expired_at TIMESTAMPTZ NOT NULL,
+	checked_out_at TIMESTAMPTZ, -- Last checkout through the cart, no reminder until the cart changes again
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	CONSTRAINT fk_cart_user FOREIGN KEY (id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+CREATE INDEX IF NOT EXISTS idx_carts_updated_at ON carts (updated_at);
+
+-- Cart Items --
+CREATE TABLE IF NOT EXISTS cart_items (
+    id character varying(100) PRIMARY KEY,
+    cart_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    quantity integer NOT NULL CHECK (quantity > 0),
+    unit_price numeric(15, 2) NOT NULL DEFAULT 0, -- Price acknowledged by the shopper
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_cart_item_cart FOREIGN KEY (cart_id) REFERENCES carts(id) ON DELETE CASCADE,
+	CONSTRAINT fk_cart_item_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cart_items_cart_product ON cart_items (cart_id, product_id);
+
+-- Cart Reminders --
+CREATE TABLE IF NOT EXISTS cart_reminders (
+    id character varying(100) PRIMARY KEY,
+    user_id character varying(100) NOT NULL,
+    cart_updated_at TIMESTAMPTZ NOT NULL, -- Cart revision the reminder was sent for
+    token_hash character varying(100) NOT NULL, -- SHA-256 of the unsubscribe token
+    voucher_id character varying(100),
+    reminded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_cart_reminder_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_cart_reminder_voucher FOREIGN KEY (voucher_id) REFERENCES vouchers(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cart_reminders_user_revision ON cart_reminders (user_id, cart_updated_at);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cart_reminders_token_hash ON cart_reminders (token_hash);
+
+-- Users unsubscribed from cart reminders --
+CREATE TABLE IF NOT EXISTS cart_reminder_opt_outs (
+    user_id character varying(100) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_cart_reminder_opt_out_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Wishlists --
+CREATE TABLE IF NOT EXISTS wishlists (
+    id character varying(100) PRIMARY KEY,
+    user_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    list_type character varying(100) NOT NULL DEFAULT 'WISHLIST', -- WISHLIST or SAVED_FOR_LATER
+    notify_back_in_stock boolean NOT NULL DEFAULT FALSE,
+    notify_price_drop boolean NOT NULL DEFAULT FALSE,
+    notified_price numeric(15, 2) NOT NULL DEFAULT 0, -- Price when added or last notified
+    is_in_stock boolean NOT NULL DEFAULT TRUE, -- Stock state when added or last notified
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_wishlist_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_wishlist_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_wishlists_user_product ON wishlists (user_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_wishlists_product_id ON wishlists (product_id);
+
+-- Back In Stock Subscriptions --
+CREATE TABLE IF NOT EXISTS stock_subscriptions (
+    id character varying(100) PRIMARY KEY,
+    user_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    status character varying(100) NOT NULL DEFAULT 'WAITING', -- WAITING, QUEUED, NOTIFIED or FAILED
+    attempts integer NOT NULL DEFAULT 0,
+    queued_at TIMESTAMPTZ,
+    notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_stock_subscription_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CONSTRAINT fk_stock_subscription_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_stock_subscriptions_user_product ON stock_subscriptions (user_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_product_status ON stock_subscriptions (product_id, status);
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_queued ON stock_subscriptions (queued_at) WHERE status = 'QUEUED';
+
+-- Guest Carts --
+CREATE TABLE IF NOT EXISTS guest_carts (
+    id character varying(100) PRIMARY KEY, -- SHA-256 of the cart token
+	expired_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guest_carts_expired_at ON guest_carts (expired_at);
+
+-- Guest Cart Items --
+CREATE TABLE IF NOT EXISTS guest_cart_items (
+    id character varying(100) PRIMARY KEY,
+    cart_id character varying(100) NOT NULL,
+    product_id character varying(100) NOT NULL,
+    quantity integer NOT NULL CHECK (quantity > 0),
+    unit_price numeric(15, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_guest_cart_item_cart FOREIGN KEY (cart_id) REFERENCES guest_carts(id) ON DELETE CASCADE,
+	CONSTRAINT fk_guest_cart_item_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_guest_cart_items_cart_product ON guest_cart_items (cart_id, product_id);
+
 -- Product Inventories -- 
 CREATE TABLE IF NOT EXISTS product_inventories (
     id character varying(100) PRIMARY KEY,
 	current_quantity BIGINT NOT NULL,
+	reorder_point BIGINT NOT NULL DEFAULT 0, -- Low stock at or below this quantity, 0 disables alerts
+	safety_stock BIGINT NOT NULL DEFAULT 0, -- Critical level, not above reorder_point
+	low_stock_at TIMESTAMPTZ, -- Since when the quantity is at or below reorder_point
 	CONSTRAINT fk_inventory_product FOREIGN KEY (id) REFERENCES products(id) ON DELETE CASCADE
 );
 
+-- Warehouses --
+CREATE TABLE IF NOT EXISTS warehouses (
+    id character varying(100) PRIMARY KEY,
+	name character varying(100) NOT NULL,
+	address character varying(255),
+	latitude DOUBLE PRECISION,
+	longitude DOUBLE PRECISION,
+	priority INT NOT NULL DEFAULT 0, -- Lower is picked first when the delivery location is unknown
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Warehouse Inventories, product inventories keep the total of every warehouse --
+CREATE TABLE IF NOT EXISTS warehouse_inventories (
+    product_id character varying(100) NOT NULL,
+	warehouse_id character varying(100) NOT NULL,
+	current_quantity BIGINT NOT NULL DEFAULT 0 CHECK (current_quantity >= 0),
+	PRIMARY KEY (product_id, warehouse_id),
+	CONSTRAINT fk_warehouseInventory_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+	CONSTRAINT fk_warehouseInventory_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses(id)
+);
+
 -- Product Inventory Transactions --
 CREATE TABLE IF NOT EXISTS product_inventory_transactions (
     id character varying(100) PRIMARY KEY,
@@ -172,9 +442,23 @@ CREATE TABLE IF NOT EXISTS product_inventory_transactions (
 	date TIMESTAMPTZ NOT NULL,
 	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
-	CONSTRAINT fk_inventoryTx_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+	quantity_change BIGINT NOT NULL, -- Signed effect on current_quantity, entries are never updated
+	balance BIGINT, -- Quantity after the entry, unknown for entries recorded before the ledger
+	corrects_id character varying(100), -- Entry reversed by this correction
+	warehouse_id character varying(100) NOT NULL,
+	warehouse_balance BIGINT, -- Quantity at the warehouse after the entry
+	transfer_id character varying(100), -- Shared by both entries of a transfer between warehouses
+	order_id character varying(100), -- Order the stock was allocated to or returned from
+	CONSTRAINT fk_inventoryTx_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+	CONSTRAINT fk_inventoryTx_correction FOREIGN KEY (corrects_id) REFERENCES product_inventory_transactions(id),
+	CONSTRAINT fk_inventoryTx_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses(id)
 );
 
+CREATE UNIQUE INDEX IF NOT EXISTS ux_inventory_tx_corrects_id ON product_inventory_transactions (corrects_id) WHERE corrects_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_tx_product_id ON product_inventory_transactions (product_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_inventory_tx_warehouse_id ON product_inventory_transactions (warehouse_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_inventory_tx_order_id ON product_inventory_transactions (order_id) WHERE order_id IS NOT NULL;
+
 -- Shippings --
 CREATE TABLE IF NOT EXISTS shippings (
     id character varying(100) PRIMARY KEY,
@@ -194,6 +478,32 @@ CREATE TABLE IF NOT EXISTS shippings (
 INSERT INTO roles (id, name, active_status) VALUES ('R001', 'Admin', True);
 INSERT INTO roles (id, name, active_status) VALUES ('R003', 'Customer', True);
 
+-- Permissions
+INSERT INTO permissions (id, description) VALUES 
+('user:manage', 'View, unlock and edit other accounts'),
+('role:manage', 'Manage roles and their permissions'),
+('category:write', 'Manage categories'),
+('collection:write', 'Manage collections'),
+('product:write', 'Manage products, including inactive ones'),
+('inventory:adjust', 'Manage product inventory transactions'),
+('order:read', 'View orders of every user'),
+('order:write', 'Create and update orders of every user'),
+('order:refund', 'Set orders and payments as refunded'),
+('payment:read', 'View payments of every user'),
+('payment:write', 'Update payment status, e.g. refunds'),
+('voucher:write', 'Manage vouchers'),
+('vip_tier:write', 'Manage VIP tiers'),
+('system:monitor', 'View cache metrics');
+
+-- Role Permissions: admin is granted every permission
+INSERT INTO role_permissions (role_id, permission_id) SELECT 'R001', id FROM permissions;
+
+-- VIP Tiers
+INSERT INTO vip_tiers (id, name, min_spend, discount_percent) VALUES 
+('tier1', 'Silver', 5000000, 3),
+('tier2', 'Gold', 20000000, 5),
+('tier3', 'Platinum', 50000000, 10);
+
 -- Categories
 INSERT INTO categories (id, name, description) VALUES 
 ('cat1', 'Cues', 'Professional and casual billiard cues'),
@@ -222,13 +532,22 @@ INSERT INTO products (id, category_id, collection_id, name, description, image,
 INSERT INTO product_inventories (id, current_quantity) VALUES
 ('prod1', 100), ('prod2', 100), ('prod3', 100), ('prod4', 100), ('prod5', 100);
 
+-- Warehouses
+INSERT INTO warehouses (id, name, address, latitude, longitude, priority) VALUES
+('main', 'Main warehouse', 'Thu Duc, Ho Chi Minh City', 10.8494, 106.7537, 0),
+('hanoi', 'Hanoi warehouse', 'Long Bien, Hanoi', 21.0362, 105.8940, 1);
+
+-- Warehouse Inventories
+INSERT INTO warehouse_inventories (product_id, warehouse_id, current_quantity) VALUES
+('prod1', 'main', 100), ('prod2', 'main', 100), ('prod3', 'main', 100), ('prod4', 'main', 100), ('prod5', 'main', 100);
+
 -- Product Inventory Transactions
-INSERT INTO product_inventory_transactions (id, product_id, amount, action, note, date) VALUES
-('tx1', 'prod1', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP),
-('tx2', 'prod2', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP),
-('tx3', 'prod3', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP),
-('tx4', 'prod4', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP),
-('tx5', 'prod5', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP);
+INSERT INTO product_inventory_transactions (id, product_id, amount, action, note, date, quantity_change, balance, warehouse_id, warehouse_balance) VALUES
+('tx1', 'prod1', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP, 100, 100, 'main', 100),
+('tx2', 'prod2', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP, 100, 100, 'main', 100),
+('tx3', 'prod3', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP, 100, 100, 'main', 100),
+('tx4', 'prod4', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP, 100, 100, 'main', 100),
+('tx5', 'prod5', 100, 'import', 'Initial stock', CURRENT_TIMESTAMP, 100, 100, 'main', 100);
 
 -- Users
 -- Password shared: hashedpass1
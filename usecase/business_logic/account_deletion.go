@@ -0,0 +1,250 @@
+package businesslogic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	domain_status "sonit_server/constant/domain_status"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils"
+	"time"
+)
+
+// Anonymized values replacing personal data of a deleted account
+const (
+	anonymized_full_name     string = "Deleted user"
+	anonymized_email_pattern string = "deleted-%s@deleted.invalid"
+	anonymized_detail        string = "[deleted]"
+)
+
+// Grace period in mails, e.g. 30 days
+func describeGracePeriod(grace time.Duration) string {
+	if grace >= 24*time.Hour && grace%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", grace/(24*time.Hour))
+	}
+
+	return grace.String()
+}
+
+func toUserProfileResponse(user entity.User) response.UserProfileResponse {
+	return response.UserProfileResponse{
+		UserId:        user.UserId,
+		RoleId:        user.RoleId,
+		FullName:      user.FullName,
+		Email:         user.Email,
+		ProfileAvatar: user.ProfileAvatar,
+		Gender:        user.Gender,
+		IsVip:         user.IsVip,
+		VipCode:       user.VipCode,
+		IsActive:      user.IsActive,
+		IsActivated:   user.IsActivated,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// Collect every order of a user, page by page
+func getAllUserOrders(userId string, orderRepo data_access.IOrderRepo, ctx context.Context) ([]entity.Order, error) {
+	var res []entity.Order
+	for pageNumber, totalPages := 1, 1; pageNumber <= totalPages; pageNumber++ {
+		orders, pages, err := orderRepo.GetOrders(request.GetOrdersRequest{
+			UserId: userId,
+			Request: request.SearchPaginatioRequest{
+				PageNumber: pageNumber,
+				FilterProp: utils.AssignFilterProperty(""),
+				Order:      utils.AssignOrder(""),
+			},
+		}, ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, *orders...)
+		totalPages = pages
+	}
+
+	return res, nil
+}
+
+// Collect every payment of a user, page by page
+func getAllUserPayments(userId string, paymentRepo data_access.IPaymentRepo, ctx context.Context) ([]entity.Payment, error) {
+	var res []entity.Payment
+	for pageNumber, totalPages := 1, 1; pageNumber <= totalPages; pageNumber++ {
+		payments, pages, err := paymentRepo.GetPayments(request.GetPaymentsRequest{
+			UserId: userId,
+			Request: request.SearchPaginatioRequest{
+				PageNumber: pageNumber,
+				FilterProp: utils.AssignFilterProperty(""),
+				Order:      utils.AssignOrder(""),
+			},
+		}, ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, *payments...)
+		totalPages = pages
+	}
+
+	return res, nil
+}
+
+// Gather personal data of a user into an export bundle
+func buildUserDataExport(account entity.User, orderRepo data_access.IOrderRepo, paymentRepo data_access.IPaymentRepo, shippingRepo data_access.IShippingRepo, cartRepo data_access.ICartRepo, productRepo data_access.IProductRepo, inventoryRepo data_access.IProductInventoryRepo, wishlistRepo data_access.IWishlistRepo, userTierRepo data_access.IUserTierRepo, reminderRepo data_access.ICartReminderRepo, subscriptionRepo data_access.IStockSubscriptionRepo, deletionRepo data_access.IAccountDeletionRepo, ctx context.Context) (*response.UserDataExportResponse, error) {
+	var res = response.UserDataExportResponse{
+		Profile:            toUserProfileResponse(account),
+		Orders:             []response.ViewOrderResponse{},
+		Payments:           []entity.Payment{},
+		Addresses:          []response.ShippingResponse{},
+		CartReminders:      []entity.CartReminder{},
+		StockSubscriptions: []entity.StockSubscription{},
+		ExportedAt:         time.Now(),
+	}
+
+	orders, err := getAllUserOrders(account.UserId, orderRepo, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range orders {
+		res.Orders = append(res.Orders, response.ViewOrderResponse{
+			OrderId:     order.OrderId,
+			UserId:      order.UserId,
+			Items:       utils.JsonStringToObject[[]response.CartItem](order.Items),
+			TotalAmount: order.TotalAmount,
+			Currency:    order.Currency,
+			Status:      order.Status,
+			Note:        order.Note,
+			CreatedAt:   order.CreatedAt,
+			UpdatedAt:   order.UpdatedAt,
+		})
+
+		shipping, _, err := shippingRepo.GetShipping(order.OrderId, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if shipping != nil {
+			res.Addresses = append(res.Addresses, response.ShippingResponse{
+				OrderId:        shipping.OrderId,
+				DeliveryCode:   shipping.DeliveryCode,
+				ShippingUnit:   shipping.ShippingUnit,
+				ShippingDetail: utils.JsonStringToObject[response.ShippingDetail](shipping.ShippingDetail),
+				DeliveredAt:    shipping.DeliveredAt,
+				CreatedAt:      shipping.CreatedAt,
+				UpdatedAt:      shipping.UpdatedAt,
+			})
+		}
+	}
+
+	payments, err := getAllUserPayments(account.UserId, paymentRepo, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Payments = append(res.Payments, payments...)
+
+	// Guest carts are merged into the cart on login and removed, their lines are exported with the cart
+	cart, _, err := cartRepo.GetCart(account.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cart != nil {
+		items, err := getCartLines(cart.UserId, cartRepo, productRepo, inventoryRepo, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Cart = &response.ViewCartResponse{
+			UserId:    cart.UserId,
+			Items:     items,
+			ExpiredAt: cart.ExpiredAt,
+		}
+	}
+
+	reminders, err := reminderRepo.GetCartRemindersByUser(account.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res.CartReminders = append(res.CartReminders, *reminders...)
+
+	res.IsCartReminderOptedOut, err = reminderRepo.IsCartReminderOptedOut(account.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Wishlist, err = getWishlistItemsDetail(account.UserId, "", wishlistRepo, productRepo, inventoryRepo, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := subscriptionRepo.GetStockSubscriptions(account.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res.StockSubscriptions = append(res.StockSubscriptions, *subscriptions...)
+
+	res.VipTier, err = userTierRepo.GetUserTier(account.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Deletion, err = deletionRepo.GetScheduledAccountDeletion(account.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// Replace personal data of the account and end its sessions, orders and payments are kept with anonymized shipping details
+func anonymizeAccount(deletion entity.AccountDeletion, logger *log.Logger, userRepo data_access.IUserRepo, sessionRepo data_access.ISessionRepo, deletionRepo data_access.IAccountDeletionRepo, ctx context.Context) error {
+	account, err := userRepo.GetUser(deletion.UserId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if account == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetUserTable()))
+	}
+
+	var curTime = time.Now()
+
+	// Login with the old password is impossible once replaced by hash of a random value
+	unusablePassword, err := utils.ToHashString(utils.GenerateId(), logger)
+	if err != nil {
+		return err
+	}
+
+	// Tokens of active sessions are revoked before sessions are removed
+	if err := revokeUserSessions(deletion.UserId, sessionRepo, logger, ctx); err != nil {
+		return err
+	}
+
+	account.FullName = anonymized_full_name
+	account.Email = fmt.Sprintf(anonymized_email_pattern, account.UserId)
+	account.Password = unusablePassword
+	account.ProfileAvatar = ""
+	account.Gender = ""
+	account.UpdatedAt = curTime
+
+	deletion.Status = domain_status.ACCOUNT_DELETION_COMPLETED
+	deletion.CompletedAt = &curTime
+	deletion.UpdatedAt = curTime
+
+	return deletionRepo.AnonymizeAccount(deletion, *account, utils.ObjectToJsonString(response.ShippingDetail{
+		RecipientName: anonymized_detail,
+		Address:       anonymized_detail,
+		PhoneNumber:   anonymized_detail,
+	}), ctx)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	domain_status "sonit_server/constant/domain_status"
 	"sonit_server/constant/noti"
 	repo "sonit_server/data_access" // Category data access ~~ Category repository
 	"sonit_server/data_access/db"
@@ -17,14 +18,18 @@ import (
 	"sonit_server/model/dto/response"
 	entity "sonit_server/model/entity"
 	"sonit_server/utils"
-	"strings"
+	"sonit_server/utils/config"
 	"time"
 )
 
 type cartService struct {
+	userRepo      data_access.IUserRepo
 	productRepo   data_access.IProductRepo
 	inventoryRepo data_access.IProductInventoryRepo
+	voucherRepo   data_access.IVoucherRepo
 	cartRepo      data_access.ICartRepo
+	guestCartRepo data_access.IGuestCartRepo
+	reminderRepo  data_access.ICartReminderRepo
 	logger        *log.Logger
 }
 
@@ -44,15 +49,22 @@ func GenerateCartService() (business_logic.ICartService, error) {
 
 func InitializeCartService(db *sql.DB, logger *log.Logger) business_logic.ICartService {
 	return &cartService{
-		productRepo:   repo.InitializeProductRepo(db, logger),
+		userRepo:      repo.InitializeUserRepo(db, logger),
+		productRepo:   repo.InitializeCachedProductRepo(db, logger),
 		inventoryRepo: repo.InitializeProductInventoryRepo(db, logger),
+		voucherRepo:   repo.InitializeCachedVoucherRepo(db, logger),
 		cartRepo:      repo.InitializeCartRepo(db, logger),
+		guestCartRepo: repo.InitializeGuestCartRepo(db, logger),
+		reminderRepo:  repo.InitializeCartReminderRepo(db, logger),
 		logger:        logger,
 	}
 }
 
 var cart_cnn *sql.DB
 
+// Page size of guest cart, same as cart of users
+const items_in_guest_cart_limit int = 10
+
 // AddItemToCart implements businesslogic.ICartService.
 func (c *cartService) AddItemToCart(req request.AddItemToCartRequest, ctx context.Context) error {
 	// _, cancel := context.WithCancel(ctx)
@@ -113,7 +125,7 @@ func (c *cartService) AddItemToCart(req request.AddItemToCartRequest, ctx contex
 		return err
 	}
 
-	if product == nil {
+	if product == nil || !product.ActiveStatus {
 		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
@@ -126,67 +138,34 @@ func (c *cartService) AddItemToCart(req request.AddItemToCartRequest, ctx contex
 		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
-	cart, _, err := c.cartRepo.GetCart(req.Request.UserId, ctx)
-	if err != nil {
-		return err
-	}
-
-	if product == nil {
-		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
-	}
-
-	var structItems []response.CartItem // Items in cart
-	if cart != nil {
-		structItems = utils.JsonStringToObject[[]response.CartItem](cart.Items)
-	}
-
-	var isFound bool = false
-	var totalCartItemQuantity int = req.Quantity // Variable to store the total quantity of the product in user's cart
-	for index, item := range structItems {
-		// Item already in cart
-		if item.ProductId == req.Request.ProductId {
-			isFound = true
-			totalCartItemQuantity += structItems[index].Quantity // Added the current quantity of cart to the total variable
-			structItems[index].Quantity += req.Quantity
-			break
-		}
+	var curTime time.Time = time.Now()
+	var cart = entity.Cart{
+		UserId:    req.Request.UserId,
+		ExpiredAt: curTime.AddDate(0, 0, 7),
+		CreatedAt: curTime,
+		UpdatedAt: curTime,
 	}
 
-	// Cart not existed or item not in cart yet?
-	if cart == nil || !isFound {
-		structItems = append(structItems, response.CartItem{
-			ProductId: req.Request.ProductId,
-			Name:      product.ProductName,
-			ImageUrl:  product.Image,
-			Quantity:  req.Quantity,
-			Price:     float64(product.Price),
-			Currency:  product.Currency,
-		})
+	// Quantity of the line is checked against inventory in the same statement that adds it
+	isAdded, err := c.cartRepo.AddCartItem(cart, entity.CartItem{
+		CartItemId: utils.GenerateId(),
+		CartId:     req.Request.UserId,
+		ProductId:  req.Request.ProductId,
+		Quantity:   req.Quantity,
+		UnitPrice:  product.Price,
+		CreatedAt:  curTime,
+		UpdatedAt:  curTime,
+	}, int(inventory.CurrentQuantity), ctx)
+	if err != nil {
+		return err
 	}
 
 	// If total items in cart bigger than the actual quantity in inventory
-	if totalCartItemQuantity > int(inventory.CurrentQuantity) {
-		return fmt.Errorf(noti.ITEM_OUT_OF_STOCK_WARN_MSG, totalCartItemQuantity)
-	}
-
-	var itemsStr string = utils.ObjectToJsonString(structItems)
-	var curTime time.Time = time.Now()
-	var expiredPeriod time.Time = curTime.AddDate(0, 0, 7)
-	if cart == nil {
-		return c.cartRepo.CreateCart(entity.Cart{
-			UserId:    req.Request.UserId,
-			Items:     itemsStr,
-			CreatedAt: curTime,
-			UpdatedAt: curTime,
-			ExpiredAt: expiredPeriod,
-		}, ctx)
+	if !isAdded {
+		return fmt.Errorf(noti.ITEM_OUT_OF_STOCK_WARN_MSG, req.Quantity)
 	}
 
-	cart.UpdatedAt = curTime
-	cart.ExpiredAt = expiredPeriod
-	cart.Items = itemsStr
-
-	return c.cartRepo.UpdateCart(*cart, ctx)
+	return nil
 }
 
 // EditItemInCart implements businesslogic.ICartService.
@@ -204,34 +183,80 @@ func (c *cartService) EditItemInCart(req request.EditItemInCartRequest, ctx cont
 	}
 
 	// Cart not existed or items not available in cart
-	if cart == nil || req.Quantity > int(inventory.CurrentQuantity) {
+	if cart == nil || inventory == nil || req.Quantity < 1 || req.Quantity > int(inventory.CurrentQuantity) {
 		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
-	var structItems []response.CartItem = utils.JsonStringToObject[[]response.CartItem](cart.Items) // Items in cart
-	var isFound bool = false
-	for index, item := range structItems {
-		// Item already in cart
-		if item.ProductId == req.Request.ProductId {
-			isFound = true
-			structItems[index].Quantity = req.Quantity
-			break
+	var curTime time.Time = time.Now()
+
+	cart.UpdatedAt = curTime
+	cart.ExpiredAt = curTime.AddDate(0, 0, 7)
+
+	return c.cartRepo.EditCartItem(*cart, entity.CartItem{
+		CartId:    cart.UserId,
+		ProductId: req.Request.ProductId,
+		Quantity:  req.Quantity,
+		UpdatedAt: curTime,
+	}, ctx)
+}
+
+// AcknowledgeCartChanges implements businesslogic.ICartService.
+// Lines are revised to current prices, capped by stock and removed if unavailable. Returns the lines before revising
+func (c *cartService) AcknowledgeCartChanges(id string, ctx context.Context) ([]response.CartLineResponse, error) {
+	defer closeCnn(cart_cnn)
+
+	cart, _, err := c.cartRepo.GetCart(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cart == nil {
+		return nil, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	lines, err := getCartLines(cart.UserId, c.cartRepo, c.productRepo, c.inventoryRepo, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes = []response.CartLineResponse{}
+	var items []entity.CartItem
+	for _, line := range lines {
+		if line.Status == domain_status.CART_LINE_AVAILABLE {
+			continue
 		}
+
+		var item = entity.CartItem{
+			ProductId: line.ProductId,
+			Quantity:  line.Quantity,
+			UnitPrice: line.Price,
+		}
+
+		switch line.Status {
+		case domain_status.CART_LINE_UNAVAILABLE:
+			item.Quantity = 0
+		case domain_status.CART_LINE_INSUFFICIENT_STOCK:
+			item.Quantity = int(line.AvailableQuantity)
+		}
+
+		changes = append(changes, line)
+		items = append(items, item)
 	}
 
-	// Product not found in cart
-	if !isFound {
-		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	if len(items) == 0 {
+		return changes, nil
 	}
 
-	var itemsStr string = utils.ObjectToJsonString(structItems)
 	var curTime time.Time = time.Now()
 
 	cart.UpdatedAt = curTime
 	cart.ExpiredAt = curTime.AddDate(0, 0, 7)
-	cart.Items = itemsStr
 
-	return c.cartRepo.UpdateCart(*cart, ctx)
+	if err := c.cartRepo.ReviseCartItems(*cart, items, ctx); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
 }
 
 // RemoveItem implements businesslogic.ICartService.
@@ -243,19 +268,11 @@ func (c *cartService) RemoveItem(req request.RemoveItemFromCartRequest, ctx cont
 		return err
 	}
 
-	if cart == nil || !strings.Contains(cart.Items, req.ProductId) {
+	if cart == nil {
 		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
-	var items []response.CartItem = utils.JsonStringToObject[[]response.CartItem](cart.Items)
-	for index, product := range items {
-		if product.ProductId == req.ProductId {
-			cart.Items = utils.ObjectToJsonString(append(items[:index], items[index+1:]...))
-			break
-		}
-	}
-
-	return c.cartRepo.UpdateCart(*cart, ctx)
+	return c.cartRepo.RemoveCartItem(cart.UserId, req.ProductId, ctx)
 }
 
 // ViewCartDetail implements businesslogic.ICartService.
@@ -273,9 +290,12 @@ func (c *cartService) ViewCartDetail(id string, pageNumber int, ctx context.Cont
 
 	//var maxRecords int = limitRecords * pageNumber
 
-	var items []response.CartItem
-	if cart != nil && cart.Items != "" {
-		items = utils.JsonStringToObject[[]response.CartItem](cart.Items)
+	var items = []response.CartLineResponse{}
+	if cart != nil {
+		items, err = getCartLines(cart.UserId, c.cartRepo, c.productRepo, c.inventoryRepo, ctx)
+		if err != nil {
+			return response.PaginationDataResponse{}, err
+		}
 	}
 
 	// [maxRecords-limitRecords : maxRecords]
@@ -285,3 +305,227 @@ func (c *cartService) ViewCartDetail(id string, pageNumber int, ctx context.Cont
 		TotalPages: int(math.Ceil(float64(len(items)) / float64(limitRecords))),
 	}, nil
 }
+
+// ViewGuestCart implements businesslogic.ICartService.
+func (c *cartService) ViewGuestCart(cartToken string, pageNumber int, ctx context.Context) (response.PaginationDataResponse, error) {
+	if pageNumber <= 0 {
+		pageNumber = 1
+	}
+
+	defer closeCnn(cart_cnn)
+
+	cart, err := getGuestCart(cartToken, c.guestCartRepo, ctx)
+	if err != nil {
+		return response.PaginationDataResponse{}, err
+	}
+
+	var items = []response.CartLineResponse{}
+	if cart != nil {
+		lines, err := c.guestCartRepo.GetGuestCartItems(cart.CartId, ctx)
+		if err != nil {
+			return response.PaginationDataResponse{}, err
+		}
+
+		items, err = revalidateCartItems(*lines, c.productRepo, c.inventoryRepo, ctx)
+		if err != nil {
+			return response.PaginationDataResponse{}, err
+		}
+	}
+
+	return response.PaginationDataResponse{
+		Data:       items,
+		PageNumber: pageNumber,
+		TotalPages: int(math.Ceil(float64(len(items)) / float64(items_in_guest_cart_limit))),
+	}, nil
+}
+
+// AddItemToGuestCart implements businesslogic.ICartService.
+// A new cart and token are created if the token is empty, unknown or expired
+func (c *cartService) AddItemToGuestCart(req request.GuestCartItemRequest, ctx context.Context) (*response.GuestCartTokenResponse, error) {
+	defer closeCnn(cart_cnn)
+
+	if req.Quantity < 1 {
+		return nil, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	product, err := c.productRepo.GetProductById(req.ProductId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory, err := c.inventoryRepo.GetProductInventory(req.ProductId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil || !product.ActiveStatus || inventory == nil {
+		return nil, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	cart, err := getGuestCart(req.CartToken, c.guestCartRepo, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cartToken = req.CartToken
+	if cart == nil {
+		if cartToken, err = utils.GenerateOpaqueToken(); err != nil {
+			c.logger.Println("Error while generating guest cart token - " + err.Error())
+			return nil, errors.New(noti.INTERNALL_ERR_MSG)
+		}
+	}
+
+	var curTime time.Time = time.Now()
+	var guestCart = entity.GuestCart{
+		CartId:    utils.ToSHA256String(cartToken),
+		ExpiredAt: curTime.Add(config.Get().Cart.GuestTTL),
+		CreatedAt: curTime,
+		UpdatedAt: curTime,
+	}
+
+	isAdded, err := c.guestCartRepo.AddGuestCartItem(guestCart, entity.CartItem{
+		CartItemId: utils.GenerateId(),
+		CartId:     guestCart.CartId,
+		ProductId:  req.ProductId,
+		Quantity:   req.Quantity,
+		UnitPrice:  product.Price,
+		CreatedAt:  curTime,
+		UpdatedAt:  curTime,
+	}, int(inventory.CurrentQuantity), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdded {
+		return nil, fmt.Errorf(noti.ITEM_OUT_OF_STOCK_WARN_MSG, req.Quantity)
+	}
+
+	return &response.GuestCartTokenResponse{
+		CartToken: cartToken,
+		ExpiredAt: guestCart.ExpiredAt,
+	}, nil
+}
+
+// EditItemInGuestCart implements businesslogic.ICartService.
+func (c *cartService) EditItemInGuestCart(req request.GuestCartItemRequest, ctx context.Context) error {
+	defer closeCnn(cart_cnn)
+
+	cart, err := getGuestCart(req.CartToken, c.guestCartRepo, ctx)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := c.inventoryRepo.GetProductInventory(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if cart == nil || inventory == nil || req.Quantity < 1 || req.Quantity > int(inventory.CurrentQuantity) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	var curTime time.Time = time.Now()
+
+	cart.UpdatedAt = curTime
+	cart.ExpiredAt = curTime.Add(config.Get().Cart.GuestTTL)
+
+	return c.guestCartRepo.EditGuestCartItem(*cart, entity.CartItem{
+		CartId:    cart.CartId,
+		ProductId: req.ProductId,
+		Quantity:  req.Quantity,
+		UpdatedAt: curTime,
+	}, ctx)
+}
+
+// RemoveItemFromGuestCart implements businesslogic.ICartService.
+func (c *cartService) RemoveItemFromGuestCart(req request.GuestCartItemRequest, ctx context.Context) error {
+	defer closeCnn(cart_cnn)
+
+	cart, err := getGuestCart(req.CartToken, c.guestCartRepo, ctx)
+	if err != nil {
+		return err
+	}
+
+	if cart == nil {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	return c.guestCartRepo.RemoveGuestCartItem(cart.CartId, req.ProductId, ctx)
+}
+
+// RemoveExpiredGuestCarts implements businesslogic.ICartService.
+func (c *cartService) RemoveExpiredGuestCarts(ctx context.Context) (int, error) {
+	defer closeCnn(cart_cnn)
+	return c.guestCartRepo.RemoveExpiredGuestCarts(time.Now(), ctx)
+}
+
+// SendAbandonedCartReminders implements businesslogic.ICartService.
+func (c *cartService) SendAbandonedCartReminders(ctx context.Context) (int, error) {
+	defer closeCnn(cart_cnn)
+
+	carts, err := c.cartRepo.GetAbandonedCarts(time.Now().Add(-config.Get().Cart.AbandonedAfter), ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var res int
+	for _, cart := range *carts {
+		isSent, err := sendCartReminder(cart, c.logger, c.userRepo, c.cartRepo, c.productRepo, c.inventoryRepo, c.voucherRepo, c.reminderRepo, ctx)
+		if err != nil {
+			c.logger.Println("Reminder of cart " + cart.UserId + " failed - " + err.Error())
+			continue
+		}
+
+		if isSent {
+			res++
+		}
+	}
+
+	return res, nil
+}
+
+// RemoveExpiredCarts implements businesslogic.ICartService.
+func (c *cartService) RemoveExpiredCarts(ctx context.Context) (int, error) {
+	defer closeCnn(cart_cnn)
+	return c.cartRepo.RemoveExpiredCarts(time.Now(), ctx)
+}
+
+// UnsubscribeCartReminders implements businesslogic.ICartService.
+// Token comes from the unsubscribe link of a reminder mail
+func (c *cartService) UnsubscribeCartReminders(token string, ctx context.Context) (string, error) {
+	defer closeCnn(cart_cnn)
+
+	if token == "" {
+		return "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	reminder, err := c.reminderRepo.GetCartReminderByTokenHash(utils.ToSHA256String(token), ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if reminder == nil {
+		return "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	if err := c.reminderRepo.OptOutCartReminders(reminder.UserId, time.Now(), ctx); err != nil {
+		return "", err
+	}
+
+	return noti.CART_REMINDER_UNSUBSCRIBED_MESSAGE, nil
+}
+
+// EditCartReminderSubscription implements businesslogic.ICartService.
+func (c *cartService) EditCartReminderSubscription(req request.CartReminderSubscriptionRequest, ctx context.Context) error {
+	defer closeCnn(cart_cnn)
+
+	if !isEntityExist(c.userRepo, req.UserId, id_type, ctx) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	if req.IsSubscribed {
+		return c.reminderRepo.OptInCartReminders(req.UserId, ctx)
+	}
+
+	return c.reminderRepo.OptOutCartReminders(req.UserId, time.Now(), ctx)
+}
@@ -38,7 +38,7 @@ func GenerateCategoryService() (business_logic.ICategoryService, error) {
 
 func InitializeCategoryService(db *sql.DB, logger *log.Logger) business_logic.ICategoryService {
 	return &categoryservice{
-		categoryRepo: repo.InitializeCategoryRepo(db, logger),
+		categoryRepo: repo.InitializeCachedCategoryRepo(db, logger),
 		logger:       logger,
 	}
 }
@@ -38,7 +38,7 @@ func GenerateCollectionService() (business_logic.ICollectionService, error) {
 
 func InitializeCollectionService(db *sql.DB, logger *log.Logger) business_logic.ICollectionService {
 	return &collectionService{
-		collectionRepo: repo.InitializeCollectionRepo(db, logger),
+		collectionRepo: repo.InitializeCachedCollectionRepo(db, logger),
 		logger:         logger,
 	}
 }
@@ -4,17 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
-	"os"
+	"math"
+	"net/url"
+	"slices"
 	action_type "sonit_server/constant/action_type"
-	"sonit_server/constant/env"
+	domain_status "sonit_server/constant/domain_status"
 	mail_const "sonit_server/constant/mail_const"
 	"sonit_server/constant/noti"
 	page_url "sonit_server/constant/page_url"
+	"sonit_server/constant/permission"
+	wishlist_type "sonit_server/constant/wishlist_type"
+	"sonit_server/data_access/cache"
 	data_access "sonit_server/interface/data_access"
 	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
 	entity "sonit_server/model/entity"
 	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"sonit_server/utils/file"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -26,10 +38,6 @@ const (
 	email_validate string = "EMAIL_VALIDATE"
 )
 
-const (
-	max_fail_attempts int = 5
-)
-
 const (
 	min_length_verify_mail_component_combine int = 3
 )
@@ -40,6 +48,7 @@ const (
 	resetPassType     string = "2"
 	updateProfileType string = "3"
 	verifyType        string = "4"
+	deleteAccountType string = "5"
 )
 
 const (
@@ -48,6 +57,16 @@ const (
 	Export_action       string = "export"
 	Return_action       string = "return"
 	Cancel_order_action string = "cancel"
+	Correction_action   string = "correction"   // Reverses a recorded transaction, never created directly
+	Transfer_out_action string = "transfer_out" // Stock leaving a warehouse for another, never created directly
+	Transfer_in_action  string = "transfer_in"
+	Stocktake_action    string = "stocktake" // Difference between counted and recorded quantities, signed by its quantity change
+)
+
+// Modes of an inventory import
+const (
+	transaction_import_mode string = "transaction"
+	stocktake_import_mode   string = "stocktake"
 )
 
 // Supported filter property
@@ -128,7 +147,7 @@ func closeCnn(cnn *sql.DB) {
 
 // -------------------- USER SERVICE HELPER --------------------
 
-func validateActorAndAffectedAccount(actorId, accountId string, userRepo data_access.IUserRepo, ctx context.Context) error {
+func validateActorAndAffectedAccount(actorId, accountId string, userRepo data_access.IUserRepo, permissionRepo data_access.IPermissionRepo, ctx context.Context) error {
 	// Fetch account
 	account, err := userRepo.GetUser(accountId, ctx)
 
@@ -160,14 +179,64 @@ func validateActorAndAffectedAccount(actorId, accountId string, userRepo data_ac
 		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
-	// Actor is not admin but edit other account's info
-	if actor.RoleId != os.Getenv(env.ADMIN_ROLE) {
+	// Actor has no right to manage other accounts but edit other account's info
+	isAllowed, err := hasPermissions(actor.RoleId, []string{permission.USER_MANAGE}, permissionRepo, ctx)
+	if err != nil {
+		return err
+	}
+
+	if !isAllowed {
 		return errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
 	}
 
 	return nil
 }
 
+// Validate a new password of an account by password policy, breach list and previous passwords, returns its hash
+func preparePassword(account entity.User, password string, historyRepo data_access.IPasswordHistoryRepo, logger *log.Logger, ctx context.Context) (string, error) {
+	if err := utils.ValidatePassword(password, logger); err != nil {
+		return "", err
+	}
+
+	// New account has no previous password
+	if size := config.Get().Password.HistorySize; size > 0 && account.UserId != "" {
+		var REUSED_ERR error = errors.New(noti.PASSWORD_REUSED_WARN_MSG)
+
+		// Current password may be set before history is recorded
+		if account.Password != "" && utils.IsHashStringMatched(password, account.Password) {
+			return "", REUSED_ERR
+		}
+
+		histories, err := historyRepo.GetRecentPasswordHistories(account.UserId, size, ctx)
+		if err != nil {
+			return "", err
+		}
+
+		for _, history := range *histories {
+			if utils.IsHashStringMatched(password, history.PasswordHash) {
+				return "", REUSED_ERR
+			}
+		}
+	}
+
+	return utils.ToHashString(password, logger)
+}
+
+// Remember a password hash set to an account so it can't be reused
+func recordPasswordHistory(userId, passwordHash string, historyRepo data_access.IPasswordHistoryRepo, ctx context.Context) error {
+	var size = config.Get().Password.HistorySize
+	if size <= 0 {
+		return nil
+	}
+
+	return historyRepo.CreatePasswordHistory(entity.PasswordHistory{
+		HistoryId:    utils.GenerateId(),
+		UserId:       userId,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	}, size, ctx)
+}
+
 // Verify if account is existed by fetching record from data sent by request coming supporting 2 fields: email or id
 func verifyAccount(field, validateField string, user *entity.User, repo data_access.IUserRepo, ctx context.Context) error {
 	if field == "" {
@@ -198,37 +267,16 @@ func verifyAccount(field, validateField string, user *entity.User, repo data_acc
 	return res
 }
 
-// Processing with wrong-credentials login case
-func processWrongCredentialsCase(accountId string, securityRepo data_access.IUserSecurityRepo, ctx context.Context) {
-	info, err := securityRepo.GetUserSecurity(accountId, ctx)
-	if err != nil {
-		return
-	}
-
-	if info != nil {
-		// Set fail access and last fail
-		info.FailAccess += 1
-		var curTime time.Time = time.Now()
-		info.LastFail = &curTime
-
-		// Update database
-		securityRepo.EditUserSecurity(*info, ctx)
-	}
-}
-
 // Processing with correct-credentials login case
-func processCorrectCredentialsCase(account entity.User, logger *log.Logger, securityRepo data_access.IUserSecurityRepo, ctx context.Context) (string, string, error) {
-	security, err := securityRepo.GetUserSecurity(account.UserId, ctx)
-	if err != nil {
-		return "", "", err
-	}
-
-	// Account need to be activated or verified as previous fail attempts
-	if !account.IsActivated || security.FailAccess > max_fail_attempts {
+func processCorrectCredentialsCase(account entity.User, security *entity.UserSecurity, totp *entity.UserTotp, req request.LoginRequest, logger *log.Logger, securityRepo data_access.IUserSecurityRepo, sessionRepo data_access.ISessionRepo, guestCartRepo data_access.IGuestCartRepo, ctx context.Context) (string, string, error) {
+	// Account need to be activated
+	if !account.IsActivated {
 		return processAccountVerifyCase(security, account.Email, logger, securityRepo, ctx)
 	}
 
 	var res1, res2 string
+	var err error
+	var isMfaPending bool = account.IsHaveToResetPw == nil && totp != nil && totp.IsEnabled
 
 	// Need to reset password
 	if account.IsHaveToResetPw != nil {
@@ -245,23 +293,112 @@ func processCorrectCredentialsCase(account entity.User, logger *log.Logger, secu
 		}, mailSepChar)
 
 		security.ActionToken = &token // Assigning created token
-	} else {
-		accessToken, refreshToken, err := utils.GenerateTokens(account.Email, account.UserId, account.RoleId, logger)
+	} else if isMfaPending {
+		// Second factor is verified with the challenge token before issuing tokens
+		token, err := utils.GenerateMfaChallengeToken(account.Email, account.UserId, account.RoleId, logger)
+		if err != nil {
+			return "", "", err
+		}
 
+		res1 = action_type.MFA_REQUIRED_TYPE
+		res2 = token
+	} else {
+		res1, res2, err = createLoginSession(account, req, false, logger, sessionRepo, guestCartRepo, ctx)
 		if err != nil {
 			return "", "", err
 		}
+	}
 
-		res1 = accessToken
-		res2 = refreshToken
-		security.AccessToken = &accessToken
-		security.RefreshToken = &refreshToken
+	// Failed attempts and lockout backoff start over, kept until second factor is verified
+	if !isMfaPending {
+		security.FailAccess = 0
+		security.LockCount = 0
 	}
 
 	return res1, res2, securityRepo.EditUserSecurity(*security, ctx)
 }
 
-// Processing to prepare for sending mail in case of account needed to be activated or verified
+// Start a new session of a device and issue its tokens, each login is a new session so other devices stay logged in.
+// Guest cart of the device is merged into the user cart once the session is started
+func createLoginSession(account entity.User, req request.LoginRequest, isMfa bool, logger *log.Logger, sessionRepo data_access.ISessionRepo, guestCartRepo data_access.IGuestCartRepo, ctx context.Context) (string, string, error) {
+	var sessionId = utils.GenerateId()
+	tokens, err := utils.GenerateTokens(account.Email, account.UserId, account.RoleId, sessionId, isMfa, logger)
+	if err != nil {
+		return "", "", err
+	}
+
+	var curTime = time.Now()
+	if err := sessionRepo.CreateSession(entity.Session{
+		SessionId:        sessionId,
+		UserId:           account.UserId,
+		RefreshTokenHash: utils.ToSHA256String(tokens.RefreshToken),
+		AccessTokenId:    tokens.AccessTokenId,
+		AccessExpiredAt:  tokens.AccessExpiredAt,
+		DeviceName:       req.DeviceName,
+		IpAddress:        req.IpAddress,
+		UserAgent:        req.UserAgent,
+		CreatedAt:        curTime,
+		LastUsedAt:       curTime,
+		ExpiredAt:        tokens.RefreshExpiredAt,
+	}, ctx); err != nil {
+		return "", "", err
+	}
+
+	mergeGuestCart(account.UserId, req.CartToken, logger, guestCartRepo, ctx)
+
+	return tokens.AccessToken, tokens.RefreshToken, nil
+}
+
+// Revoke a session along with its last access token so it can't be used until expired
+func revokeSession(session entity.Session, sessionRepo data_access.ISessionRepo, logger *log.Logger, ctx context.Context) error {
+	if err := sessionRepo.RevokeSession(session.SessionId, ctx); err != nil {
+		return err
+	}
+
+	return cache.RevokeToken(session.AccessTokenId, session.AccessExpiredAt, logger)
+}
+
+// Revoke all active sessions of a user
+func revokeUserSessions(userId string, sessionRepo data_access.ISessionRepo, logger *log.Logger, ctx context.Context) error {
+	sessions, err := sessionRepo.GetActiveSessionsByUser(userId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := sessionRepo.RevokeSessionsByUser(userId, ctx); err != nil {
+		return err
+	}
+
+	for _, session := range *sessions {
+		if err := cache.RevokeToken(session.AccessTokenId, session.AccessExpiredAt, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Revoke active sessions of a user except the current one
+func revokeOtherSessions(userId, currentSessionId string, sessionRepo data_access.ISessionRepo, logger *log.Logger, ctx context.Context) error {
+	sessions, err := sessionRepo.GetActiveSessionsByUser(userId, ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range *sessions {
+		if session.SessionId == currentSessionId {
+			continue
+		}
+
+		if err := revokeSession(session, sessionRepo, logger, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Processing to prepare for sending mail in case of account needed to be activated
 func processAccountVerifyCase(security *entity.UserSecurity, email string, logger *log.Logger, secureRepo data_access.IUserSecurityRepo, ctx context.Context) (string, string, error) {
 	var capturedErr error
 	ctx, cancel := context.WithCancel(ctx)
@@ -275,24 +412,12 @@ func processAccountVerifyCase(security *entity.UserSecurity, email string, logge
 	go func() {
 		defer wg.Done()
 
-		var actionType, templatePath, subject string
+		var actionType string = activateType
+		var templatePath string = mail_const.ACCOUNT_REGISTRATION_MAIL_TEMPLATE
+		var subject string = noti.REGISTRATION_ACCOUNT_MAIL_SUBJECT
 
-		if security.FailAccess > max_fail_attempts {
-			actionType = verifyType
-			templatePath = mail_const.ACCOUNT_RECOVERY_MAIL_TEMPLATE
-			subject = noti.VERIFY_ACCOUNT_MAIL_SUBJECT
-
-			res1 = action_type.VERIFY_TYPE
-			res2 = noti.VERIFY_ACCOUNT_MESSAGE
-
-		} else {
-			actionType = activateType
-			templatePath = mail_const.ACCOUNT_REGISTRATION_MAIL_TEMPLATE
-			subject = noti.REGISTRATION_ACCOUNT_MAIL_SUBJECT
-
-			res1 = action_type.ACTIVATE_TYPE
-			res2 = noti.ACTIVATE_ACCOUNT_MESSAGE
-		}
+		res1 = action_type.ACTIVATE_TYPE
+		res2 = noti.ACTIVATE_ACCOUNT_MESSAGE
 
 		// Generate action token
 		token, err := utils.GenerateActionToken(email, security.UserId, "", logger)
@@ -309,7 +434,7 @@ func processAccountVerifyCase(security *entity.UserSecurity, email string, logge
 			mu.Unlock()
 		} else {
 			// Assigning created token
-			*security.ActionToken = token
+			security.ActionToken = &token
 
 			// Send mail
 			if err := utils.SendMail(request.SendMailRequest{
@@ -375,11 +500,113 @@ func processAccountVerifyCase(security *entity.UserSecurity, email string, logge
 	return res1, res2, nil
 }
 
+// Find account of a provider identity. An unknown identity is linked to the account of the same email if provider verified
+// the email, otherwise a new account is created
+func resolveOidcAccount(provider string, claims utils.OidcClaims, logger *log.Logger, userRepo data_access.IUserRepo, securityRepo data_access.IUserSecurityRepo, identityRepo data_access.IIdentityRepo, ctx context.Context) (*entity.User, error) {
+	var curTime = time.Now()
+
+	identity, err := identityRepo.GetIdentity(provider, claims.Subject, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var account entity.User
+	if identity != nil {
+		if err := verifyAccount(identity.UserId, id_validate, &account, userRepo, ctx); err != nil {
+			return nil, err
+		}
+
+		identityRepo.UpdateLastLogin(identity.IdentityId, curTime, ctx)
+		return &account, nil
+	}
+
+	if claims.Email == "" {
+		return nil, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	existed, err := userRepo.GetUserByEmail(claims.Email, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if existed != nil {
+		// Unverified email could be anyone's, the owner has to log in with password
+		if !claims.EmailVerified {
+			return nil, errors.New(noti.EMAIL_REGISTERED_WARN_MSG)
+		}
+
+		account = *existed
+	} else {
+		account, err = createOidcAccount(claims, logger, userRepo, securityRepo, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := identityRepo.CreateIdentity(entity.Identity{
+		IdentityId:  utils.GenerateId(),
+		UserId:      account.UserId,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		CreatedAt:   curTime,
+		LastLoginAt: curTime,
+	}, ctx); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// Create customer account of a social login, password is random so it can only be used after a reset
+func createOidcAccount(claims utils.OidcClaims, logger *log.Logger, userRepo data_access.IUserRepo, securityRepo data_access.IUserSecurityRepo, ctx context.Context) (entity.User, error) {
+	hashPw, err := utils.ToHashString(utils.GenerateId(), logger)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	var fullName string = claims.Name
+	if fullName == "" {
+		fullName = claims.Email
+	}
+
+	var curTime time.Time = time.Now()
+	var tmpTime time.Time = utils.GetPrimitiveTime()
+	var account = entity.User{
+		UserId:      utils.GenerateId(),
+		RoleId:      config.Get().Role.UserRole,
+		FullName:    fullName,
+		Email:       claims.Email,
+		Password:    hashPw,
+		Gender:      "Unknown",
+		IsActive:    true,
+		IsActivated: bool(claims.EmailVerified), // Unverified email is activated by mail as registration
+		CreatedAt:   curTime,
+		UpdatedAt:   curTime,
+	}
+
+	if err := userRepo.CreateUser(account, ctx); err != nil {
+		return entity.User{}, err
+	}
+
+	if err := securityRepo.CreateUserSecurity(entity.UserSecurity{
+		UserId:     account.UserId,
+		FailAccess: 0,
+		LastFail:   &tmpTime,
+	}, ctx); err != nil {
+		return entity.User{}, err
+	}
+
+	return account, nil
+}
+
 // -------------------- ~~~~~ --------------------
 // -------------------- VOUCHER SERVICE HELPER --------------------
 
-func isVoucherAvailable(voucher entity.Voucher) bool {
-	return !utils.IsActionExpired(voucher.ExpiredAt) && voucher.ActiveStatus && voucher.Amount > 0
+// Whether the user can redeem the voucher, personal vouchers are only for their owner
+func isVoucherAvailable(voucher entity.Voucher, userId string) bool {
+	return !utils.IsActionExpired(voucher.ExpiredAt) && voucher.ActiveStatus && voucher.Amount > 0 &&
+		(voucher.UserId == nil || *voucher.UserId == userId)
 }
 
 // -------------------- ~~~~~ --------------------
@@ -404,22 +631,1126 @@ func isInventoryActionValid(action string) bool {
 
 // Execute inventory transaction when updated
 func executeInventoryTransaction(action string, amount int64) int64 {
-	// As sale and export are the actions which minus the product inventory -> turn the amount to negative
-	if action == Sale_action || action == Export_action {
+	// As sale, export and transfer out are the actions which minus the inventory -> turn the amount to negative
+	if action == Sale_action || action == Export_action || action == Transfer_out_action {
 		amount = -amount
 	}
 
 	return amount
 }
 
-// Inverse inventory transaction when updated
-func inverseInventoryTransaction(action string, amount int64) int64 {
-	// As sale and export are the actions which minus the product inventory
-	// When reverse these actions, we will add them back to the inventory
-	// Otherwise, the rest actions as Adding quantity to inventory will be inverse to minus
-	if action != Sale_action || action != Export_action {
-		amount = -amount
+// Ledger entry of an action on a product inventory at a warehouse, quantity change is signed by the action
+func generateInventoryTransaction(productId, warehouseId, action string, amount int64, note string, date time.Time) entity.ProductInventoryTransaction {
+	var curTime time.Time = time.Now()
+	if date.IsZero() {
+		date = curTime
 	}
 
-	return amount
+	return entity.ProductInventoryTransaction{
+		TransactionId:  utils.GenerateId(),
+		ProductId:      productId,
+		WarehouseId:    warehouseId,
+		Amount:         amount,
+		Action:         action,
+		Note:           note,
+		Date:           date,
+		CreatedAt:      curTime,
+		UpdatedAt:      curTime,
+		QuantityChange: executeInventoryTransaction(action, amount),
+	}
+}
+
+// Inventory change made by a recorded ledger entry
+func toInventoryEvent(tx entity.ProductInventoryTransaction) inventoryEvent {
+	var res = inventoryEvent{
+		ProductId: tx.ProductId,
+		Action:    tx.Action,
+	}
+
+	if tx.Balance != nil {
+		res.CurrentQuantity = *tx.Balance
+		res.PreviousQuantity = *tx.Balance - tx.QuantityChange
+	}
+
+	return res
+}
+
+// Inventory change of a product after a transaction was stored
+type inventoryEvent struct {
+	ProductId        string
+	Action           string
+	PreviousQuantity int64
+	CurrentQuantity  int64
+}
+
+// Reaction of another domain to an inventory change
+type inventoryHook func(event inventoryEvent, ctx context.Context) error
+
+// Hooks run on every stored inventory change, whichever service recorded it
+func getInventoryHooks(stockSubscriptionRepo data_access.IStockSubscriptionRepo, inventoryRepo data_access.IProductInventoryRepo, logger *log.Logger) []inventoryHook {
+	return []inventoryHook{
+		queueBackInStockHook(stockSubscriptionRepo),
+		evaluateLowStockHook(inventoryRepo, logger),
+	}
+}
+
+// Record transactions to the ledger at once then run the hooks on the changed inventories
+func appendInventoryTransactions(txs []entity.ProductInventoryTransaction, hooks []inventoryHook, inventoryTxRepo data_access.IProductInventoryTransactionRepo, logger *log.Logger, ctx context.Context) error {
+	res, err := inventoryTxRepo.AppendProductInventoryTransactions(txs, ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range *res {
+		publishInventoryEvent(toInventoryEvent(tx), hooks, logger, ctx)
+	}
+
+	return nil
+}
+
+// Record a stocktake at once and run the hooks on the recorded differences, the number of recorded entries is returned
+func appendStocktakeTransactions(txs []entity.ProductInventoryTransaction, hooks []inventoryHook, inventoryTxRepo data_access.IProductInventoryTransactionRepo, logger *log.Logger, ctx context.Context) (int, error) {
+	res, err := inventoryTxRepo.AppendStocktakeTransactions(txs, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, tx := range *res {
+		publishInventoryEvent(toInventoryEvent(tx), hooks, logger, ctx)
+	}
+
+	return len(*res), nil
+}
+
+// Run the hooks after an inventory change was stored, a failed hook doesn't undo the transaction
+func publishInventoryEvent(event inventoryEvent, hooks []inventoryHook, logger *log.Logger, ctx context.Context) {
+	for _, hook := range hooks {
+		if err := hook(event, ctx); err != nil {
+			logger.Println(fmt.Sprintf(noti.INVENTORY_HOOK_ERR_MSG, event.ProductId) + err.Error())
+		}
+	}
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- MFA SERVICE HELPER --------------------
+
+// Verify second factor with a TOTP code or a one-time recovery code, accepted codes can't be used again
+func verifySecondFactor(totp entity.UserTotp, code, recoveryCode string, mfaRepo data_access.IMfaRepo, ctx context.Context) error {
+	var INVALID_CODE_ERR error = errors.New(noti.MFA_CODE_INVALID_WARN_MSG)
+
+	if code != "" {
+		step, ok := utils.VerifyTotpCode(totp.Secret, code, time.Now())
+		if !ok {
+			return INVALID_CODE_ERR
+		}
+
+		isUsed, err := mfaRepo.UseTotpStep(totp.UserId, step, ctx)
+		if err != nil {
+			return err
+		}
+
+		if !isUsed { // Replayed code
+			return INVALID_CODE_ERR
+		}
+
+		return nil
+	}
+
+	if recoveryCode == "" {
+		return INVALID_CODE_ERR
+	}
+
+	codes, err := mfaRepo.GetUnusedRecoveryCodes(totp.UserId, ctx)
+	if err != nil {
+		return err
+	}
+
+	var normalized = utils.NormalizeRecoveryCode(recoveryCode)
+	for _, x := range *codes {
+		if !utils.IsHashStringMatched(normalized, x.CodeHash) {
+			continue
+		}
+
+		isUsed, err := mfaRepo.UseRecoveryCode(x.CodeId, ctx)
+		if err != nil {
+			return err
+		}
+
+		if isUsed {
+			return nil
+		}
+	}
+
+	return INVALID_CODE_ERR
+}
+
+// Replace recovery codes of user with new ones, returns plain codes to be shown once
+func generateRecoveryCodes(userId string, logger *log.Logger, mfaRepo data_access.IMfaRepo, ctx context.Context) (*response.RecoveryCodesResponse, error) {
+	codes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		logger.Println("Error while generating recovery codes - " + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	var curTime = time.Now()
+	var records []entity.RecoveryCode
+	for _, code := range codes {
+		hash, err := utils.ToHashString(utils.NormalizeRecoveryCode(code), logger)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, entity.RecoveryCode{
+			CodeId:    utils.GenerateId(),
+			UserId:    userId,
+			CodeHash:  hash,
+			CreatedAt: curTime,
+		})
+	}
+
+	if err := mfaRepo.ReplaceRecoveryCodes(userId, records, ctx); err != nil {
+		return nil, err
+	}
+
+	return &response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- ROLE SERVICE HELPER --------------------
+
+// Check if a role is granted every permission, permission sets are read through cache
+func hasPermissions(roleId string, permissions []string, permissionRepo data_access.IPermissionRepo, ctx context.Context) (bool, error) {
+	granted, err := permissionRepo.GetPermissionsByRole(roleId, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		if !slices.Contains(*granted, permission) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- VIP TIER SERVICE HELPER --------------------
+
+func isVipTierValueValid(minSpend, discountPercent float64) bool {
+	return minSpend >= 0 && discountPercent >= 0 && discountPercent <= 100
+}
+
+// Highest tier reached by the spend, tiers are sorted by threshold
+func resolveVipTier(tiers []entity.VipTier, spend float64) *entity.VipTier {
+	var res *entity.VipTier
+	for index := range tiers {
+		if tiers[index].MinSpend <= spend {
+			res = &tiers[index]
+		}
+	}
+
+	return res
+}
+
+// Lowest tier not reached yet by the spend, tiers are sorted by threshold
+func getNextVipTier(tiers []entity.VipTier, spend float64) *entity.VipTier {
+	for index := range tiers {
+		if tiers[index].MinSpend > spend {
+			return &tiers[index]
+		}
+	}
+
+	return nil
+}
+
+func findVipTier(tiers []entity.VipTier, tierId *string) *entity.VipTier {
+	if tierId == nil {
+		return nil
+	}
+
+	for index := range tiers {
+		if tiers[index].TierId == *tierId {
+			return &tiers[index]
+		}
+	}
+
+	return nil
+}
+
+func isSameVipTier(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// Apply discount of the user's current tier to a checkout amount
+func applyVipTierDiscount(userId string, amount float64, tierRepo data_access.IVipTierRepo, userTierRepo data_access.IUserTierRepo, ctx context.Context) (float64, error) {
+	userTier, err := userTierRepo.GetUserTier(userId, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if userTier == nil || userTier.TierId == nil {
+		return amount, nil
+	}
+
+	tier, err := tierRepo.GetVipTierById(*userTier.TierId, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if tier == nil || tier.DiscountPercent <= 0 {
+		return amount, nil
+	}
+
+	return math.Round(amount * (100 - tier.DiscountPercent) / 100), nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- CART SERVICE HELPER --------------------
+
+// Lines of a cart revalidated against current product and stock
+func getCartLines(cartId string, cartRepo data_access.ICartRepo, productRepo data_access.IProductRepo, inventoryRepo data_access.IProductInventoryRepo, ctx context.Context) ([]response.CartLineResponse, error) {
+	items, err := cartRepo.GetCartItems(cartId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return revalidateCartItems(*items, productRepo, inventoryRepo, ctx)
+}
+
+// Re-price cart lines with current products and flag lines which changed since acknowledged or became unavailable
+func revalidateCartItems(items []entity.CartItem, productRepo data_access.IProductRepo, inventoryRepo data_access.IProductInventoryRepo, ctx context.Context) ([]response.CartLineResponse, error) {
+	var res = []response.CartLineResponse{}
+	for _, item := range items {
+		var line = response.CartLineResponse{
+			CartItem: response.CartItem{
+				ProductId: item.ProductId,
+				Quantity:  item.Quantity,
+			},
+			Status:            domain_status.CART_LINE_UNAVAILABLE,
+			AcknowledgedPrice: item.UnitPrice,
+		}
+
+		product, err := productRepo.GetProductById(item.ProductId, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		inventory, err := inventoryRepo.GetProductInventory(item.ProductId, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if inventory != nil {
+			line.AvailableQuantity = inventory.CurrentQuantity
+		}
+
+		if product != nil {
+			line.Name = product.ProductName
+			line.ImageUrl = product.Image
+			line.Price = product.Price
+			line.Currency = product.Currency
+		}
+
+		line.Status = getCartLineStatus(item, product, inventory)
+		res = append(res, line)
+	}
+
+	return res, nil
+}
+
+// Status of a cart line against current product and stock, unavailability outranks stock which outranks price
+func getCartLineStatus(item entity.CartItem, product *entity.Product, inventory *entity.ProductInventory) string {
+	switch {
+	case product == nil || !product.ActiveStatus || inventory == nil || inventory.CurrentQuantity <= 0:
+		return domain_status.CART_LINE_UNAVAILABLE
+	case inventory.CurrentQuantity < int64(item.Quantity):
+		return domain_status.CART_LINE_INSUFFICIENT_STOCK
+	case product.Price != item.UnitPrice:
+		return domain_status.CART_LINE_PRICE_CHANGED
+	default:
+		return domain_status.CART_LINE_AVAILABLE
+	}
+}
+
+// Lines of a cart indexed by product
+func getCartItemsByProduct(cartId string, cartRepo data_access.ICartRepo, ctx context.Context) (map[string]entity.CartItem, error) {
+	items, err := cartRepo.GetCartItems(cartId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = make(map[string]entity.CartItem, len(*items))
+	for _, item := range *items {
+		res[item.ProductId] = item
+	}
+
+	return res, nil
+}
+
+// Get guest cart of the token, expired cart is considered not existed
+func getGuestCart(cartToken string, guestCartRepo data_access.IGuestCartRepo, ctx context.Context) (*entity.GuestCart, error) {
+	if cartToken == "" {
+		return nil, nil
+	}
+
+	cart, err := guestCartRepo.GetGuestCart(utils.ToSHA256String(cartToken), ctx)
+	if err != nil || cart == nil || !cart.ExpiredAt.After(time.Now()) {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// Merge guest cart of the token into the user cart, stock is rechecked at merge.
+// Failures are only logged so login and registration never fail because of the cart
+func mergeGuestCart(userId, cartToken string, logger *log.Logger, guestCartRepo data_access.IGuestCartRepo, ctx context.Context) {
+	guestCart, err := getGuestCart(cartToken, guestCartRepo, ctx)
+	if err != nil || guestCart == nil {
+		return
+	}
+
+	var curTime = time.Now()
+	if _, err := guestCartRepo.MergeGuestCart(guestCart.CartId, entity.Cart{
+		UserId:    userId,
+		ExpiredAt: curTime.AddDate(0, 0, 7),
+		CreatedAt: curTime,
+		UpdatedAt: curTime,
+	}, config.Get().Cart.MergeStrategy, ctx); err != nil {
+		logger.Println("Merge guest cart into cart of user " + userId + " failed - " + err.Error())
+	}
+}
+
+// Vouchers and unsubscribe links of abandoned cart reminders
+const (
+	cart_reminder_voucher_code_length  int    = 10
+	cart_reminder_voucher_code_prefix  string = "CART-"
+	cart_reminder_voucher_amount       int64  = 1 // Voucher is single use, only for the owner of the cart
+	cart_reminder_unsubscribe_token_qs string = "token"
+)
+
+// Unsubscribe link of a reminder, empty if no unsubscribe url is configured
+func getCartReminderUnsubscribeUrl(token string) string {
+	var value = config.Get().Cart.ReminderUnsubscribeUrl
+	if value == "" {
+		return ""
+	}
+
+	link, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+
+	var query = link.Query()
+	query.Set(cart_reminder_unsubscribe_token_qs, token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
+
+// Mail a reminder of an abandoned cart with its available lines at current prices and an optional one-time voucher.
+// Returns false if nothing was sent: no available line, already reminded by a concurrent run or mail failed
+func sendCartReminder(cart entity.Cart, logger *log.Logger, userRepo data_access.IUserRepo, cartRepo data_access.ICartRepo, productRepo data_access.IProductRepo,
+	inventoryRepo data_access.IProductInventoryRepo, voucherRepo data_access.IVoucherRepo, reminderRepo data_access.ICartReminderRepo, ctx context.Context) (bool, error) {
+	user, err := userRepo.GetUser(cart.UserId, ctx)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	lines, err := getCartLines(cart.UserId, cartRepo, productRepo, inventoryRepo, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var items []response.CartItem
+	var productIds []string
+	for _, line := range lines {
+		if line.Status == domain_status.CART_LINE_UNAVAILABLE {
+			continue
+		}
+
+		items = append(items, line.CartItem)
+		productIds = append(productIds, line.ProductId)
+	}
+
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		logger.Println("Error while generating cart reminder token - " + err.Error())
+		return false, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	var curTime = time.Now()
+	var reminder = entity.CartReminder{
+		ReminderId:    utils.GenerateId(),
+		UserId:        cart.UserId,
+		CartUpdatedAt: cart.UpdatedAt,
+		TokenHash:     utils.ToSHA256String(token),
+		RemindedAt:    curTime,
+	}
+
+	var mailBody = request.MailBody{
+		Email:          user.Email,
+		Subject:        noti.ABANDONED_CART_MAIL_SUBJECT,
+		Username:       user.FullName,
+		Url:            config.Get().Cart.ReminderUrl,
+		CartItems:      items,
+		UnsubscribeUrl: getCartReminderUnsubscribeUrl(token),
+	}
+
+	var voucher *entity.Voucher
+	if percent, ttl := config.Get().Cart.ReminderVoucherPercent, config.Get().Cart.ReminderVoucherTTL; percent > 0 {
+		voucher = &entity.Voucher{
+			VoucherId:          utils.GenerateId(),
+			Code:               cart_reminder_voucher_code_prefix + strings.ToUpper(strings.ReplaceAll(utils.GenerateId(), "-", "")[:cart_reminder_voucher_code_length]),
+			Discount:           percent,
+			Amount:             cart_reminder_voucher_amount,
+			Description:        "Abandoned cart reminder",
+			ActiveStatus:       true,
+			AllowedCategoryIDs: []string{},
+			AllowedProductIDs:  productIds,
+			UserId:             &cart.UserId,
+			ExpiredAt:          curTime.Add(ttl),
+			CreatedAt:          curTime,
+			UpdatedAt:          curTime,
+		}
+
+		if err := voucherRepo.CreateVoucher(*voucher, ctx); err != nil {
+			return false, err
+		}
+
+		reminder.VoucherId = &voucher.VoucherId
+		mailBody.VoucherCode = voucher.Code
+		mailBody.VoucherDiscount = strconv.FormatFloat(percent, 'f', -1, 64)
+		mailBody.VoucherExpiredAt = voucher.ExpiredAt.Format(lockout_time_layout)
+	}
+
+	// Voucher of a reminder which is not sent is useless
+	var discardVoucher = func() {
+		if voucher != nil {
+			voucherRepo.RemoveVoucher(voucher.VoucherId, ctx)
+		}
+	}
+
+	isCreated, err := reminderRepo.CreateCartReminder(reminder, ctx)
+	if err != nil || !isCreated {
+		discardVoucher()
+		return false, err
+	}
+
+	if err := utils.SendMail(request.SendMailRequest{
+		Body:         mailBody,
+		TemplatePath: mail_const.ABANDONED_CART_MAIL_TEMPLATE,
+		Logger:       logger,
+	}); err != nil {
+		// Reminder is retried by the next run
+		reminderRepo.RemoveCartReminder(reminder.ReminderId, ctx)
+		discardVoucher()
+		return false, err
+	}
+
+	return true, nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- WISHLIST SERVICE HELPER --------------------
+
+// Validate list type of a wishlist request, empty means the wishlist
+func getWishlistType(listType string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(listType)) {
+	case "", wishlist_type.WISHLIST:
+		return wishlist_type.WISHLIST, nil
+	case wishlist_type.SAVED_FOR_LATER:
+		return wishlist_type.SAVED_FOR_LATER, nil
+	default:
+		return "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+}
+
+// Saved product with current product details, a removed product keeps only its id
+func toWishlistItemResponse(item entity.WishlistItem, product *entity.Product, inventory *entity.ProductInventory) response.WishlistItemResponse {
+	var res = response.WishlistItemResponse{
+		ProductId:         item.ProductId,
+		ListType:          item.ListType,
+		NotifyBackInStock: item.NotifyBackInStock,
+		NotifyPriceDrop:   item.NotifyPriceDrop,
+		AddedAt:           item.CreatedAt,
+	}
+
+	if product != nil {
+		res.Name = product.ProductName
+		res.ImageUrl = product.Image
+		res.Price = product.Price
+		res.Currency = product.Currency
+		res.IsAvailable = product.ActiveStatus && inventory != nil && inventory.CurrentQuantity > 0
+	}
+
+	return res
+}
+
+// Saved products of a user with current details, every list if list type is empty
+func getWishlistItemsDetail(userId, listType string, wishlistRepo data_access.IWishlistRepo, productRepo data_access.IProductRepo, inventoryRepo data_access.IProductInventoryRepo, ctx context.Context) ([]response.WishlistItemResponse, error) {
+	items, err := wishlistRepo.GetWishlistItems(userId, listType, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = []response.WishlistItemResponse{}
+	for _, item := range *items {
+		product, err := productRepo.GetProductById(item.ProductId, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		inventory, err := inventoryRepo.GetProductInventory(item.ProductId, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, toWishlistItemResponse(item, product, inventory))
+	}
+
+	return res, nil
+}
+
+// Mail restocked and cheaper saved products to their owner then take a new snapshot of the notified items.
+// Returns number of notified items
+func sendWishlistNotice(userId string, items []entity.WishlistItem, logger *log.Logger, userRepo data_access.IUserRepo, productRepo data_access.IProductRepo,
+	inventoryRepo data_access.IProductInventoryRepo, wishlistRepo data_access.IWishlistRepo, ctx context.Context) (int, error) {
+	user, err := userRepo.GetUser(userId, ctx)
+	if err != nil || user == nil || !user.IsActive {
+		return 0, err
+	}
+
+	var notices []response.WishlistNoticeResponse
+	var notifiedItemIds []string
+	for _, item := range items {
+		product, err := productRepo.GetProductById(item.ProductId, ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		inventory, err := inventoryRepo.GetProductInventory(item.ProductId, ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		var notice = response.WishlistNoticeResponse{
+			WishlistItemResponse: toWishlistItemResponse(item, product, inventory),
+			PreviousPrice:        item.NotifiedPrice,
+		}
+
+		// Changed again since selected
+		if !notice.IsAvailable {
+			continue
+		}
+
+		notice.IsRestocked = item.NotifyBackInStock && !item.IsInStock
+		notice.IsPriceDropped = item.NotifyPriceDrop && notice.Price < item.NotifiedPrice
+		if notice.IsRestocked || notice.IsPriceDropped {
+			notices = append(notices, notice)
+			notifiedItemIds = append(notifiedItemIds, item.WishlistItemId)
+		}
+	}
+
+	if len(notices) == 0 {
+		return 0, nil
+	}
+
+	if err := utils.SendMail(request.SendMailRequest{
+		Body: request.MailBody{
+			Email:         user.Email,
+			Subject:       noti.WISHLIST_MAIL_SUBJECT,
+			Username:      user.FullName,
+			WishlistItems: notices,
+		},
+		TemplatePath: mail_const.WISHLIST_MAIL_TEMPLATE,
+		Logger:       logger,
+	}); err != nil {
+		return 0, err
+	}
+
+	var curTime = time.Now()
+	for index, notice := range notices {
+		if err := wishlistRepo.UpdateWishlistItemSnapshot(notifiedItemIds[index], notice.Price, true, curTime, ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(notices), nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- STOCK SUBSCRIPTION SERVICE HELPER --------------------
+
+// Queue waiting subscriptions of a product when stock comes back from zero,
+// mails are sent later in batches by the stock notification job
+func queueBackInStockHook(stockSubscriptionRepo data_access.IStockSubscriptionRepo) inventoryHook {
+	return func(event inventoryEvent, ctx context.Context) error {
+		if event.PreviousQuantity > 0 || event.CurrentQuantity <= 0 {
+			return nil
+		}
+
+		_, err := stockSubscriptionRepo.QueueStockSubscriptions(event.ProductId, time.Now(), ctx)
+		return err
+	}
+}
+
+// Mail a claimed subscription to its owner. A product out of stock again sends the subscription back to waiting.
+// Returns whether the mail was sent
+func sendBackInStockNotice(subscription entity.StockSubscription, logger *log.Logger, userRepo data_access.IUserRepo, productRepo data_access.IProductRepo,
+	inventoryRepo data_access.IProductInventoryRepo, stockSubscriptionRepo data_access.IStockSubscriptionRepo, ctx context.Context) (bool, error) {
+	var curTime time.Time = time.Now()
+
+	user, err := userRepo.GetUser(subscription.UserId, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	product, err := productRepo.GetProductById(subscription.ProductId, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// Nobody to tell or nothing to sell anymore
+	if user == nil || !user.IsActive || product == nil || !product.ActiveStatus {
+		return false, stockSubscriptionRepo.UpdateStockSubscriptionStatus(subscription.SubscriptionId, domain_status.STOCK_SUBSCRIPTION_FAILED, curTime, ctx)
+	}
+
+	inventory, err := inventoryRepo.GetProductInventory(subscription.ProductId, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if inventory == nil || inventory.CurrentQuantity <= 0 {
+		return false, stockSubscriptionRepo.UpdateStockSubscriptionStatus(subscription.SubscriptionId, domain_status.STOCK_SUBSCRIPTION_WAITING, curTime, ctx)
+	}
+
+	if err := utils.SendMail(request.SendMailRequest{
+		Body: request.MailBody{
+			Email:       user.Email,
+			Subject:     noti.BACK_IN_STOCK_MAIL_SUBJECT,
+			Username:    user.FullName,
+			ProductName: product.ProductName,
+			Url:         config.Get().Inventory.ProductPageUrl + product.ProductId,
+		},
+		TemplatePath: mail_const.BACK_IN_STOCK_MAIL_TEMPLATE,
+		Logger:       logger,
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- INVENTORY SERVICE HELPER --------------------
+
+// Quantity is low at or below the reorder point, a zero reorder point disables alerts
+func isInventoryLow(inventory entity.ProductInventory) bool {
+	return inventory.ReorderPoint > 0 && inventory.CurrentQuantity <= inventory.ReorderPoint
+}
+
+// Flag products falling to their reorder point after a sale or export, the flag is cleared once the quantity is above it again
+func evaluateLowStockHook(inventoryRepo data_access.IProductInventoryRepo, logger *log.Logger) inventoryHook {
+	return func(event inventoryEvent, ctx context.Context) error {
+		inventory, err := inventoryRepo.GetProductInventory(event.ProductId, ctx)
+		if err != nil || inventory == nil {
+			return err
+		}
+
+		var isLow bool = isInventoryLow(*inventory)
+		if isLow && inventory.LowStockAt == nil && (event.Action == Sale_action || event.Action == Export_action) {
+			logger.Println("Product " + inventory.ProductId + " is low in stock with " + strconv.FormatInt(inventory.CurrentQuantity, 10) +
+				" items left, reorder point is " + strconv.FormatInt(inventory.ReorderPoint, 10))
+
+			var curTime time.Time = time.Now()
+			return inventoryRepo.UpdateLowStockAt(inventory.ProductId, &curTime, ctx)
+		}
+
+		if !isLow && inventory.LowStockAt != nil {
+			return inventoryRepo.UpdateLowStockAt(inventory.ProductId, nil, ctx)
+		}
+
+		return nil
+	}
+}
+
+// Products at or below their reorder point with days of cover estimated from units sold during the sales velocity window
+func getLowStockInventoriesDetail(warehouseId string, productRepo data_access.IProductRepo, inventoryRepo data_access.IProductInventoryRepo, warehouseRepo data_access.IWarehouseRepo,
+	logger *log.Logger, ctx context.Context) ([]response.LowStockInventoryResponse, error) {
+
+	inventories, err := inventoryRepo.GetLowStockInventories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock of each product per warehouse, only at the warehouse if filtered
+	stocks, err := warehouseRepo.GetWarehouseInventories("", warehouseId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stocksByProduct = map[string][]entity.WarehouseInventory{}
+	for _, stock := range *stocks {
+		stocksByProduct[stock.ProductId] = append(stocksByProduct[stock.ProductId], stock)
+	}
+
+	var window time.Duration = config.Get().Inventory.SalesVelocityWindow
+	soldQuantities, err := inventoryRepo.GetSoldQuantities(time.Now().Add(-window), []string{Sale_action, Export_action, Cancel_order_action, Return_action}, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var days float64 = window.Hours() / 24
+	var res = []response.LowStockInventoryResponse{}
+	for _, inventory := range *inventories {
+		if _, isStocked := stocksByProduct[inventory.ProductId]; warehouseId != "" && !isStocked {
+			continue
+		}
+
+		var item = response.LowStockInventoryResponse{
+			ProductId:          inventory.ProductId,
+			CurrentQuantity:    inventory.CurrentQuantity,
+			ReorderPoint:       inventory.ReorderPoint,
+			SafetyStock:        inventory.SafetyStock,
+			IsBelowSafetyStock: inventory.CurrentQuantity <= inventory.SafetyStock,
+			DailySales:         math.Round(float64(max(soldQuantities[inventory.ProductId], 0))/days*100) / 100,
+			LowStockAt:         inventory.LowStockAt,
+			Warehouses:         stocksByProduct[inventory.ProductId],
+		}
+
+		if product, _ := productRepo.GetProductById(inventory.ProductId, ctx); product != nil {
+			item.Name = product.ProductName
+		}
+
+		if sold := soldQuantities[inventory.ProductId]; sold > 0 {
+			var daysOfCover float64 = math.Round(float64(max(inventory.CurrentQuantity, 0))*days/float64(sold)*10) / 10
+			item.DaysOfCover = &daysOfCover
+		}
+
+		res = append(res, item)
+	}
+
+	return res, nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- WAREHOUSE SERVICE HELPER --------------------
+
+// Mean radius of the earth in kilometers
+const earth_radius_km float64 = 6371
+
+func getDefaultWarehouseId() string {
+	return config.Get().Inventory.DefaultWarehouse
+}
+
+// Warehouse stock can be moved to, default warehouse if id is empty
+func getActiveWarehouse(id string, warehouseRepo data_access.IWarehouseRepo, ctx context.Context) (*entity.Warehouse, error) {
+	if id == "" {
+		id = getDefaultWarehouseId()
+	}
+
+	warehouse, err := warehouseRepo.GetWarehouseById(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if warehouse == nil {
+		return nil, errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetWarehouseTable()))
+	}
+
+	if !warehouse.IsActive {
+		return nil, errors.New(noti.WAREHOUSE_INACTIVE_WARN_MSG)
+	}
+
+	return warehouse, nil
+}
+
+func isCoordinateValid(latitude, longitude *float64) bool {
+	return latitude != nil && longitude != nil && *latitude >= -90 && *latitude <= 90 && *longitude >= -180 && *longitude <= 180
+}
+
+// Warehouse has no coordinates or valid ones
+func isWarehouseLocationValid(latitude, longitude *float64) bool {
+	return (latitude == nil && longitude == nil) || isCoordinateValid(latitude, longitude)
+}
+
+// Great-circle distance in kilometers between a warehouse and a location
+func getWarehouseDistance(warehouse entity.Warehouse, latitude, longitude float64) float64 {
+	var toRadian = func(degree float64) float64 { return degree * math.Pi / 180 }
+
+	var dLat = toRadian(latitude - *warehouse.Latitude)
+	var dLng = toRadian(longitude - *warehouse.Longitude)
+	var a = math.Pow(math.Sin(dLat/2), 2) + math.Cos(toRadian(*warehouse.Latitude))*math.Cos(toRadian(latitude))*math.Pow(math.Sin(dLng/2), 2)
+
+	return 2 * earth_radius_km * math.Asin(math.Sqrt(a))
+}
+
+// Order warehouses from the nearest to the delivery location, warehouses without coordinates come last.
+// Priority decides between warehouses at the same distance or when the location is unknown
+func sortWarehousesByLocation(warehouses []entity.Warehouse, latitude, longitude *float64) {
+	var isLocated = isCoordinateValid(latitude, longitude)
+
+	sort.SliceStable(warehouses, func(i, j int) bool {
+		if isLocated {
+			var iLocated, jLocated = isCoordinateValid(warehouses[i].Latitude, warehouses[i].Longitude), isCoordinateValid(warehouses[j].Latitude, warehouses[j].Longitude)
+			if iLocated != jLocated {
+				return iLocated
+			}
+
+			if iLocated {
+				var iDistance, jDistance = getWarehouseDistance(warehouses[i], *latitude, *longitude), getWarehouseDistance(warehouses[j], *latitude, *longitude)
+				if iDistance != jDistance {
+					return iDistance < jDistance
+				}
+			}
+		}
+
+		return warehouses[i].Priority < warehouses[j].Priority
+	})
+}
+
+// Sale entries taking a quantity of a product from the nearest active warehouse holding all of it,
+// split across the nearest warehouses if none does
+func allocateInventoryTransactions(productId string, quantity int64, latitude, longitude *float64, orderId string, curTime time.Time,
+	warehouseRepo data_access.IWarehouseRepo, ctx context.Context) ([]entity.ProductInventoryTransaction, error) {
+
+	warehouses, err := warehouseRepo.GetWarehouses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stocks, err := warehouseRepo.GetWarehouseInventories(productId, "", ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var quantities = map[string]int64{}
+	for _, stock := range *stocks {
+		quantities[stock.WarehouseId] = stock.CurrentQuantity
+	}
+
+	var candidates []entity.Warehouse
+	for _, warehouse := range *warehouses {
+		if warehouse.IsActive && quantities[warehouse.WarehouseId] > 0 {
+			candidates = append(candidates, warehouse)
+		}
+	}
+
+	sortWarehousesByLocation(candidates, latitude, longitude)
+
+	var generateSale = func(warehouseId string, amount int64) entity.ProductInventoryTransaction {
+		var res = generateInventoryTransaction(productId, warehouseId, Sale_action, amount, "Order "+orderId, curTime)
+		res.OrderId = &orderId
+		return res
+	}
+
+	for _, warehouse := range candidates {
+		if quantities[warehouse.WarehouseId] >= quantity {
+			return []entity.ProductInventoryTransaction{generateSale(warehouse.WarehouseId, quantity)}, nil
+		}
+	}
+
+	var res []entity.ProductInventoryTransaction
+	for _, warehouse := range candidates {
+		if quantity == 0 {
+			break
+		}
+
+		var amount = min(quantity, quantities[warehouse.WarehouseId])
+		res = append(res, generateSale(warehouse.WarehouseId, amount))
+		quantity -= amount
+	}
+
+	if quantity > 0 {
+		return nil, errors.New(noti.INVENTORY_NOT_ENOUGH_WARN_MSG)
+	}
+
+	return res, nil
+}
+
+// Cancel entries returning stock of an order to the warehouses it was taken from.
+// Orders placed before warehouses have no linked entries, their items go back to the default warehouse
+func getOrderRefundTransactions(order entity.Order, inventoryTxRepo data_access.IProductInventoryTransactionRepo, ctx context.Context) ([]entity.ProductInventoryTransaction, error) {
+	var curTime time.Time = time.Now()
+	var note string = "Cancelled order " + order.OrderId
+	var res []entity.ProductInventoryTransaction
+
+	orderTxs, err := inventoryTxRepo.GetOrderInventoryTransactions(order.OrderId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(*orderTxs) > 0 {
+		for _, tx := range *orderTxs {
+			if tx.Action != Sale_action {
+				continue
+			}
+
+			var refund = generateInventoryTransaction(tx.ProductId, tx.WarehouseId, Cancel_order_action, tx.Amount, note, curTime)
+			refund.OrderId = &order.OrderId
+			res = append(res, refund)
+		}
+
+		return res, nil
+	}
+
+	for _, item := range utils.JsonStringToObject[[]response.CartItem](order.Items) {
+		var refund = generateInventoryTransaction(item.ProductId, getDefaultWarehouseId(), Cancel_order_action, int64(item.Quantity), note, curTime)
+		refund.OrderId = &order.OrderId
+		res = append(res, refund)
+	}
+
+	return res, nil
+}
+
+// -------------------- ~~~~~ --------------------
+// -------------------- INVENTORY IMPORT SERVICE HELPER --------------------
+
+// Position of each named column of the header row, sku and quantity are required and action too out of a stocktake
+func getInventoryImportColumns(header []string, mode string) (map[string]int, bool) {
+	var res = map[string]int{}
+	for index, name := range header {
+		res[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+
+	_, hasSku := res["sku"]
+	_, hasQuantity := res["quantity"]
+	_, hasAction := res["action"]
+
+	return res, hasSku && hasQuantity && (hasAction || mode == stocktake_import_mode)
+}
+
+func getInventoryImportCell(row file.SpreadsheetRow, columns map[string]int, name string) string {
+	index, isExisted := columns[name]
+	if !isExisted || index >= len(row.Cells) {
+		return ""
+	}
+
+	return strings.TrimSpace(row.Cells[index])
+}
+
+// Whole quantity, spreadsheets may store it as a decimal such as 10.0
+func parseInventoryImportQuantity(value string) (int64, bool) {
+	if res, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return res, true
+	}
+
+	res, err := strconv.ParseFloat(value, 64)
+	if err != nil || res != math.Trunc(res) || math.Abs(res) > math.MaxInt32 {
+		return 0, false
+	}
+
+	return int64(res), true
+}
+
+// Ledger entries of the rows of an import at a warehouse, rows are validated in order against products and stock left by previous rows.
+// Entries are only meant to be recorded if no row error is returned
+func generateInventoryImportTransactions(rows []file.SpreadsheetRow, columns map[string]int, mode, warehouseId string,
+	productRepo data_access.IProductRepo, warehouseRepo data_access.IWarehouseRepo, ctx context.Context) ([]entity.ProductInventoryTransaction, []response.InventoryImportError, error) {
+
+	var curTime time.Time = time.Now()
+	var txs []entity.ProductInventoryTransaction
+	var rowErrors []response.InventoryImportError
+
+	var products = map[string]bool{}    // Existence of each sku
+	var quantities = map[string]int64{} // Quantity at the warehouse after previous rows of transactions
+	var counted = map[string]bool{}     // Products counted by previous rows of a stocktake
+
+	for _, row := range rows {
+		var sku = getInventoryImportCell(row, columns, "sku")
+		var note = getInventoryImportCell(row, columns, "note")
+		var rowError = func(msg string) {
+			rowErrors = append(rowErrors, response.InventoryImportError{Line: row.Line, Sku: sku, Message: msg})
+		}
+
+		isExisted, isChecked := products[sku]
+		if !isChecked && sku != "" {
+			product, err := productRepo.GetProductById(sku, ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			isExisted = product != nil
+			products[sku] = isExisted
+		}
+
+		if !isExisted {
+			rowError(noti.INVENTORY_IMPORT_SKU_WARN_MSG)
+			continue
+		}
+
+		quantity, isValid := parseInventoryImportQuantity(getInventoryImportCell(row, columns, "quantity"))
+
+		// Difference with the stock is computed when recorded, the count is kept as the warehouse balance
+		if mode == stocktake_import_mode {
+			if !isValid || quantity < 0 {
+				rowError(noti.INVENTORY_IMPORT_COUNT_WARN_MSG)
+				continue
+			}
+
+			if counted[sku] {
+				rowError(noti.INVENTORY_IMPORT_DUPLICATED_WARN_MSG)
+				continue
+			}
+
+			counted[sku] = true
+
+			if note == "" {
+				note = "Stocktake"
+			}
+
+			var count = quantity
+			var tx = generateInventoryTransaction(sku, warehouseId, Stocktake_action, 0, note, curTime)
+			tx.WarehouseBalance = &count
+			txs = append(txs, tx)
+			continue
+		}
+
+		if _, isLoaded := quantities[sku]; !isLoaded {
+			stocks, err := warehouseRepo.GetWarehouseInventories(sku, warehouseId, ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			quantities[sku] = 0
+			for _, stock := range *stocks {
+				quantities[sku] = stock.CurrentQuantity
+			}
+		}
+
+		if !isValid || quantity <= 0 {
+			rowError(noti.INVENTORY_IMPORT_QUANTITY_WARN_MSG)
+			continue
+		}
+
+		var action string = strings.ToLower(getInventoryImportCell(row, columns, "action"))
+		if !isInventoryActionValid(action) {
+			rowError(noti.INVENTORY_IMPORT_ACTION_WARN_MSG)
+			continue
+		}
+
+		var tx = generateInventoryTransaction(sku, warehouseId, action, quantity, note, curTime)
+		if quantities[sku]+tx.QuantityChange < 0 {
+			rowError(noti.INVENTORY_NOT_ENOUGH_WARN_MSG)
+			continue
+		}
+
+		if tx.Note == "" {
+			tx.Note = "Bulk import"
+		}
+
+		quantities[sku] += tx.QuantityChange
+		txs = append(txs, tx)
+	}
+
+	return txs, rowErrors, nil
 }
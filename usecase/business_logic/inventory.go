@@ -0,0 +1,155 @@
+package businesslogic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	mail_const "sonit_server/constant/mail_const"
+	"sonit_server/constant/noti"
+	"sonit_server/constant/permission"
+	repo "sonit_server/data_access"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	business_logic "sonit_server/interface/business_logic"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	"sonit_server/model/entity"
+	"sonit_server/utils"
+	"time"
+)
+
+type inventoryService struct {
+	userRepo        data_access.IUserRepo
+	productRepo     data_access.IProductRepo
+	inventoryRepo   data_access.IProductInventoryRepo
+	inventoryTxRepo data_access.IProductInventoryTransactionRepo
+	warehouseRepo   data_access.IWarehouseRepo
+	logger          *log.Logger
+}
+
+func GenerateInventoryService() (business_logic.IInventoryService, error) {
+	var logger = utils.GetLogConfig()
+
+	cnn, err := db.ConnectDB(logger, db_server.InitializePostgreSQL())
+
+	if err != nil {
+		return nil, err
+	}
+
+	inventory_cnn = cnn
+
+	return InitializeInventoryService(cnn, logger), nil
+}
+
+func InitializeInventoryService(db *sql.DB, logger *log.Logger) business_logic.IInventoryService {
+	return &inventoryService{
+		userRepo:        repo.InitializeUserRepo(db, logger),
+		productRepo:     repo.InitializeCachedProductRepo(db, logger),
+		inventoryRepo:   repo.InitializeProductInventoryRepo(db, logger),
+		inventoryTxRepo: repo.InitializeProductInventoryTransactionRepo(db, logger),
+		warehouseRepo:   repo.InitializeWarehouseRepo(db, logger),
+		logger:          logger,
+	}
+}
+
+var inventory_cnn *sql.DB
+
+// GetLowStockInventories implements businesslogic.IInventoryService.
+// Products stocked at the warehouse only if warehouse is not empty
+func (i *inventoryService) GetLowStockInventories(warehouseId string, ctx context.Context) ([]response.LowStockInventoryResponse, error) {
+	defer closeCnn(inventory_cnn)
+
+	// Quantities changed by orders are not evaluated on the fly
+	if err := i.inventoryRepo.SyncLowStockInventories(time.Now(), ctx); err != nil {
+		return nil, err
+	}
+
+	return getLowStockInventoriesDetail(warehouseId, i.productRepo, i.inventoryRepo, i.warehouseRepo, i.logger, ctx)
+}
+
+// UpdateInventoryThreshold implements businesslogic.IInventoryService.
+func (i *inventoryService) UpdateInventoryThreshold(req request.UpdateInventoryThresholdRequest, ctx context.Context) error {
+	defer closeCnn(inventory_cnn)
+
+	if req.ReorderPoint < 0 || req.SafetyStock < 0 || req.SafetyStock > req.ReorderPoint {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	inventory, err := i.inventoryRepo.GetProductInventory(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if inventory == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetProductInventoryTable()))
+	}
+
+	inventory.ReorderPoint = req.ReorderPoint
+	inventory.SafetyStock = req.SafetyStock
+
+	// Keep the time the product went low if it still is
+	if !isInventoryLow(*inventory) {
+		inventory.LowStockAt = nil
+	} else if inventory.LowStockAt == nil {
+		var curTime time.Time = time.Now()
+		inventory.LowStockAt = &curTime
+	}
+
+	return i.inventoryRepo.UpdateInventoryThreshold(*inventory, ctx)
+}
+
+// SendLowStockDigest implements businesslogic.IInventoryService.
+// Every user allowed to adjust inventories gets the list of low stock products, nothing is sent if there is none
+func (i *inventoryService) SendLowStockDigest(ctx context.Context) (int, error) {
+	defer closeCnn(inventory_cnn)
+
+	if err := i.inventoryRepo.SyncLowStockInventories(time.Now(), ctx); err != nil {
+		return 0, err
+	}
+
+	items, err := getLowStockInventoriesDetail("", i.productRepo, i.inventoryRepo, i.warehouseRepo, i.logger, ctx)
+	if err != nil || len(items) == 0 {
+		return 0, err
+	}
+
+	recipients, err := i.userRepo.GetUsersByPermission(permission.INVENTORY_ADJUST, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var res int
+	for _, user := range *recipients {
+		if err := utils.SendMail(request.SendMailRequest{
+			Body: request.MailBody{
+				Email:         user.Email,
+				Subject:       noti.LOW_STOCK_MAIL_SUBJECT,
+				Username:      user.FullName,
+				LowStockItems: items,
+			},
+			TemplatePath: mail_const.LOW_STOCK_MAIL_TEMPLATE,
+			Logger:       i.logger,
+		}); err != nil {
+			i.logger.Println("Low stock digest to user " + user.UserId + " failed - " + err.Error())
+			continue
+		}
+
+		res++
+	}
+
+	return res, nil
+}
+
+// VerifyInventoryLedger implements businesslogic.IInventoryService.
+func (i *inventoryService) VerifyInventoryLedger(ctx context.Context) ([]entity.InventoryDrift, error) {
+	defer closeCnn(inventory_cnn)
+
+	drifts, err := i.inventoryTxRepo.GetInventoryDrifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return *drifts, nil
+}
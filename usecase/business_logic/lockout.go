@@ -0,0 +1,103 @@
+package businesslogic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	action_type "sonit_server/constant/action_type"
+	mail_const "sonit_server/constant/mail_const"
+	"sonit_server/constant/noti"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"time"
+)
+
+const lockout_time_layout string = "2006-01-02 15:04:05 MST"
+
+// Lock duration doubles on each lockout: base, 2 * base, 4 * base, ... up to max duration
+func lockDuration(policy config.LockoutConfig, lockCount int) time.Duration {
+	var res = policy.BaseDuration
+	for i := 1; i < lockCount && res < policy.MaxDuration; i++ {
+		res *= 2
+	}
+
+	if res > policy.MaxDuration {
+		return policy.MaxDuration
+	}
+
+	return res
+}
+
+// Reject login of a locked account, a lock which has passed is released and recorded
+func checkAccountLock(security *entity.UserSecurity, ipAddress string, lockoutRepo data_access.ILockoutEventRepo, ctx context.Context) error {
+	if security.LockedUntil == nil {
+		return nil
+	}
+
+	var curTime = time.Now()
+	if curTime.Before(*security.LockedUntil) {
+		return errors.New(noti.ACCOUNT_LOCKED_WARN_MSG)
+	}
+
+	if err := lockoutRepo.ReleaseLock(entity.LockoutEvent{
+		EventId:   utils.GenerateId(),
+		UserId:    security.UserId,
+		Action:    action_type.LOCKOUT_AUTO_UNLOCK_ACTION,
+		IpAddress: &ipAddress,
+		CreatedAt: curTime,
+	}, ctx); err != nil {
+		return err
+	}
+
+	security.LockedUntil = nil
+	security.FailAccess = 0
+
+	return nil
+}
+
+// Processing with wrong-credentials login case, the account is locked when failed attempts in the window reach the threshold.
+// Failed attempts are counted and the account locked by the database so concurrent attempts are neither lost nor locking twice
+func processWrongCredentialsCase(account entity.User, ipAddress string, logger *log.Logger, securityRepo data_access.IUserSecurityRepo, lockoutRepo data_access.ILockoutEventRepo, ctx context.Context) {
+	var policy = config.Get().Lockout
+	var curTime = time.Now()
+
+	// Failed attempts out of the window are forgotten
+	failAccess, lockCount, err := securityRepo.IncreaseFailAccess(account.UserId, curTime.Add(-policy.Window), curTime, ctx)
+	if err != nil || failAccess < policy.Threshold {
+		return
+	}
+
+	var lockedUntil = curTime.Add(lockDuration(policy, lockCount+1))
+	var note = fmt.Sprintf("Locked after %d failed login attempts.", policy.Threshold)
+
+	isLocked, err := lockoutRepo.LockAccount(entity.LockoutEvent{
+		EventId:     utils.GenerateId(),
+		UserId:      account.UserId,
+		Action:      action_type.LOCKOUT_LOCK_ACTION,
+		IpAddress:   &ipAddress,
+		LockedUntil: &lockedUntil,
+		Note:        &note,
+		CreatedAt:   curTime,
+	}, policy.Threshold, ctx)
+	if err != nil || !isLocked {
+		return
+	}
+
+	// Let user know in case it was not them
+	utils.SendMail(request.SendMailRequest{
+		Body: request.MailBody{
+			Email:       account.Email,
+			Subject:     noti.ACCOUNT_LOCKED_MAIL_SUBJECT,
+			Username:    account.FullName,
+			LockedUntil: lockedUntil.Format(lockout_time_layout),
+		},
+
+		TemplatePath: mail_const.ACCOUNT_LOCKED_MAIL_TEMPLATE,
+
+		Logger: logger,
+	})
+}
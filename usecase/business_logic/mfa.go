@@ -0,0 +1,263 @@
+package businesslogic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	repo "sonit_server/data_access"
+	"sonit_server/data_access/cache"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	business_logic "sonit_server/interface/business_logic"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	entity "sonit_server/model/entity"
+	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"time"
+)
+
+type mfaService struct {
+	logger           *log.Logger
+	userRepo         data_access.IUserRepo
+	userSecurityRepo data_access.IUserSecurityRepo
+	sessionRepo      data_access.ISessionRepo
+	lockoutRepo      data_access.ILockoutEventRepo
+	mfaRepo          data_access.IMfaRepo
+	guestCartRepo    data_access.IGuestCartRepo
+}
+
+func InitializeMfaService(db *sql.DB, logger *log.Logger) business_logic.IMfaService {
+	return &mfaService{
+		logger:           logger,
+		userRepo:         repo.InitializeUserRepo(db, logger),
+		userSecurityRepo: repo.InitializeUserSecurityRepo(db, logger),
+		sessionRepo:      repo.InitializeSessionRepo(db, logger),
+		lockoutRepo:      repo.InitializeLockoutEventRepo(db, logger),
+		mfaRepo:          repo.InitializeMfaRepo(db, logger),
+		guestCartRepo:    repo.InitializeGuestCartRepo(db, logger),
+	}
+}
+
+func GenerateMfaService() (business_logic.IMfaService, error) {
+	var logger = utils.GetLogConfig()
+
+	cnn, err := db.ConnectDB(logger, db_server.InitializePostgreSQL())
+
+	if err != nil {
+		return nil, err
+	}
+
+	mfa_cnn = cnn
+
+	return InitializeMfaService(cnn, logger), nil
+}
+
+var mfa_cnn *sql.DB
+
+// EnrollTotp implements businesslogic.IMfaService.
+func (m *mfaService) EnrollTotp(req request.TotpEnrollRequest, ctx context.Context) (*response.TotpEnrollResponse, error) {
+	defer closeCnn(mfa_cnn)
+
+	// Only owner can set up second factor of the account
+	if req.ActorId != req.UserId {
+		return nil, errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	var account entity.User
+	if err := verifyAccount(req.UserId, id_validate, &account, m.userRepo, ctx); err != nil {
+		return nil, err
+	}
+
+	totp, err := m.mfaRepo.GetUserTotp(account.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enabled one must be disabled with a code first
+	if totp != nil && totp.IsEnabled {
+		return nil, errors.New(fmt.Sprintf(noti.DATA_EXISTED_WARN_MSG, "Two-factor authentication", "action"))
+	}
+
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		m.logger.Println("Error while generating TOTP secret - " + err.Error())
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	// Pending secret replaces the previous pending one
+	var curTime = time.Now()
+	if err := m.mfaRepo.SaveUserTotp(entity.UserTotp{
+		UserId:    account.UserId,
+		Secret:    secret,
+		IsEnabled: false,
+		CreatedAt: curTime,
+		UpdatedAt: curTime,
+	}, ctx); err != nil {
+		return nil, err
+	}
+
+	return &response.TotpEnrollResponse{
+		Secret:     secret,
+		OtpauthUri: utils.BuildTotpUri(config.Get().Auth.MfaIssuer, account.Email, secret),
+	}, nil
+}
+
+// ConfirmTotp implements businesslogic.IMfaService.
+func (m *mfaService) ConfirmTotp(req request.MfaCodeRequest, ctx context.Context) (*response.RecoveryCodesResponse, error) {
+	defer closeCnn(mfa_cnn)
+
+	if req.ActorId != req.UserId {
+		return nil, errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	totp, err := m.mfaRepo.GetUserTotp(req.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if totp == nil || totp.IsEnabled {
+		return nil, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	// Only TOTP code proves the authenticator is set up
+	step, ok := utils.VerifyTotpCode(totp.Secret, req.Code, time.Now())
+	if !ok {
+		return nil, errors.New(noti.MFA_CODE_INVALID_WARN_MSG)
+	}
+
+	totp.IsEnabled = true
+	totp.LastUsedStep = step
+	totp.UpdatedAt = time.Now()
+
+	if err := m.mfaRepo.SaveUserTotp(*totp, ctx); err != nil {
+		return nil, err
+	}
+
+	return generateRecoveryCodes(totp.UserId, m.logger, m.mfaRepo, ctx)
+}
+
+// DisableTotp implements businesslogic.IMfaService.
+func (m *mfaService) DisableTotp(req request.MfaCodeRequest, ctx context.Context) error {
+	defer closeCnn(mfa_cnn)
+
+	if req.ActorId != req.UserId {
+		return errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	totp, err := m.mfaRepo.GetUserTotp(req.UserId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if totp == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Two-factor authentication"))
+	}
+
+	// Pending enrollment is removed without a code
+	if totp.IsEnabled {
+		if err := verifySecondFactor(*totp, req.Code, req.RecoveryCode, m.mfaRepo, ctx); err != nil {
+			return err
+		}
+	}
+
+	return m.mfaRepo.RemoveUserTotp(totp.UserId, ctx)
+}
+
+// RegenerateRecoveryCodes implements businesslogic.IMfaService.
+func (m *mfaService) RegenerateRecoveryCodes(req request.MfaCodeRequest, ctx context.Context) (*response.RecoveryCodesResponse, error) {
+	defer closeCnn(mfa_cnn)
+
+	if req.ActorId != req.UserId {
+		return nil, errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	totp, err := m.mfaRepo.GetUserTotp(req.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if totp == nil || !totp.IsEnabled {
+		return nil, errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Two-factor authentication"))
+	}
+
+	if err := verifySecondFactor(*totp, req.Code, "", m.mfaRepo, ctx); err != nil {
+		return nil, err
+	}
+
+	return generateRecoveryCodes(totp.UserId, m.logger, m.mfaRepo, ctx)
+}
+
+// VerifyMfaLogin implements businesslogic.IMfaService.
+func (m *mfaService) VerifyMfaLogin(req request.MfaLoginRequest, ctx context.Context) (string, string, error) {
+	defer closeCnn(mfa_cnn)
+
+	claims, err := utils.ParseToken(req.ChallengeToken, m.logger)
+	if err != nil {
+		return "", "", err
+	}
+
+	if claims.TokenUse != utils.MFA_TOKEN_USE || cache.IsTokenRevoked(claims.ID, m.logger) {
+		return "", "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	var account entity.User
+	if err := verifyAccount(claims.UserId, id_validate, &account, m.userRepo, ctx); err != nil {
+		return "", "", err
+	}
+
+	security, err := m.userSecurityRepo.GetUserSecurity(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if security == nil {
+		return "", "", errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Account"))
+	}
+
+	// Wrong codes count towards lockout as wrong passwords do
+	if err := checkAccountLock(security, req.IpAddress, m.lockoutRepo, ctx); err != nil {
+		return "", "", err
+	}
+
+	totp, err := m.mfaRepo.GetUserTotp(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if totp == nil || !totp.IsEnabled {
+		return "", "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	if err := verifySecondFactor(*totp, req.Code, req.RecoveryCode, m.mfaRepo, ctx); err != nil {
+		if err.Error() == noti.MFA_CODE_INVALID_WARN_MSG {
+			processWrongCredentialsCase(account, req.IpAddress, m.logger, m.userSecurityRepo, m.lockoutRepo, ctx)
+		}
+
+		return "", "", err
+	}
+
+	// Challenge can't be used again
+	if err := cache.RevokeToken(claims.ID, claims.ExpiresAt.Time, m.logger); err != nil {
+		return "", "", err
+	}
+
+	accessToken, refreshToken, err := createLoginSession(account, request.LoginRequest{
+		DeviceName: req.DeviceName,
+		IpAddress:  req.IpAddress,
+		UserAgent:  req.UserAgent,
+		CartToken:  req.CartToken,
+	}, true, m.logger, m.sessionRepo, m.guestCartRepo, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	security.FailAccess = 0
+	security.LockCount = 0
+
+	return accessToken, refreshToken, m.userSecurityRepo.EditUserSecurity(*security, ctx)
+}
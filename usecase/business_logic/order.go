@@ -17,7 +17,6 @@ import (
 	"sonit_server/model/dto/response"
 	"sonit_server/model/entity"
 	"sonit_server/utils"
-	"strings"
 	"sync"
 	"time"
 )
@@ -25,11 +24,16 @@ import (
 type orderService struct {
 	logger          *log.Logger
 	userRepo        data_access.IUserRepo
+	productRepo     data_access.IProductRepo
 	inventoryRepo   data_access.IProductInventoryRepo
 	inventoryTxRepo data_access.IProductInventoryTransactionRepo
+	warehouseRepo   data_access.IWarehouseRepo
 	shippingRepo    data_access.IShippingRepo
 	cartRepo        data_access.ICartRepo
 	orderRepo       data_access.IOrderRepo
+	tierRepo        data_access.IVipTierRepo
+	userTierRepo    data_access.IUserTierRepo
+	hooks           []inventoryHook
 }
 
 func GenerateOrderService() (business_logic.IOrderService, error) {
@@ -47,13 +51,20 @@ func GenerateOrderService() (business_logic.IOrderService, error) {
 }
 
 func InitializeOrderService(db *sql.DB, logger *log.Logger) business_logic.IOrderService {
+	var inventoryRepo = repo.InitializeProductInventoryRepo(db, logger)
+
 	return &orderService{
 		logger:          logger,
 		userRepo:        repo.InitializeUserRepo(db, logger),
-		inventoryRepo:   repo.InitializeProductInventoryRepo(db, logger),
+		productRepo:     repo.InitializeCachedProductRepo(db, logger),
+		inventoryRepo:   inventoryRepo,
 		inventoryTxRepo: repo.InitializeProductInventoryTransactionRepo(db, logger),
+		warehouseRepo:   repo.InitializeWarehouseRepo(db, logger),
 		cartRepo:        repo.InitializeCartRepo(db, logger),
 		orderRepo:       repo.InitializeOrderRepo(db, logger),
+		tierRepo:        repo.InitializeCachedVipTierRepo(db, logger),
+		userTierRepo:    repo.InitializeUserTierRepo(db, logger),
+		hooks:           getInventoryHooks(repo.InitializeStockSubscriptionRepo(db, logger), inventoryRepo, logger),
 	}
 }
 
@@ -64,7 +75,7 @@ func (o *orderService) CreateOrder(req request.CreateOrderRequest, ctx context.C
 	var genericError error = errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	defer closeCnn(order_cnn)
 
-	if !isEntityExist(o.userRepo, id_type, req.UserId, ctx) {
+	if !isEntityExist(o.userRepo, req.UserId, id_type, ctx) {
 		return genericError
 	}
 
@@ -80,12 +91,20 @@ func (o *orderService) CreateOrder(req request.CreateOrderRequest, ctx context.C
 		status = domain_status.ORDER_PENDING
 	}
 
-	var itemsInCart []response.CartItem = utils.JsonStringToObject[[]response.CartItem](cart.Items)
+	var itemsInCart = map[string]entity.CartItem{}
+	if cart != nil {
+		itemsInCart, err = getCartItemsByProduct(cart.UserId, o.cartRepo, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	var purchasedItems []entity.CartItem // Cart lines bought by the order
 
-	var inventories []entity.ProductInventory = make([]entity.ProductInventory, len(req.Items))
-	var inventoryTxs []entity.ProductInventoryTransaction = make([]entity.ProductInventoryTransaction, len(req.Items))
+	var inventoryTxs []entity.ProductInventoryTransaction
 	var totalAmount float64
 	var curTime time.Time = time.Now()
+	var orderId string = utils.GenerateId()
 
 	for index, item := range req.Items {
 		inventory, err := o.inventoryRepo.GetProductInventory(item.ProductId, ctx)
@@ -93,7 +112,22 @@ func (o *orderService) CreateOrder(req request.CreateOrderRequest, ctx context.C
 			return err
 		}
 
-		if inventory == nil {
+		product, err := o.productRepo.GetProductById(item.ProductId, ctx)
+		if err != nil {
+			return err
+		}
+
+		// Line repriced, out of stock or unavailable since acknowledged by the shopper
+		line, isInCart := itemsInCart[item.ProductId]
+		if isInCart && getCartLineStatus(entity.CartItem{
+			ProductId: item.ProductId,
+			Quantity:  item.Quantity,
+			UnitPrice: line.UnitPrice,
+		}, product, inventory) != domain_status.CART_LINE_AVAILABLE {
+			return errors.New(noti.CART_CHANGED_WARN_MSG)
+		}
+
+		if product == nil || !product.ActiveStatus || inventory == nil {
 			return genericError
 		}
 
@@ -101,78 +135,57 @@ func (o *orderService) CreateOrder(req request.CreateOrderRequest, ctx context.C
 			return genericError
 		}
 
-		inventory.CurrentQuantity -= int64(item.Quantity)
-		totalAmount += float64(item.Quantity) * item.Price
-
-		if strings.Contains(cart.Items, item.ProductId) {
-			for i, itemInCart := range itemsInCart {
-				if itemInCart.ProductId == item.ProductId {
-					itemsInCart[i].Quantity -= item.Quantity
-					if itemsInCart[i].Quantity < 1 {
-						itemsInCart = append(itemsInCart[:i], itemsInCart[i+1:]...)
-					}
-					break
-				}
-			}
+		// Items are priced with current products, not with prices sent by client
+		req.Items[index].Name = product.ProductName
+		req.Items[index].ImageUrl = product.Image
+		req.Items[index].Price = product.Price
+		req.Items[index].Currency = product.Currency
+
+		totalAmount += float64(item.Quantity) * product.Price
+
+		if isInCart {
+			purchasedItems = append(purchasedItems, entity.CartItem{
+				ProductId: item.ProductId,
+				Quantity:  item.Quantity,
+			})
 		}
 
-		inventories[index] = *inventory
-		inventoryTxs[index] = entity.ProductInventoryTransaction{
-			TransactionId: utils.GenerateId(),
-			ProductId:     item.ProductId,
-			Amount:        int64(item.Quantity),
-			Action:        "Sale",
-			Date:          curTime,
-			CreatedAt:     curTime,
-			UpdatedAt:     curTime,
+		// Shipped from the nearest warehouses holding the item
+		saleTxs, err := allocateInventoryTransactions(item.ProductId, int64(item.Quantity), req.Latitude, req.Longitude, orderId, curTime, o.warehouseRepo, ctx)
+		if err != nil {
+			return err
 		}
+
+		inventoryTxs = append(inventoryTxs, saleTxs...)
+	}
+
+	if cart != nil {
+		cart.UpdatedAt = curTime
+	}
+
+	// Discount of VIP tier
+	totalAmount, err = applyVipTierDiscount(req.UserId, totalAmount, o.tierRepo, o.userTierRepo, ctx)
+	if err != nil {
+		return err
 	}
 
-	cart.Items = utils.ObjectToJsonString(itemsInCart)
-	cart.UpdatedAt = curTime
+	// Take items out of stock first, nothing is taken if one of them ran out meanwhile
+	if err := appendInventoryTransactions(inventoryTxs, o.hooks, o.inventoryTxRepo, o.logger, ctx); err != nil {
+		return err
+	}
 
 	_, cancel := context.WithCancel(ctx)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var capturedErr error
 
-	wg.Add(4)
-
-	// Create inventory transaction
-	go func() {
-		defer wg.Done()
-		for _, tx := range inventoryTxs {
-			if err := o.inventoryTxRepo.CreateProductInventoryTransaction(tx, ctx); err != nil {
-				mu.Lock()
-				if capturedErr == nil {
-					capturedErr = err
-					cancel()
-				}
-				mu.Unlock()
-			}
-		}
-	}()
-
-	// Update inventories
-	go func() {
-		defer wg.Done()
-		for _, inventory := range inventories {
-			if err := o.inventoryRepo.UpdateProductInventory(inventory, ctx); err != nil {
-				mu.Lock()
-				if capturedErr == nil {
-					capturedErr = err
-					cancel()
-				}
-				mu.Unlock()
-			}
-		}
-	}()
+	wg.Add(2)
 
 	// Create order
 	go func() {
 		defer wg.Done()
 		if err := o.orderRepo.CreateOrder(entity.Order{
-			OrderId:     utils.GenerateId(),
+			OrderId:     orderId,
 			UserId:      req.UserId,
 			Items:       utils.ObjectToJsonString(req.Items),
 			TotalAmount: totalAmount,
@@ -194,8 +207,8 @@ func (o *orderService) CreateOrder(req request.CreateOrderRequest, ctx context.C
 	// Update cart if existed
 	go func() {
 		defer wg.Done()
-		if cart != nil {
-			if err := o.cartRepo.UpdateCart(*cart, ctx); err != nil {
+		if cart != nil && len(purchasedItems) > 0 {
+			if err := o.cartRepo.DeductCartItems(*cart, purchasedItems, ctx); err != nil {
 				mu.Lock()
 				if capturedErr == nil {
 					capturedErr = err
@@ -6,10 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
 	"sonit_server/constant/currency"
 	domain_status "sonit_server/constant/domain_status"
-	payment_env "sonit_server/constant/env/payment"
 	mail_const "sonit_server/constant/mail_const"
 	"sonit_server/constant/noti"
 	payment_method "sonit_server/constant/payment_method"
@@ -21,7 +19,7 @@ import (
 	"sonit_server/model/dto/request"
 	"sonit_server/model/dto/response"
 	"sonit_server/model/entity"
-	"strings"
+	"sonit_server/utils/config"
 	"time"
 
 	"sonit_server/utils"
@@ -30,26 +28,38 @@ import (
 )
 
 type paymentService struct {
-	logger       *log.Logger
-	userRepo     data_access.IUserRepo
-	cartRepo     data_access.ICartRepo
-	invetoryRepo data_access.IProductInventoryRepo
-	productRepo  data_access.IProductRepo
-	shippingRepo data_access.IShippingRepo
-	orderRepo    data_access.IOrderRepo
-	paymentRepo  data_access.IPaymentRepo
+	logger          *log.Logger
+	userRepo        data_access.IUserRepo
+	cartRepo        data_access.ICartRepo
+	invetoryRepo    data_access.IProductInventoryRepo
+	inventoryTxRepo data_access.IProductInventoryTransactionRepo
+	warehouseRepo   data_access.IWarehouseRepo
+	productRepo     data_access.IProductRepo
+	shippingRepo    data_access.IShippingRepo
+	orderRepo       data_access.IOrderRepo
+	paymentRepo     data_access.IPaymentRepo
+	tierRepo        data_access.IVipTierRepo
+	userTierRepo    data_access.IUserTierRepo
+	hooks           []inventoryHook
 }
 
 func InitializePaymentService(db *sql.DB, logger *log.Logger) business_logic.IPaymentService {
+	var inventoryRepo = repo.InitializeProductInventoryRepo(db, logger)
+
 	return &paymentService{
-		logger:       logger,
-		userRepo:     repo.InitializeUserRepo(db, logger),
-		cartRepo:     repo.InitializeCartRepo(db, logger),
-		invetoryRepo: repo.InitializeProductInventoryRepo(db, logger),
-		productRepo:  repo.InitializeProductRepo(db, logger),
-		shippingRepo: repo.InitializeShippingRepo(db, logger),
-		orderRepo:    repo.InitializeOrderRepo(db, logger),
-		paymentRepo:  repo.InitializePaymentRepo(db, logger),
+		logger:          logger,
+		userRepo:        repo.InitializeUserRepo(db, logger),
+		cartRepo:        repo.InitializeCartRepo(db, logger),
+		invetoryRepo:    inventoryRepo,
+		inventoryTxRepo: repo.InitializeProductInventoryTransactionRepo(db, logger),
+		warehouseRepo:   repo.InitializeWarehouseRepo(db, logger),
+		productRepo:     repo.InitializeCachedProductRepo(db, logger),
+		shippingRepo:    repo.InitializeShippingRepo(db, logger),
+		orderRepo:       repo.InitializeOrderRepo(db, logger),
+		paymentRepo:     repo.InitializePaymentRepo(db, logger),
+		tierRepo:        repo.InitializeCachedVipTierRepo(db, logger),
+		userTierRepo:    repo.InitializeUserTierRepo(db, logger),
+		hooks:           getInventoryHooks(repo.InitializeStockSubscriptionRepo(db, logger), inventoryRepo, logger),
 	}
 }
 
@@ -144,15 +154,22 @@ func (p *paymentService) CreatePaymentThroughCart(req request.CreatePaymentThrou
 		return res, errRes
 	}
 
-	var itemsInCart []response.CartItem = utils.JsonStringToObject[[]response.CartItem](cart.Items)
+	itemsInCart, err := getCartItemsByProduct(cart.UserId, p.cartRepo, ctx)
+	if err != nil {
+		return res, err
+	}
+
+	var purchasedItems []entity.CartItem
 	var totalAmount float64
 	var items []payos.Item
-	var invetories []entity.ProductInventory
+	var inventoryTxs []entity.ProductInventoryTransaction
 	var formatItems []response.CartItem
+	var orderId string = utils.GenerateId()
 
 	for _, prod := range req.Items {
 		// Item not existed in cart
-		if !strings.Contains(cart.Items, prod.ProductId) {
+		line, isExisted := itemsInCart[prod.ProductId]
+		if !isExisted {
 			return res, errRes
 		}
 
@@ -161,26 +178,28 @@ func (p *paymentService) CreatePaymentThroughCart(req request.CreatePaymentThrou
 			return res, err
 		}
 
-		if inventory == nil {
-			return res, errRes
-		}
-
 		product, err := p.productRepo.GetProductById(prod.ProductId, ctx)
 		if err != nil {
 			return res, err
 		}
 
-		if product == nil {
-			return res, errRes
+		// Line repriced, out of stock or unavailable since acknowledged by the shopper
+		if getCartLineStatus(entity.CartItem{
+			ProductId: prod.ProductId,
+			Quantity:  prod.Quantity,
+			UnitPrice: line.UnitPrice,
+		}, product, inventory) != domain_status.CART_LINE_AVAILABLE {
+			return res, errors.New(noti.CART_CHANGED_WARN_MSG)
 		}
 
-		if inventory.CurrentQuantity < int64(prod.Quantity) {
-			return res, errRes
+		// Shipped from the nearest warehouses holding the item
+		saleTxs, err := allocateInventoryTransactions(prod.ProductId, int64(prod.Quantity), req.Latitude, req.Longitude, orderId, time.Now(), p.warehouseRepo, ctx)
+		if err != nil {
+			return res, err
 		}
 
-		inventory.CurrentQuantity -= int64(prod.Quantity)
 		totalAmount += float64(prod.Quantity) * product.Price
-		invetories = append(invetories, *inventory)
+		inventoryTxs = append(inventoryTxs, saleTxs...)
 
 		items = append(items, payos.Item{
 			Name:     product.ProductName,
@@ -197,16 +216,16 @@ func (p *paymentService) CreatePaymentThroughCart(req request.CreatePaymentThrou
 			Currency:  product.Currency,
 		})
 
-		for index, item := range itemsInCart {
-			if item.ProductId == prod.ProductId {
-				itemsInCart[index].Quantity -= prod.Quantity
-				if itemsInCart[index].Quantity <= 0 { // Remove item from cart
-					itemsInCart = append(itemsInCart[:index], itemsInCart[index+1:]...)
-				}
+		purchasedItems = append(purchasedItems, entity.CartItem{
+			ProductId: prod.ProductId,
+			Quantity:  prod.Quantity,
+		})
+	}
 
-				break
-			}
-		}
+	// Discount of VIP tier
+	totalAmount, err = applyVipTierDiscount(req.UserId, totalAmount, p.tierRepo, p.userTierRepo, ctx)
+	if err != nil {
+		return res, err
 	}
 
 	var paymentId string = utils.GenerateId()
@@ -218,8 +237,8 @@ func (p *paymentService) CreatePaymentThroughCart(req request.CreatePaymentThrou
 		Amount:      int(totalAmount),
 		Items:       items,
 		Description: fmt.Sprint(orderCode),
-		ReturnUrl:   os.Getenv(payment_env.PAYMENT_CALLBACK_SUCCESS_PROCESS) + paymentId,
-		CancelUrl:   os.Getenv(payment_env.PAYMENT_CALLBACK_CANCEL_PROCESS) + paymentId,
+		ReturnUrl:   config.Get().Payment.CallbackSuccessProcess + paymentId,
+		CancelUrl:   config.Get().Payment.CallbackCancelProcess + paymentId,
 	})
 
 	if err != nil {
@@ -227,26 +246,17 @@ func (p *paymentService) CreatePaymentThroughCart(req request.CreatePaymentThrou
 		return res, errors.New(noti.INTERNALL_ERR_MSG)
 	}
 
-	// Update product inventories
-	for _, inventory := range invetories {
-		if err := p.invetoryRepo.UpdateProductInventory(inventory, ctx); err != nil {
-			return res, err
-		}
+	// Take items out of stock, nothing is taken if one of them ran out meanwhile
+	if err := appendInventoryTransactions(inventoryTxs, p.hooks, p.inventoryTxRepo, p.logger, ctx); err != nil {
+		return res, err
 	}
 
 	var curTime time.Time = time.Now()
 
-	// No more items in cart
-	if len(itemsInCart) == 0 {
-		p.cartRepo.RemoveCart(req.UserId, ctx)
-	} else {
-		cart.Items = utils.ObjectToJsonString(itemsInCart)
-		cart.UpdatedAt = curTime
-		cart.ExpiredAt = curTime.AddDate(0, 0, 7)
-		p.cartRepo.UpdateCart(*cart, ctx)
-	}
-
-	var orderId string = utils.GenerateId()
+	// Bought items leave the cart, cart is removed once empty
+	cart.UpdatedAt = curTime
+	cart.ExpiredAt = curTime.AddDate(0, 0, 7)
+	p.cartRepo.DeductCartItems(*cart, purchasedItems, ctx)
 
 	// Create order
 	if err := p.orderRepo.CreateOrder(entity.Order{
@@ -337,7 +347,12 @@ func (p *paymentService) CreatePaymentDirect(req request.CreatePaymentDirectRequ
 
 	var paymentId string = utils.GenerateId()
 	var orderCode int = utils.GenerateNumber()
-	var totalAmount float64 = product.Price * float64(req.Product.Quantity)
+
+	// Discount of VIP tier
+	totalAmount, err := applyVipTierDiscount(req.UserId, product.Price*float64(req.Product.Quantity), p.tierRepo, p.userTierRepo, ctx)
+	if err != nil {
+		return res, err
+	}
 
 	// Create transaction url
 	data, err := payos.CreatePaymentLink(payos.CheckoutRequestType{
@@ -351,8 +366,8 @@ func (p *paymentService) CreatePaymentDirect(req request.CreatePaymentDirectRequ
 			},
 		},
 		Description: fmt.Sprint(orderCode),
-		ReturnUrl:   os.Getenv(payment_env.PAYMENT_CALLBACK_SUCCESS_PROCESS) + paymentId,
-		CancelUrl:   os.Getenv(payment_env.PAYMENT_CALLBACK_CANCEL_PROCESS) + paymentId,
+		ReturnUrl:   config.Get().Payment.CallbackSuccessProcess + paymentId,
+		CancelUrl:   config.Get().Payment.CallbackCancelProcess + paymentId,
 	})
 
 	if err != nil {
@@ -360,15 +375,18 @@ func (p *paymentService) CreatePaymentDirect(req request.CreatePaymentDirectRequ
 		return res, errors.New(noti.INTERNALL_ERR_MSG)
 	}
 
-	inventory.CurrentQuantity -= int64(req.Product.Quantity)
+	var orderId string = utils.GenerateId()
+	var curTime time.Time = time.Now()
 
-	// Update inventory
-	if err := p.invetoryRepo.UpdateProductInventory(*inventory, ctx); err != nil {
+	// Take item out of stock of the nearest warehouses holding it
+	inventoryTxs, err := allocateInventoryTransactions(req.Product.ProductId, int64(req.Product.Quantity), req.Latitude, req.Longitude, orderId, curTime, p.warehouseRepo, ctx)
+	if err != nil {
 		return res, err
 	}
 
-	var orderId string = utils.GenerateId()
-	var curTime time.Time = time.Now()
+	if err := appendInventoryTransactions(inventoryTxs, p.hooks, p.inventoryTxRepo, p.logger, ctx); err != nil {
+		return res, err
+	}
 
 	// Create order
 	if err := p.orderRepo.CreateOrder(entity.Order{
@@ -519,7 +537,7 @@ func (p *paymentService) CallbackPaymentSuccess(id string, ctx context.Context)
 	// 	Items:       utils.JsonStringToObject[[]response.CartItem](order.Items),
 	// }, nil
 
-	return os.Getenv(payment_env.PAYMENT_CALLBACK_SUCCESS) + id, nil
+	return config.Get().Payment.CallbackSuccess + id, nil
 }
 
 // CallbackPaymentCancel implements businesslogic.IPaymentService.
@@ -570,13 +588,10 @@ func (p *paymentService) CallbackPaymentCancel(id string, ctx context.Context) (
 		return "", err
 	}
 
-	// Refund product amount
-	for _, item := range utils.JsonStringToObject[[]response.CartItem](order.Items) {
-		inventory, _ := p.invetoryRepo.GetProductInventory(item.ProductId, ctx)
-		if inventory != nil {
-			inventory.CurrentQuantity += int64(item.Quantity)
-			p.invetoryRepo.UpdateProductInventory(*inventory, ctx)
-		}
+	// Refund product amount to the warehouses it was taken from
+	refundTxs, _ := getOrderRefundTransactions(*order, p.inventoryTxRepo, ctx)
+	for _, tx := range refundTxs {
+		appendInventoryTransactions([]entity.ProductInventoryTransaction{tx}, p.hooks, p.inventoryTxRepo, p.logger, ctx)
 	}
 
 	p.shippingRepo.RemoveShipping(order.OrderId, ctx)
@@ -594,5 +609,5 @@ func (p *paymentService) CallbackPaymentCancel(id string, ctx context.Context) (
 		})
 	}
 
-	return os.Getenv(payment_env.PAYMENT_CALLBACK_CANCEL) + id, nil
+	return config.Get().Payment.CallbackCancel + id, nil
 }
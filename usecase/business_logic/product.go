@@ -22,20 +22,26 @@ import (
 )
 
 type productService struct {
-	logger               *log.Logger
-	categoryRepo         data_access.ICategoryRepo
-	collectionRepo       data_access.ICollectionRepo
-	productInventoryRepo data_access.IProductInventoryRepo
-	productRepo          data_access.IProductRepo
+	logger                 *log.Logger
+	categoryRepo           data_access.ICategoryRepo
+	collectionRepo         data_access.ICollectionRepo
+	productInventoryRepo   data_access.IProductInventoryRepo
+	productInventoryTxRepo data_access.IProductInventoryTransactionRepo
+	productRepo            data_access.IProductRepo
+	hooks                  []inventoryHook
 }
 
 func InitializeProductService(db *sql.DB, logger *log.Logger) business_logic.IProductService {
+	var productInventoryRepo = repo.InitializeProductInventoryRepo(db, logger)
+
 	return &productService{
-		logger:               logger,
-		categoryRepo:         repo.InitializeCategoryRepo(db, logger),
-		collectionRepo:       repo.InitializeCollectionRepo(db, logger),
-		productInventoryRepo: repo.InitializeProductInventoryRepo(db, logger),
-		productRepo:          repo.InitializeProductRepo(db, logger),
+		logger:                 logger,
+		categoryRepo:           repo.InitializeCachedCategoryRepo(db, logger),
+		collectionRepo:         repo.InitializeCachedCollectionRepo(db, logger),
+		productInventoryRepo:   productInventoryRepo,
+		productInventoryTxRepo: repo.InitializeProductInventoryTransactionRepo(db, logger),
+		productRepo:            repo.InitializeCachedProductRepo(db, logger),
+		hooks:                  getInventoryHooks(repo.InitializeStockSubscriptionRepo(db, logger), productInventoryRepo, logger),
 	}
 }
 
@@ -161,8 +167,7 @@ func (p *productService) CreateProduct(req request.CreateProductRequest, ctx con
 		defer wg.Done()
 
 		if err := p.productInventoryRepo.CreateProductInventory(entity.ProductInventory{
-			ProductId:       productId,
-			CurrentQuantity: req.Amount,
+			ProductId: productId,
 		}, ctx); err != nil {
 			mu.Lock()
 			if capturedErr == nil {
@@ -179,6 +184,13 @@ func (p *productService) CreateProduct(req request.CreateProductRequest, ctx con
 	// Wait for 2 goroutines to finish
 	wg.Wait()
 
+	// Initial quantity is recorded to the ledger, stocked at the default warehouse
+	if capturedErr == nil && req.Amount > 0 {
+		capturedErr = appendInventoryTransactions([]entity.ProductInventoryTransaction{
+			generateInventoryTransaction(productId, getDefaultWarehouseId(), Import_action, req.Amount, "Initial stock", time.Time{}),
+		}, p.hooks, p.productInventoryTxRepo, p.logger, ctx)
+	}
+
 	return capturedErr
 }
 
@@ -418,12 +430,26 @@ func (p *productService) UpdateProduct(req request.UpdateProductRequest, ctx con
 		return err
 	}
 
+	// New quantity is recorded to the ledger as the difference with the current one, taken at the default warehouse
 	if req.Amount != nil {
-		go func() {
-			inventory, _ := p.productInventoryRepo.GetProductInventory(req.ProductId, ctx)
-			inventory.CurrentQuantity = *req.Amount
-			p.productInventoryRepo.UpdateProductInventory(*inventory, ctx)
-		}()
+		inventory, err := p.productInventoryRepo.GetProductInventory(req.ProductId, ctx)
+		if err != nil {
+			return err
+		}
+
+		if inventory != nil && *req.Amount >= 0 && *req.Amount != inventory.CurrentQuantity {
+			var action string = Import_action
+			if *req.Amount < inventory.CurrentQuantity {
+				action = Export_action
+			}
+
+			var amount int64 = *req.Amount - inventory.CurrentQuantity
+			if err := appendInventoryTransactions([]entity.ProductInventoryTransaction{
+				generateInventoryTransaction(req.ProductId, getDefaultWarehouseId(), action, max(amount, -amount), "Quantity set on product update", time.Time{}),
+			}, p.hooks, p.productInventoryTxRepo, p.logger, ctx); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Verify category if not empty
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"sonit_server/constant/noti"
 	repo "sonit_server/data_access"
@@ -14,7 +15,8 @@ import (
 	"sonit_server/model/dto/request"
 	"sonit_server/model/dto/response"
 	"sonit_server/model/entity"
-	"sync"
+	"sonit_server/utils/file"
+	"strings"
 	"time"
 
 	"sonit_server/utils"
@@ -25,14 +27,20 @@ type productInventoryTransactionService struct {
 	productRepo            data_access.IProductRepo
 	productInventoryRepo   data_access.IProductInventoryRepo
 	productInventoryTxRepo data_access.IProductInventoryTransactionRepo
+	warehouseRepo          data_access.IWarehouseRepo
+	hooks                  []inventoryHook
 }
 
 func InitializeProductInventoryTransactionService(db *sql.DB, logger *log.Logger) business_logic.IProductInventoryTransactionService {
+	var productInventoryRepo = repo.InitializeProductInventoryRepo(db, logger)
+
 	return &productInventoryTransactionService{
 		logger:                 logger,
-		productRepo:            repo.InitializeProductRepo(db, logger),
-		productInventoryRepo:   repo.InitializeProductInventoryRepo(db, logger),
+		productRepo:            repo.InitializeCachedProductRepo(db, logger),
+		productInventoryRepo:   productInventoryRepo,
 		productInventoryTxRepo: repo.InitializeProductInventoryTransactionRepo(db, logger),
+		warehouseRepo:          repo.InitializeWarehouseRepo(db, logger),
+		hooks:                  getInventoryHooks(repo.InitializeStockSubscriptionRepo(db, logger), productInventoryRepo, logger),
 	}
 }
 
@@ -54,28 +62,139 @@ var inventoryTx_cnn *sql.DB
 
 // CreateProductInventoryTransaction implements businesslogic.IProductInventoryTransactionService.
 func (p *productInventoryTransactionService) CreateProductInventoryTransaction(req request.CreateProductInventoryTransactionRequest, ctx context.Context) error {
+	defer closeCnn(inventoryTx_cnn)
+
+	if !p.isTransactionRequestValid(req, ctx) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	warehouse, err := getActiveWarehouse(req.WarehouseId, p.warehouseRepo, ctx)
+	if err != nil {
+		return err
+	}
+
+	// Quantity can't drop below zero
+	return appendInventoryTransactions([]entity.ProductInventoryTransaction{
+		generateInventoryTransaction(req.ProductId, warehouse.WarehouseId, req.Action, req.Amount, req.Note, req.Date),
+	}, p.hooks, p.productInventoryTxRepo, p.logger, ctx)
+}
+
+// TransferInventory implements businesslogic.IProductInventoryTransactionService.
+// Both entries are recorded at once, stock can leave an inactive warehouse but only reach an active one
+func (p *productInventoryTransactionService) TransferInventory(req request.TransferInventoryRequest, ctx context.Context) error {
 	var errRes error = errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	defer closeCnn(inventoryTx_cnn)
 
-	if !isEntityExist(p.productRepo, req.ProductId, id_type, ctx) {
+	if req.Amount <= 0 || req.FromWarehouseId == req.ToWarehouseId || !isEntityExist(p.productRepo, req.ProductId, id_type, ctx) {
 		return errRes
 	}
 
-	if !isInventoryActionValid(req.Action) {
-		return errRes
+	source, err := p.warehouseRepo.GetWarehouseById(req.FromWarehouseId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if source == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetWarehouseTable()))
+	}
+
+	destination, err := getActiveWarehouse(req.ToWarehouseId, p.warehouseRepo, ctx)
+	if err != nil {
+		return err
+	}
+
+	if req.Note == "" {
+		req.Note = "Transfer from " + source.WarehouseId + " to " + destination.WarehouseId
 	}
 
-	var curTime time.Time = time.Now()
-	return p.productInventoryTxRepo.CreateProductInventoryTransaction(entity.ProductInventoryTransaction{
-		TransactionId: utils.GenerateId(),
-		ProductId:     req.ProductId,
-		Amount:        req.Amount,
-		Action:        req.Action,
-		Note:          req.Note,
-		Date:          req.Date,
-		CreatedAt:     curTime,
-		UpdatedAt:     curTime,
-	}, ctx)
+	var transferId string = utils.GenerateId()
+	var transferOut = generateInventoryTransaction(req.ProductId, source.WarehouseId, Transfer_out_action, req.Amount, req.Note, time.Time{})
+	var transferIn = generateInventoryTransaction(req.ProductId, destination.WarehouseId, Transfer_in_action, req.Amount, req.Note, transferOut.Date)
+	transferOut.TransferId = &transferId
+	transferIn.TransferId = &transferId
+
+	// Product total is unchanged, no hook to run
+	_, err = p.productInventoryTxRepo.AppendProductInventoryTransactions([]entity.ProductInventoryTransaction{transferOut, transferIn}, ctx)
+	return err
+}
+
+// ImportInventoryTransactions implements businesslogic.IProductInventoryTransactionService.
+// Every row is validated first and reported with its line, nothing is recorded if one of them is invalid. Valid rows are recorded at once
+func (p *productInventoryTransactionService) ImportInventoryTransactions(req request.ImportInventoryRequest, ctx context.Context) (response.InventoryImportResponse, error) {
+	defer closeCnn(inventoryTx_cnn)
+
+	if req.Mode == "" {
+		req.Mode = transaction_import_mode
+	}
+
+	var res = response.InventoryImportResponse{
+		Mode:   req.Mode,
+		Errors: []response.InventoryImportError{},
+	}
+
+	if req.Mode != transaction_import_mode && req.Mode != stocktake_import_mode {
+		return res, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	warehouse, err := getActiveWarehouse(req.WarehouseId, p.warehouseRepo, ctx)
+	if err != nil {
+		return res, err
+	}
+
+	spreadsheet, err := file.ReadSpreadsheet(req.FileName, req.Data)
+	if err != nil {
+		return res, err
+	}
+
+	// Blank rows are skipped
+	var rows []file.SpreadsheetRow
+	for _, row := range spreadsheet {
+		if strings.TrimSpace(strings.Join(row.Cells, "")) != "" {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return res, errors.New(noti.INVENTORY_IMPORT_HEADER_WARN_MSG)
+	}
+
+	columns, isValid := getInventoryImportColumns(rows[0].Cells, req.Mode)
+	if !isValid {
+		return res, errors.New(noti.INVENTORY_IMPORT_HEADER_WARN_MSG)
+	}
+
+	res.Rows = len(rows) - 1
+	if res.Rows == 0 {
+		return res, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	txs, rowErrors, err := generateInventoryImportTransactions(rows[1:], columns, req.Mode, warehouse.WarehouseId, p.productRepo, p.warehouseRepo, ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if len(rowErrors) > 0 {
+		res.Errors = rowErrors
+		return res, nil
+	}
+
+	// Counts are compared with the stock when recorded, a stocktake matching every quantity records nothing
+	if req.Mode == stocktake_import_mode {
+		res.Transactions, err = appendStocktakeTransactions(txs, p.hooks, p.productInventoryTxRepo, p.logger, ctx)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		if err := appendInventoryTransactions(txs, p.hooks, p.productInventoryTxRepo, p.logger, ctx); err != nil {
+			return res, err
+		}
+
+		res.Transactions = len(txs)
+	}
+
+	res.IsApplied = true
+
+	return res, nil
 }
 
 // GetAllProductInventoryTransactions implements businesslogic.IProductInventoryTransactionService.
@@ -109,107 +228,62 @@ func (p *productInventoryTransactionService) GetProductInventoryTransaction(id s
 	return p.productInventoryTxRepo.GetProductInventoryTransaction(id, ctx)
 }
 
-// UpdateProductInventoryTransaction implements businesslogic.IProductInventoryTransactionService.
-func (p *productInventoryTransactionService) UpdateProductInventoryTransaction(req request.UpdateProductInventoryTransactionRequest, ctx context.Context) error {
+// CorrectProductInventoryTransaction implements businesslogic.IProductInventoryTransactionService.
+// Recorded transactions are never changed: the transaction is reversed by a correction linked to it, then the replacement is recorded
+func (p *productInventoryTransactionService) CorrectProductInventoryTransaction(req request.CorrectProductInventoryTransactionRequest, ctx context.Context) error {
 	var errRes error = errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	defer closeCnn(inventoryTx_cnn)
 
-	// Verify transaction
-	if !isEntityExist(p.productInventoryTxRepo, req.TransactionId, id_type, ctx) {
-		return errRes
+	originalTx, err := p.productInventoryTxRepo.GetProductInventoryTransaction(req.TransactionId, ctx)
+	if err != nil {
+		return err
 	}
 
-	// Verify product
-	if !isEntityExist(p.productRepo, req.UpdateData.ProductId, id_type, ctx) {
-		return errRes
+	if originalTx == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetProductInventoryTransactionTable()))
 	}
 
-	// Retrieve original transaction data
-	originalTx, err := p.productInventoryTxRepo.GetProductInventoryTransaction(req.TransactionId, ctx)
-	if err != nil {
-		return err
+	// A correction is fixed by correcting its replacement, a transfer by transferring back
+	if originalTx.Action == Correction_action || originalTx.TransferId != nil {
+		return errRes
 	}
 
-	// Retrieve product inventory
-	inventory, err := p.productInventoryRepo.GetProductInventory(originalTx.ProductId, ctx)
+	isCorrected, err := p.productInventoryTxRepo.IsProductInventoryTransactionCorrected(originalTx.TransactionId, ctx)
 	if err != nil {
 		return err
 	}
 
-	// Reverse previous action which affected to the inventory
-	inventory.CurrentQuantity += inverseInventoryTransaction(originalTx.Action, originalTx.Amount)
-
-	originalTx.Action = req.UpdateData.Action
-	originalTx.Amount = req.UpdateData.Amount
+	if isCorrected {
+		return errors.New(noti.INVENTORY_TX_CORRECTED_WARN_MSG)
+	}
 
-	if req.UpdateData.Note != "" {
-		originalTx.Note = req.UpdateData.Note
+	if req.Note == "" {
+		req.Note = "Correction of " + originalTx.TransactionId
 	}
 
-	var updatedAmount int64 = executeInventoryTransaction(req.UpdateData.Action, req.UpdateData.Amount)
+	var correction = generateInventoryTransaction(originalTx.ProductId, originalTx.WarehouseId, Correction_action, originalTx.Amount, req.Note, time.Time{})
+	correction.QuantityChange = -originalTx.QuantityChange
+	correction.CorrectsId = &originalTx.TransactionId
+	correction.OrderId = originalTx.OrderId
 
-	// Retrieve new affected product inventory if changed
-	if req.UpdateData.ProductId != originalTx.ProductId {
-		newAfftedInventory, err := p.productInventoryRepo.GetProductInventory(req.UpdateData.ProductId, ctx)
-		if err != nil {
-			return err
+	var txs = []entity.ProductInventoryTransaction{correction}
+	if req.Replacement != nil {
+		if !p.isTransactionRequestValid(*req.Replacement, ctx) {
+			return errRes
 		}
 
-		newAfftedInventory.CurrentQuantity += updatedAmount
-		if err := p.productInventoryRepo.UpdateProductInventory(*newAfftedInventory, ctx); err != nil {
+		warehouse, err := getActiveWarehouse(req.Replacement.WarehouseId, p.warehouseRepo, ctx)
+		if err != nil {
 			return err
 		}
-	} else {
-		inventory.CurrentQuantity += updatedAmount
-	}
 
-	var capturedErr error
-
-	_, cancel := context.WithCancel(ctx)
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	// Add 2 process: updating inventory and transaction
-	wg.Add(2)
-
-	originalTx.UpdatedAt = time.Now()
-
-	// Update inventory
-	go func() {
-		defer wg.Done()
-
-		if err := p.productInventoryRepo.UpdateProductInventory(*inventory, ctx); err != nil {
-			mu.Lock()
-			if capturedErr == nil {
-				// Assigning error
-				capturedErr = err
-				// Make a flag to cancel other goroutines
-				cancel()
-			}
-
-			mu.Unlock()
-		}
-	}()
-
-	// Update inventory transaction
-	go func() {
-		defer wg.Done()
-
-		if err := p.productInventoryTxRepo.UpdateProductInventoryTransaction(*originalTx, ctx); err != nil {
-			mu.Lock()
-			if capturedErr == nil {
-				// Assigning error
-				capturedErr = err
-				// Make a flag to cancel other goroutines
-				cancel()
-			}
-
-			mu.Unlock()
-		}
-	}()
+		txs = append(txs, generateInventoryTransaction(req.Replacement.ProductId, warehouse.WarehouseId, req.Replacement.Action, req.Replacement.Amount, req.Replacement.Note, req.Replacement.Date))
+	}
 
-	// Wait for 2 goroutines to finish
-	wg.Wait()
+	return appendInventoryTransactions(txs, p.hooks, p.productInventoryTxRepo, p.logger, ctx)
+}
 
-	return capturedErr
+// Product and action of a new transaction, corrections are only recorded by correcting a transaction
+func (p *productInventoryTransactionService) isTransactionRequestValid(req request.CreateProductInventoryTransactionRequest, ctx context.Context) bool {
+	return isEntityExist(p.productRepo, req.ProductId, id_type, ctx) && isInventoryActionValid(req.Action) && req.Amount > 0
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"sonit_server/constant/noti"
 	repo "sonit_server/data_access" // Role data access ~~ Role repository
 	"sonit_server/data_access/db"
@@ -14,13 +16,15 @@ import (
 	"sonit_server/model/dto/request"
 	entity "sonit_server/model/entity"
 	"sonit_server/utils"
+	"sonit_server/utils/config"
 	"strings"
 	"time"
 )
 
 type roleService struct {
-	roleRepo data_access.IRoleRepo
-	logger   *log.Logger
+	roleRepo       data_access.IRoleRepo
+	permissionRepo data_access.IPermissionRepo
+	logger         *log.Logger
 }
 
 func GenerateRoleService() (business_logic.IRoleService, error) {
@@ -39,8 +43,9 @@ func GenerateRoleService() (business_logic.IRoleService, error) {
 
 func InitializeRoleService(db *sql.DB, logger *log.Logger) business_logic.IRoleService {
 	return &roleService{
-		roleRepo: repo.InitializeRoleRepo(db, logger),
-		logger:   logger,
+		roleRepo:       repo.InitializeCachedRoleRepo(db, logger),
+		permissionRepo: repo.InitializeCachedPermissionRepo(db, logger),
+		logger:         logger,
 	}
 }
 
@@ -149,11 +154,82 @@ func (r *roleService) UpdateRole(req request.UpdateRoleRequest, ctx context.Cont
 		return err
 	}
 
-	if req.RoleName == "" {
+	if role == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetRoleTable()))
+	}
+
+	if strings.TrimSpace(req.RoleName) == "" {
 		return nil
 	}
 
-	role.RoleName = strings.TrimSpace(role.RoleName)
+	role.RoleName = strings.TrimSpace(req.RoleName)
 	role.UpdatedAt = time.Now()
 	return r.roleRepo.UpdateRole(*role, ctx)
 }
+
+// GetAllPermissions implements business_logic.IRoleService.
+func (r *roleService) GetAllPermissions(ctx context.Context) (*[]entity.Permission, error) {
+	defer closeCnn(role_cnn)
+	return r.permissionRepo.GetAllPermissions(ctx)
+}
+
+// GetRolePermissions implements business_logic.IRoleService.
+func (r *roleService) GetRolePermissions(id string, ctx context.Context) (*[]string, error) {
+	defer closeCnn(role_cnn)
+
+	if !isEntityExist(r.roleRepo, id, id_type, ctx) {
+		return nil, errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetRoleTable()))
+	}
+
+	return r.permissionRepo.GetPermissionsByRole(id, ctx)
+}
+
+// SetRolePermissions implements business_logic.IRoleService.
+func (r *roleService) SetRolePermissions(req request.SetRolePermissionsRequest, ctx context.Context) error {
+	defer closeCnn(role_cnn)
+
+	if !isEntityExist(r.roleRepo, req.RoleId, id_type, ctx) {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetRoleTable()))
+	}
+
+	catalog, err := r.permissionRepo.GetAllPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only permissions of the catalog can be granted
+	var permissions []string
+	for _, permission := range req.Permissions {
+		permission = strings.TrimSpace(permission)
+		if slices.Contains(permissions, permission) {
+			continue
+		}
+
+		if !slices.ContainsFunc(*catalog, func(x entity.Permission) bool { return x.PermissionId == permission }) {
+			return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	return r.permissionRepo.SetRolePermissions(req.RoleId, permissions, ctx)
+}
+
+// HasPermissions implements business_logic.IRoleService.
+func (r *roleService) HasPermissions(roleId string, permissions []string, ctx context.Context) (bool, error) {
+	defer closeCnn(role_cnn)
+	return hasPermissions(roleId, permissions, r.permissionRepo, ctx)
+}
+
+// GrantAdminPermissions implements business_logic.IRoleService.
+// The configured admin role holds every permission, including the ones added by later migrations
+func (r *roleService) GrantAdminPermissions(ctx context.Context) (int, error) {
+	defer closeCnn(role_cnn)
+
+	var roleId = config.Get().Role.AdminRole
+	if !isEntityExist(r.roleRepo, roleId, id_type, ctx) {
+		return 0, errors.New("admin role " + roleId + " (ADMIN_ROLE) does not exist")
+	}
+
+	return r.permissionRepo.GrantAllPermissions(roleId, ctx)
+}
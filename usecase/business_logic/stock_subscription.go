@@ -0,0 +1,133 @@
+package businesslogic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	domain_status "sonit_server/constant/domain_status"
+	"sonit_server/constant/noti"
+	repo "sonit_server/data_access"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	business_logic "sonit_server/interface/business_logic"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/entity"
+	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"time"
+)
+
+type stockSubscriptionService struct {
+	userRepo              data_access.IUserRepo
+	productRepo           data_access.IProductRepo
+	inventoryRepo         data_access.IProductInventoryRepo
+	stockSubscriptionRepo data_access.IStockSubscriptionRepo
+	logger                *log.Logger
+}
+
+func GenerateStockSubscriptionService() (business_logic.IStockSubscriptionService, error) {
+	var logger = utils.GetLogConfig()
+
+	cnn, err := db.ConnectDB(logger, db_server.InitializePostgreSQL())
+
+	if err != nil {
+		return nil, err
+	}
+
+	stock_subscription_cnn = cnn
+
+	return InitializeStockSubscriptionService(cnn, logger), nil
+}
+
+func InitializeStockSubscriptionService(db *sql.DB, logger *log.Logger) business_logic.IStockSubscriptionService {
+	return &stockSubscriptionService{
+		userRepo:              repo.InitializeUserRepo(db, logger),
+		productRepo:           repo.InitializeCachedProductRepo(db, logger),
+		inventoryRepo:         repo.InitializeProductInventoryRepo(db, logger),
+		stockSubscriptionRepo: repo.InitializeStockSubscriptionRepo(db, logger),
+		logger:                logger,
+	}
+}
+
+var stock_subscription_cnn *sql.DB
+
+// Failed mails of a subscription before giving up
+const stock_notification_max_attempts int = 3
+
+// GetStockSubscriptions implements businesslogic.IStockSubscriptionService.
+func (s *stockSubscriptionService) GetStockSubscriptions(userId string, ctx context.Context) (*[]entity.StockSubscription, error) {
+	defer closeCnn(stock_subscription_cnn)
+	return s.stockSubscriptionRepo.GetStockSubscriptions(userId, ctx)
+}
+
+// SubscribeToStock implements businesslogic.IStockSubscriptionService.
+func (s *stockSubscriptionService) SubscribeToStock(req request.StockSubscriptionRequest, ctx context.Context) error {
+	defer closeCnn(stock_subscription_cnn)
+
+	if !isEntityExist(s.userRepo, req.UserId, id_type, ctx) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	product, err := s.productRepo.GetProductById(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if product == nil || !product.ActiveStatus {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	inventory, err := s.inventoryRepo.GetProductInventory(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if inventory != nil && inventory.CurrentQuantity > 0 {
+		return errors.New(noti.PRODUCT_IN_STOCK_WARN_MSG)
+	}
+
+	var curTime time.Time = time.Now()
+	return s.stockSubscriptionRepo.SaveStockSubscription(entity.StockSubscription{
+		SubscriptionId: utils.GenerateId(),
+		UserId:         req.UserId,
+		ProductId:      req.ProductId,
+		Status:         domain_status.STOCK_SUBSCRIPTION_WAITING,
+		CreatedAt:      curTime,
+		UpdatedAt:      curTime,
+	}, ctx)
+}
+
+// UnsubscribeFromStock implements businesslogic.IStockSubscriptionService.
+func (s *stockSubscriptionService) UnsubscribeFromStock(req request.StockSubscriptionRequest, ctx context.Context) error {
+	defer closeCnn(stock_subscription_cnn)
+	return s.stockSubscriptionRepo.RemoveStockSubscription(req.UserId, req.ProductId, ctx)
+}
+
+// SendBackInStockNotifications implements businesslogic.IStockSubscriptionService.
+// Only a batch of the oldest queued subscriptions is mailed per run, the rest waits for next runs
+func (s *stockSubscriptionService) SendBackInStockNotifications(ctx context.Context) (int, error) {
+	defer closeCnn(stock_subscription_cnn)
+
+	subscriptions, err := s.stockSubscriptionRepo.ClaimQueuedStockSubscriptions(config.Get().Inventory.StockNotificationBatch, time.Now(), ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var res int
+	for _, subscription := range *subscriptions {
+		isSent, err := sendBackInStockNotice(subscription, s.logger, s.userRepo, s.productRepo, s.inventoryRepo, s.stockSubscriptionRepo, ctx)
+		if err != nil {
+			s.logger.Println("Back in stock notice of subscription " + subscription.SubscriptionId + " failed - " + err.Error())
+			s.stockSubscriptionRepo.RequeueStockSubscription(subscription.SubscriptionId, stock_notification_max_attempts, time.Now(), ctx)
+			continue
+		}
+
+		if isSent {
+			res++
+		}
+	}
+
+	return res, nil
+}
@@ -6,13 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
-	"sonit_server/constant/env"
-	"sonit_server/constant/env/auth"
+	action_type "sonit_server/constant/action_type"
+	domain_status "sonit_server/constant/domain_status"
 	mail_const "sonit_server/constant/mail_const"
 	"sonit_server/constant/noti"
 	page_url "sonit_server/constant/page_url"
+	"sonit_server/constant/permission"
 	repo "sonit_server/data_access" // Role data access ~~ Role repository
+	"sonit_server/data_access/cache"
 	"sonit_server/data_access/db"
 	db_server "sonit_server/data_access/db_server"
 	business_logic "sonit_server/interface/business_logic"
@@ -20,6 +21,7 @@ import (
 	"sonit_server/model/dto/response"
 	entity "sonit_server/model/entity"
 	"sonit_server/utils"
+	"sonit_server/utils/config"
 	"strings"
 	"sync"
 	"time"
@@ -32,6 +34,24 @@ type userService struct {
 	roleRepo         data_access.IRoleRepo
 	userSecurityRepo data_access.IUserSecurityRepo
 	userRepo         data_access.IUserRepo
+	sessionRepo      data_access.ISessionRepo
+	lockoutRepo      data_access.ILockoutEventRepo
+	mfaRepo          data_access.IMfaRepo
+	identityRepo     data_access.IIdentityRepo
+	permissionRepo   data_access.IPermissionRepo
+	historyRepo      data_access.IPasswordHistoryRepo
+	deletionRepo     data_access.IAccountDeletionRepo
+	orderRepo        data_access.IOrderRepo
+	paymentRepo      data_access.IPaymentRepo
+	shippingRepo     data_access.IShippingRepo
+	cartRepo         data_access.ICartRepo
+	productRepo      data_access.IProductRepo
+	inventoryRepo    data_access.IProductInventoryRepo
+	guestCartRepo    data_access.IGuestCartRepo
+	wishlistRepo     data_access.IWishlistRepo
+	userTierRepo     data_access.IUserTierRepo
+	reminderRepo     data_access.ICartReminderRepo
+	subscriptionRepo data_access.IStockSubscriptionRepo
 }
 
 func InitializeUserService(db *sql.DB, logger *log.Logger) business_logic.IUserService {
@@ -40,6 +60,24 @@ func InitializeUserService(db *sql.DB, logger *log.Logger) business_logic.IUserS
 		roleRepo:         repo.InitializeRoleRepo(db, logger),
 		userSecurityRepo: repo.InitializeUserSecurityRepo(db, logger),
 		userRepo:         repo.InitializeUserRepo(db, logger),
+		sessionRepo:      repo.InitializeSessionRepo(db, logger),
+		lockoutRepo:      repo.InitializeLockoutEventRepo(db, logger),
+		mfaRepo:          repo.InitializeMfaRepo(db, logger),
+		identityRepo:     repo.InitializeIdentityRepo(db, logger),
+		permissionRepo:   repo.InitializeCachedPermissionRepo(db, logger),
+		historyRepo:      repo.InitializePasswordHistoryRepo(db, logger),
+		deletionRepo:     repo.InitializeAccountDeletionRepo(db, logger),
+		orderRepo:        repo.InitializeOrderRepo(db, logger),
+		paymentRepo:      repo.InitializePaymentRepo(db, logger),
+		shippingRepo:     repo.InitializeShippingRepo(db, logger),
+		cartRepo:         repo.InitializeCartRepo(db, logger),
+		productRepo:      repo.InitializeCachedProductRepo(db, logger),
+		inventoryRepo:    repo.InitializeProductInventoryRepo(db, logger),
+		guestCartRepo:    repo.InitializeGuestCartRepo(db, logger),
+		wishlistRepo:     repo.InitializeWishlistRepo(db, logger),
+		userTierRepo:     repo.InitializeUserTierRepo(db, logger),
+		reminderRepo:     repo.InitializeCartReminderRepo(db, logger),
+		subscriptionRepo: repo.InitializeStockSubscriptionRepo(db, logger),
 	}
 }
 
@@ -105,7 +143,7 @@ func (u *userService) ChangeUserStatus(req request.ChangeUserStatusRequest, ctx
 	}
 
 	// Validate actor and affected account
-	if err := validateActorAndAffectedAccount(req.ActorId, req.UserId, u.userRepo, ctx); err != nil {
+	if err := validateActorAndAffectedAccount(req.ActorId, req.UserId, u.userRepo, u.permissionRepo, ctx); err != nil {
 		return "", err
 	}
 
@@ -120,7 +158,7 @@ func (u *userService) ChangeUserStatus(req request.ChangeUserStatusRequest, ctx
 	if !status {
 		// User locking their own account
 		if req.ActorId == req.UserId {
-			return os.Getenv(auth.LOGIN_PAGE_URL), nil
+			return config.Get().Auth.LoginPageUrl, nil
 		}
 	}
 
@@ -134,37 +172,32 @@ func (u *userService) CreateUser(req request.CreateUserRequest, ctx context.Cont
 	// Admin create new account
 	if req.ActorId != "" {
 		// Verify admin account
-		if err := validateActorAndAffectedAccount(req.ActorId, req.ActorId, u.userRepo, ctx); err != nil {
+		if err := validateActorAndAffectedAccount(req.ActorId, req.ActorId, u.userRepo, u.permissionRepo, ctx); err != nil {
 			return "", err
 		}
 	}
 
 	// Email registered
-	var check = isEntityExist(u.userRepo, email_type, req.Email, ctx)
+	var check = isEntityExist(u.userRepo, req.Email, email_type, ctx)
 	u.logger.Println(check)
 	if check {
 		return "", errors.New(noti.EMAIL_REGISTERED_WARN_MSG)
 	}
 
-	// Check password secure
-	if !utils.IsPasswordSecure(req.Password) {
-		return "", errors.New(noti.PASSWORD_NOT_SECURE_WARN_MSG)
-	}
-
 	if req.ProfileAvatar != "" {
 		// if !InitializeImageService(req.ProfileAvatar).IsImageValid() {
 		// 	return "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
 		// }
 	}
 
-	// Hash password
-	hashPw, err := utils.ToHashString(req.Password, u.logger)
+	// Check password policy and hash password
+	hashPw, err := preparePassword(entity.User{}, req.Password, u.historyRepo, u.logger, ctx)
 	if err != nil {
 		return "", err
 	}
 
 	if req.RoleId == "" {
-		req.RoleId = os.Getenv(env.USER_ROLE)
+		req.RoleId = config.Get().Role.UserRole
 	}
 
 	if req.Gender == "" {
@@ -212,6 +245,10 @@ func (u *userService) CreateUser(req request.CreateUserRequest, ctx context.Cont
 		return "", err
 	}
 
+	if err := recordPasswordHistory(userId, hashPw, u.historyRepo, ctx); err != nil {
+		return "", err
+	}
+
 	// Create account security
 
 	if err := u.userSecurityRepo.CreateUserSecurity(entity.UserSecurity{
@@ -223,6 +260,11 @@ func (u *userService) CreateUser(req request.CreateUserRequest, ctx context.Cont
 		return "", err
 	}
 
+	// Shopper registering from the storefront keeps its guest cart
+	if req.ActorId == "" {
+		mergeGuestCart(userId, req.CartToken, u.logger, u.guestCartRepo, ctx)
+	}
+
 	// Send confirmation mail
 	if err := utils.SendMail(request.SendMailRequest{
 		Body: request.MailBody{ // Mail body
@@ -230,7 +272,7 @@ func (u *userService) CreateUser(req request.CreateUserRequest, ctx context.Cont
 			Password: req.Password,
 			Subject:  noti.REGISTRATION_ACCOUNT_MAIL_SUBJECT,
 			Url: utils.ToCombinedString([]string{ // Call back url when guest clicks to the confirmation, it will call back to the api endpoint which generate here to verify and finish the registration process
-				os.Getenv(auth.PROCESS_ACTION_URL),
+				config.Get().Auth.ProcessActionUrl,
 				token,
 				userId,
 				activateType,
@@ -253,6 +295,70 @@ func (u *userService) CreateUser(req request.CreateUserRequest, ctx context.Cont
 	return msg, nil
 }
 
+// CreateAdminAccount implements businesslogic.IUserService.
+func (u *userService) CreateAdminAccount(req request.CreateUserRequest, ctx context.Context) (string, error) {
+	defer closeCnn(user_cnn)
+
+	// Email registered
+	if isEntityExist(u.userRepo, req.Email, email_type, ctx) {
+		return "", errors.New(noti.EMAIL_REGISTERED_WARN_MSG)
+	}
+
+	// Admin role must be available
+	if !isEntityExist(u.roleRepo, config.Get().Role.AdminRole, id_type, ctx) {
+		return "", errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Admin role"))
+	}
+
+	// Check password policy and hash password
+	hashPw, err := preparePassword(entity.User{}, req.Password, u.historyRepo, u.logger, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var fullName string = req.FullName
+	if fullName == "" {
+		fullName = req.Email
+	}
+
+	if req.Gender == "" {
+		req.Gender = "Unknown"
+	}
+
+	var userId string = utils.GenerateId()
+	var curTime time.Time = time.Now()
+	var tmpTime time.Time = utils.GetPrimitiveTime()
+
+	// Admin account is created by operator so it is activated without mail verification
+	if err := u.userRepo.CreateUser(entity.User{
+		UserId:      userId,
+		RoleId:      config.Get().Role.AdminRole,
+		FullName:    fullName,
+		Email:       req.Email,
+		Password:    hashPw,
+		Gender:      req.Gender,
+		IsActive:    true,
+		IsActivated: true,
+		CreatedAt:   curTime,
+		UpdatedAt:   curTime,
+	}, ctx); err != nil {
+		return "", err
+	}
+
+	if err := recordPasswordHistory(userId, hashPw, u.historyRepo, ctx); err != nil {
+		return "", err
+	}
+
+	if err := u.userSecurityRepo.CreateUserSecurity(entity.UserSecurity{
+		UserId:     userId,
+		FailAccess: 0,
+		LastFail:   &tmpTime,
+	}, ctx); err != nil {
+		return "", err
+	}
+
+	return userId, nil
+}
+
 // GetAllUsers implements businesslogic.IUserService.
 func (u *userService) GetAllUsers(pageNumber int, ctx context.Context) (response.PaginationDataResponse, error) {
 	if pageNumber < 1 {
@@ -361,68 +467,162 @@ func (u *userService) Login(req request.LoginRequest, ctx context.Context) (stri
 		return "", "", err
 	}
 
+	security, err := u.userSecurityRepo.GetUserSecurity(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if security == nil {
+		return "", "", errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Account"))
+	}
+
+	// Locked account is rejected before checking password
+	if err := checkAccountLock(security, req.IpAddress, u.lockoutRepo, ctx); err != nil {
+		return "", "", err
+	}
+
 	// Incorrect password
 	if !utils.IsHashStringMatched(req.Password, account.Password) {
-		processWrongCredentialsCase(account.UserId, u.userSecurityRepo, ctx)
+		processWrongCredentialsCase(account, req.IpAddress, u.logger, u.userSecurityRepo, u.lockoutRepo, ctx)
 		return "", "", errors.New(noti.WRONG_CREDENTIALS_WARN_MSG)
 	}
 
-	return processCorrectCredentialsCase(account, u.logger, u.userSecurityRepo, ctx)
+	totp, err := u.mfaRepo.GetUserTotp(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	return processCorrectCredentialsCase(account, security, totp, req, u.logger, u.userSecurityRepo, u.sessionRepo, u.guestCartRepo, ctx)
+}
+
+// LoginWithOidc implements businesslogic.IUserService.
+func (u *userService) LoginWithOidc(req request.OidcLoginRequest, ctx context.Context) (string, string, error) {
+	defer closeCnn(user_cnn)
+
+	provider, ok := utils.GetOidcProvider(req.Provider)
+	if !ok {
+		return "", "", errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Login provider"))
+	}
+
+	claims, err := utils.VerifyIdToken(*provider, req.IdToken, req.Nonce, u.logger)
+	if err != nil {
+		return "", "", err
+	}
+
+	account, err := resolveOidcAccount(provider.Name, *claims, u.logger, u.userRepo, u.userSecurityRepo, u.identityRepo, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	security, err := u.userSecurityRepo.GetUserSecurity(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if security == nil {
+		return "", "", errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Account"))
+	}
+
+	if err := checkAccountLock(security, req.IpAddress, u.lockoutRepo, ctx); err != nil {
+		return "", "", err
+	}
+
+	totp, err := u.mfaRepo.GetUserTotp(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Same token issuance as password login
+	return processCorrectCredentialsCase(*account, security, totp, request.LoginRequest{
+		Email:      account.Email,
+		DeviceName: req.DeviceName,
+		IpAddress:  req.IpAddress,
+		UserAgent:  req.UserAgent,
+		CartToken:  req.CartToken,
+	}, u.logger, u.userSecurityRepo, u.sessionRepo, u.guestCartRepo, ctx)
 }
 
 // Logout implements businesslogic.IUserService.
-func (u *userService) Logout(id string, ctx context.Context) error {
+func (u *userService) Logout(req request.LogoutRequest, ctx context.Context) error {
 	defer closeCnn(user_cnn)
-	return u.userSecurityRepo.Logout(id, ctx)
+
+	// Only owner can log out of the account
+	if req.ActorId != req.UserId {
+		return errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	// Revoke token in use so it can't be used until expired
+	if err := cache.RevokeToken(req.TokenId, req.TokenExp, u.logger); err != nil {
+		return err
+	}
+
+	// Only the current session is ended, other devices stay logged in
+	if req.SessionId == "" {
+		return nil
+	}
+
+	session, err := u.sessionRepo.GetSession(req.SessionId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.UserId != req.UserId || session.IsRevoked {
+		return nil
+	}
+
+	return revokeSession(*session, u.sessionRepo, u.logger, ctx)
 }
 
 // ResetPassword implements businesslogic.IUserService.
-func (u *userService) ResetPassword(newPass string, reNewPass string, token string, ctx context.Context) (string, error) {
+func (u *userService) ResetPassword(req request.ResetPasswordRequest, ctx context.Context) (string, error) {
 	defer closeCnn(user_cnn)
 
+	var token = req.Token
 	accountId, _, exp, err := utils.ExtractDataFromToken(token, u.logger)
 	if err != nil {
-		return os.Getenv(auth.LOGIN_PAGE_URL), err
+		return config.Get().Auth.LoginPageUrl, err
 	}
 
 	var account entity.User
 	if err := verifyAccount(accountId, id_validate, &account, u.userRepo, ctx); err != nil { // Verify account
-		return os.Getenv(auth.LOGIN_PAGE_URL), err
+		return config.Get().Auth.LoginPageUrl, err
 	}
 
 	// User state doesn't have to reset password
 	if account.IsHaveToResetPw == nil {
-		return os.Getenv(auth.LOGIN_PAGE_URL), errors.New(noti.GENERIC_ERROR_WARN_MSG)
+		return config.Get().Auth.LoginPageUrl, errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
 	// Expired
 	if utils.IsActionExpired(exp) {
-		return os.Getenv(auth.LOGIN_PAGE_URL), errors.New("")
+		return config.Get().Auth.LoginPageUrl, errors.New("")
 	}
 
 	// Retrieve account security info
 	usc, err := u.userSecurityRepo.GetUserSecurity(accountId, ctx)
 	if err != nil {
-		return os.Getenv(auth.LOGIN_PAGE_URL), err
+		return config.Get().Auth.LoginPageUrl, err
 	}
 
 	// Not matched token
-	if *usc.ActionToken != token {
-		return os.Getenv(auth.LOGIN_PAGE_URL), errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	if usc == nil || usc.ActionToken == nil || *usc.ActionToken != token {
+		return config.Get().Auth.LoginPageUrl, errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
+	var retryUrl = utils.ToCombinedString([]string{
+		page_url.PROCESS_ENDPOINT,
+		token,
+	}, sepChar)
+
 	// Passwords not matched
-	if newPass != reNewPass {
-		return utils.ToCombinedString([]string{
-			page_url.PROCESS_ENDPOINT,
-			token,
-		}, sepChar), errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	if req.Password != req.ConfirmPassword {
+		return retryUrl, errors.New(noti.PASSWORD_NOT_MATCHED_WARN_MSG)
 	}
 
-	// Hash new password
-	hashPw, err := utils.ToHashString(reNewPass, u.logger)
+	// Check password policy and hash new password
+	hashPw, err := preparePassword(account, req.Password, u.historyRepo, u.logger, ctx)
 	if err != nil {
-		return os.Getenv(auth.LOGIN_PAGE_URL), err
+		return retryUrl, err
 	}
 
 	// Assign new data
@@ -474,7 +674,115 @@ func (u *userService) ResetPassword(newPass string, reNewPass string, token stri
 
 	wg.Wait()
 
-	return os.Getenv(os.Getenv(auth.LOGIN_PAGE_URL)), capturedErr
+	if capturedErr != nil {
+		return config.Get().Auth.LoginPageUrl, capturedErr
+	}
+
+	if err := recordPasswordHistory(account.UserId, hashPw, u.historyRepo, ctx); err != nil {
+		return config.Get().Auth.LoginPageUrl, err
+	}
+
+	// Sessions opened with the old password are ended
+	return config.Get().Auth.LoginPageUrl, revokeUserSessions(account.UserId, u.sessionRepo, u.logger, ctx)
+}
+
+// ChangePassword implements businesslogic.IUserService.
+func (u *userService) ChangePassword(req request.ChangePasswordRequest, ctx context.Context) error {
+	defer closeCnn(user_cnn)
+
+	// Only owner can change password, operators reset it instead
+	if req.ActorId != req.UserId {
+		return errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	var account entity.User
+	if err := verifyAccount(req.UserId, id_validate, &account, u.userRepo, ctx); err != nil {
+		return err
+	}
+
+	if !utils.IsHashStringMatched(req.CurrentPassword, account.Password) {
+		return errors.New(noti.PASSWORD_INCORRECT_WARN_MSG)
+	}
+
+	if req.NewPassword != req.ConfirmPassword {
+		return errors.New(noti.PASSWORD_NOT_MATCHED_WARN_MSG)
+	}
+
+	// Check password policy and hash new password
+	hashPw, err := preparePassword(account, req.NewPassword, u.historyRepo, u.logger, ctx)
+	if err != nil {
+		return err
+	}
+
+	account.Password = hashPw
+	account.IsHaveToResetPw = nil
+	account.UpdatedAt = time.Now()
+
+	if err := u.userRepo.UpdateUser(account, ctx); err != nil {
+		return err
+	}
+
+	if err := recordPasswordHistory(account.UserId, hashPw, u.historyRepo, ctx); err != nil {
+		return err
+	}
+
+	// Other devices have to log in with the new password, the current one stays logged in
+	return revokeOtherSessions(account.UserId, req.SessionId, u.sessionRepo, u.logger, ctx)
+}
+
+// ForceResetPassword implements businesslogic.IUserService.
+func (u *userService) ForceResetPassword(req request.ForceResetPasswordRequest, ctx context.Context) error {
+	defer closeCnn(user_cnn)
+
+	var account entity.User
+	if err := verifyAccount(req.Email, email_validate, &account, u.userRepo, ctx); err != nil {
+		return err
+	}
+
+	// Check password policy and hash new password
+	hashPw, err := preparePassword(account, req.Password, u.historyRepo, u.logger, ctx)
+	if err != nil {
+		return err
+	}
+
+	usc, err := u.userSecurityRepo.GetUserSecurity(account.UserId, ctx)
+	if err != nil {
+		return err
+	}
+
+	account.Password = hashPw
+	account.IsHaveToResetPw = nil
+	if req.IsHaveToReset {
+		var flag bool = true
+		account.IsHaveToResetPw = &flag
+	}
+
+	account.UpdatedAt = time.Now()
+
+	if err := u.userRepo.UpdateUser(account, ctx); err != nil {
+		return err
+	}
+
+	if err := recordPasswordHistory(account.UserId, hashPw, u.historyRepo, ctx); err != nil {
+		return err
+	}
+
+	// End previous sessions
+	if err := revokeUserSessions(account.UserId, u.sessionRepo, u.logger, ctx); err != nil {
+		return err
+	}
+
+	// Clear pending action and failed attempts
+	if usc != nil {
+		usc.ActionToken = nil
+		usc.AccessToken = nil
+		usc.RefreshToken = nil
+		usc.FailAccess = 0
+
+		return u.userSecurityRepo.EditUserSecurity(*usc, ctx)
+	}
+
+	return nil
 }
 
 // UpdateUser implements businesslogic.IUserService.
@@ -482,7 +790,7 @@ func (u *userService) UpdateUser(req request.UpdateUserRequest, ctx context.Cont
 	defer closeCnn(user_cnn)
 
 	// Verify actor and affected account
-	if err := validateActorAndAffectedAccount(req.ActorId, req.UserId, u.userRepo, ctx); err != nil {
+	if err := validateActorAndAffectedAccount(req.ActorId, req.UserId, u.userRepo, u.permissionRepo, ctx); err != nil {
 		return "", err
 	}
 
@@ -512,21 +820,35 @@ func (u *userService) UpdateUser(req request.UpdateUserRequest, ctx context.Cont
 		}
 	}
 
+	var isPasswordChanged bool = false
 	if req.Password != "" {
-		if utils.IsPasswordSecure(req.Password) {
-			// Hash password
-			hashedPassword, err := utils.ToHashString(req.Password, u.logger)
-			// No error
-			if err == nil {
-				// Assign new hashed password
-				account.Password = hashedPassword
-			}
-		} else {
-			return "", errors.New(noti.PASSWORD_NOT_SECURE_WARN_MSG)
+		// Check password policy and hash password
+		hashedPassword, err := preparePassword(*account, req.Password, u.historyRepo, u.logger, ctx)
+		if err != nil {
+			return "", err
 		}
+
+		// Assign new hashed password
+		account.Password = hashedPassword
+		isPasswordChanged = true
 	}
 
-	if req.RoleId != "" {
+	// Only roles managing roles can change role, owners can't grant themselves another role
+	if req.RoleId != "" && req.RoleId != account.RoleId {
+		actor, err := u.userRepo.GetUser(req.ActorId, ctx)
+		if err != nil {
+			return "", err
+		}
+
+		isAllowed, err := hasPermissions(actor.RoleId, []string{permission.ROLE_MANAGE}, u.permissionRepo, ctx)
+		if err != nil {
+			return "", err
+		}
+
+		if !isAllowed || !isEntityExist(u.roleRepo, req.RoleId, id_type, ctx) {
+			return "", errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+		}
+
 		account.RoleId = req.RoleId
 	}
 
@@ -550,6 +872,12 @@ func (u *userService) UpdateUser(req request.UpdateUserRequest, ctx context.Cont
 		return "", err
 	}
 
+	if isPasswordChanged {
+		if err := recordPasswordHistory(account.UserId, account.Password, u.historyRepo, ctx); err != nil {
+			return "", err
+		}
+	}
+
 	var res string
 
 	// If must be sended verification mail
@@ -578,7 +906,7 @@ func (u *userService) UpdateUser(req request.UpdateUserRequest, ctx context.Cont
 				Email:   newEmail,
 				Subject: noti.UPDATE_EMAIL_MAIL_SUBJECT,
 				Url: utils.ToCombinedString([]string{
-					os.Getenv(auth.PROCESS_ACTION_URL),
+					config.Get().Auth.ProcessActionUrl,
 					token,
 					req.UserId,
 					updateProfileType,
@@ -620,7 +948,7 @@ func (u *userService) VerifyAction(rawToken string, ctx context.Context) (string
 		return "", errRes
 	}
 
-	if *usc.ActionToken != token {
+	if usc == nil || usc.ActionToken == nil || *usc.ActionToken != token {
 		return "", errRes
 	}
 
@@ -640,10 +968,39 @@ func (u *userService) VerifyAction(rawToken string, ctx context.Context) (string
 	}
 
 	// Invalid action type
-	if actionType != activateType && actionType != resetPassType && actionType != updateProfileType && actionType != verifyType {
+	if actionType != activateType && actionType != resetPassType && actionType != updateProfileType && actionType != verifyType && actionType != deleteAccountType {
 		return "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
+	// Confirmed deletion runs after the grace period, the token can't be used again
+	if actionType == deleteAccountType {
+		scheduled, err := u.deletionRepo.GetScheduledAccountDeletion(id, ctx)
+		if err != nil {
+			return "", err
+		}
+
+		if scheduled == nil {
+			var curTime = time.Now()
+			if err := u.deletionRepo.CreateAccountDeletion(entity.AccountDeletion{
+				DeletionId:  utils.GenerateId(),
+				UserId:      id,
+				Status:      domain_status.ACCOUNT_DELETION_SCHEDULED,
+				ScheduledAt: curTime.Add(config.Get().Account.DeletionGracePeriod),
+				CreatedAt:   curTime,
+				UpdatedAt:   curTime,
+			}, ctx); err != nil {
+				return "", err
+			}
+		}
+
+		usc.ActionToken = nil
+		if err := u.userSecurityRepo.EditUserSecurity(*usc, ctx); err != nil {
+			return "", err
+		}
+
+		return config.Get().Auth.LoginPageUrl, nil
+	}
+
 	var res string
 
 	if actionType == activateType || actionType == updateProfileType {
@@ -662,7 +1019,7 @@ func (u *userService) VerifyAction(rawToken string, ctx context.Context) (string
 			return res, err
 		}
 
-		res = os.Getenv(auth.LOGIN_PAGE_URL)
+		res = config.Get().Auth.LoginPageUrl
 	} else {
 		// Setup prepare to reset password
 		token, err := utils.GenerateActionToken("", usc.UserId, "", u.logger)
@@ -681,36 +1038,337 @@ func (u *userService) VerifyAction(rawToken string, ctx context.Context) (string
 	return res, nil
 }
 
-// RefreshToken implements businesslogic.IUserService.
-func (u *userService) RefreshToken(req request.RefreshTokenRequest, ctx context.Context) (string, error) {
+// ExportUserData implements businesslogic.IUserService.
+func (u *userService) ExportUserData(userId string, ctx context.Context) (*response.UserDataExportResponse, error) {
 	defer closeCnn(user_cnn)
 
-	// Extract data from token
-	id, _, exp, err := utils.ExtractDataFromToken(req.RefreshToken, u.logger)
+	account, err := u.userRepo.GetUser(userId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		return nil, errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetUserTable()))
+	}
+
+	return buildUserDataExport(*account, u.orderRepo, u.paymentRepo, u.shippingRepo, u.cartRepo, u.productRepo, u.inventoryRepo, u.wishlistRepo, u.userTierRepo, u.reminderRepo, u.subscriptionRepo, u.deletionRepo, ctx)
+}
+
+// RequestAccountDeletion implements businesslogic.IUserService.
+// Nothing is deleted until the owner confirms with the link sent by mail
+func (u *userService) RequestAccountDeletion(req request.AccountDeletionRequest, ctx context.Context) (string, error) {
+	defer closeCnn(user_cnn)
+
+	// Only owner can delete account
+	if req.ActorId != req.UserId {
+		return "", errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	var account entity.User
+	if err := verifyAccount(req.UserId, id_validate, &account, u.userRepo, ctx); err != nil {
+		return "", err
+	}
+
+	scheduled, err := u.deletionRepo.GetScheduledAccountDeletion(req.UserId, ctx)
 	if err != nil {
 		return "", err
 	}
 
-	// Get user security
-	sc, err := u.userSecurityRepo.GetUserSecurity(id, ctx)
+	if scheduled != nil {
+		return "", errors.New(noti.ACCOUNT_DELETION_SCHEDULED_WARN_MSG)
+	}
+
+	usc, err := u.userSecurityRepo.GetUserSecurity(req.UserId, ctx)
 	if err != nil {
 		return "", err
 	}
 
-	if *sc.RefreshToken != req.RefreshToken {
+	if usc == nil {
 		return "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
 	}
 
-	if utils.IsActionExpired(exp) {
-		return "", errors.New(noti.TOKEN_EXPIRED_MESSAGE)
+	token, err := utils.GenerateActionToken(account.Email, account.UserId, "", u.logger)
+	if err != nil {
+		return "", err
 	}
 
-	token, _, err := utils.GenerateTokens("", id, "", u.logger)
-	if err != nil {
+	usc.ActionToken = &token
+	if err := u.userSecurityRepo.EditUserSecurity(*usc, ctx); err != nil {
 		return "", err
 	}
 
-	sc.AccessToken = &token
+	if err := utils.SendMail(request.SendMailRequest{
+		Body: request.MailBody{
+			Email:    account.Email,
+			Subject:  noti.ACCOUNT_DELETION_MAIL_SUBJECT,
+			Username: account.FullName,
+			Url: utils.ToCombinedString([]string{
+				config.Get().Auth.ProcessActionUrl,
+				token,
+				account.UserId,
+				deleteAccountType,
+			}, mailSepChar),
+			GracePeriod: describeGracePeriod(config.Get().Account.DeletionGracePeriod),
+		},
+
+		TemplatePath: mail_const.ACCOUNT_DELETION_MAIL_TEMPLATE,
+
+		Logger: u.logger,
+	}); err != nil {
+		return "", errors.New(noti.GENERATE_MAIL_WARN_MSG)
+	}
+
+	return noti.DELETE_ACCOUNT_MESSAGE, nil
+}
+
+// CancelAccountDeletion implements businesslogic.IUserService.
+func (u *userService) CancelAccountDeletion(req request.AccountDeletionRequest, ctx context.Context) error {
+	defer closeCnn(user_cnn)
+
+	if req.ActorId != req.UserId {
+		return errors.New(noti.GENERIC_RIGHT_ACCESS_WARN_MSG)
+	}
+
+	scheduled, err := u.deletionRepo.GetScheduledAccountDeletion(req.UserId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if scheduled == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetAccountDeletionTable()))
+	}
+
+	scheduled.Status = domain_status.ACCOUNT_DELETION_CANCELLED
+	scheduled.UpdatedAt = time.Now()
+
+	return u.deletionRepo.UpdateAccountDeletion(*scheduled, ctx)
+}
+
+// ProcessAccountDeletions implements businesslogic.IUserService.
+// Anonymizes accounts whose grace period has passed, a failed account is retried on the next run
+func (u *userService) ProcessAccountDeletions(ctx context.Context) (int, error) {
+	defer closeCnn(user_cnn)
+
+	deletions, err := u.deletionRepo.GetDueAccountDeletions(time.Now(), ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var res int
+	for _, deletion := range *deletions {
+		if err := anonymizeAccount(deletion, u.logger, u.userRepo, u.sessionRepo, u.deletionRepo, ctx); err != nil {
+			u.logger.Println("Account deletion " + deletion.DeletionId + " failed - " + err.Error())
+			continue
+		}
+
+		res++
+	}
+
+	return res, nil
+}
+
+// RefreshToken implements businesslogic.IUserService.
+// Refresh tokens are single use, presenting an already rotated one means it was stolen so the whole session is revoked
+func (u *userService) RefreshToken(req request.RefreshTokenRequest, ctx context.Context) (string, string, error) {
+	defer closeCnn(user_cnn)
+
+	// Extract data from token, expired token is rejected here
+	claims, err := utils.ParseToken(req.RefreshToken, u.logger)
+	if err != nil {
+		return "", "", err
+	}
+
+	if claims.TokenUse != utils.REFRESH_TOKEN_USE || claims.SessionId == "" || cache.IsTokenRevoked(claims.ID, u.logger) {
+		return "", "", errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	session, err := u.sessionRepo.GetSession(claims.SessionId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if session == nil || session.UserId != claims.UserId || session.IsRevoked {
+		return "", "", errors.New(noti.SESSION_REVOKED_WARN_MSG)
+	}
+
+	var tokenHash = utils.ToSHA256String(req.RefreshToken)
+
+	// Reused token
+	if tokenHash != session.RefreshTokenHash {
+		u.logger.Println("Refresh token reuse detected, revoking session " + session.SessionId + " of user " + session.UserId)
+		if err := revokeSession(*session, u.sessionRepo, u.logger, ctx); err != nil {
+			return "", "", err
+		}
+
+		return "", "", errors.New(noti.SESSION_REVOKED_WARN_MSG)
+	}
+
+	// Claims are issued from the current account: role or email changes apply, a disabled account or one
+	// scheduled for deletion loses the session and the second factor only counts while it is still enabled
+	var account entity.User
+	if err := verifyAccount(claims.UserId, id_validate, &account, u.userRepo, ctx); err != nil {
+		return "", "", err
+	}
+
+	scheduled, err := u.deletionRepo.GetScheduledAccountDeletion(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !account.IsActive || scheduled != nil {
+		if err := revokeSession(*session, u.sessionRepo, u.logger, ctx); err != nil {
+			return "", "", err
+		}
+
+		return "", "", errors.New(noti.SESSION_REVOKED_WARN_MSG)
+	}
+
+	totp, err := u.mfaRepo.GetUserTotp(account.UserId, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	var isMfa bool = claims.Mfa && totp != nil && totp.IsEnabled
+
+	tokens, err := utils.GenerateTokens(account.Email, account.UserId, account.RoleId, session.SessionId, isMfa, u.logger)
+	if err != nil {
+		return "", "", err
+	}
+
+	var prevSession = *session
+
+	session.RefreshTokenHash = utils.ToSHA256String(tokens.RefreshToken)
+	session.AccessTokenId = tokens.AccessTokenId
+	session.AccessExpiredAt = tokens.AccessExpiredAt
+	session.IpAddress = req.IpAddress
+	session.UserAgent = req.UserAgent
+	session.LastUsedAt = time.Now()
+	session.ExpiredAt = tokens.RefreshExpiredAt
+
+	isRotated, err := u.sessionRepo.RotateSession(*session, tokenHash, ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Same token was used by a concurrent request
+	if !isRotated {
+		u.logger.Println("Refresh token reuse detected, revoking session " + session.SessionId + " of user " + session.UserId)
+		if err := revokeSession(*session, u.sessionRepo, u.logger, ctx); err != nil {
+			return "", "", err
+		}
+
+		return "", "", errors.New(noti.SESSION_REVOKED_WARN_MSG)
+	}
+
+	// Previous access token of the session is replaced
+	cache.RevokeToken(prevSession.AccessTokenId, prevSession.AccessExpiredAt, u.logger)
+
+	return tokens.AccessToken, tokens.RefreshToken, nil
+}
+
+// GetUserSessions implements businesslogic.IUserService.
+func (u *userService) GetUserSessions(req request.GetSessionsRequest, ctx context.Context) (*[]response.SessionResponse, error) {
+	defer closeCnn(user_cnn)
+
+	// Owner or admin only
+	if err := validateActorAndAffectedAccount(req.ActorId, req.UserId, u.userRepo, u.permissionRepo, ctx); err != nil {
+		return nil, err
+	}
+
+	sessions, err := u.sessionRepo.GetActiveSessionsByUser(req.UserId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = []response.SessionResponse{}
+	for _, session := range *sessions {
+		res = append(res, response.SessionResponse{
+			Session:   session,
+			IsCurrent: session.SessionId == req.CurrentSessionId,
+		})
+	}
+
+	return &res, nil
+}
+
+// RevokeUserSessions implements businesslogic.IUserService.
+func (u *userService) RevokeUserSessions(req request.RevokeSessionRequest, ctx context.Context) error {
+	defer closeCnn(user_cnn)
+
+	// Owner or admin only
+	if err := validateActorAndAffectedAccount(req.ActorId, req.UserId, u.userRepo, u.permissionRepo, ctx); err != nil {
+		return err
+	}
+
+	if req.SessionId == "" {
+		return revokeUserSessions(req.UserId, u.sessionRepo, u.logger, ctx)
+	}
+
+	session, err := u.sessionRepo.GetSession(req.SessionId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.UserId != req.UserId {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Session"))
+	}
+
+	if session.IsRevoked {
+		return nil
+	}
+
+	return revokeSession(*session, u.sessionRepo, u.logger, ctx)
+}
+
+// UnlockAccount implements businesslogic.IUserService.
+func (u *userService) UnlockAccount(req request.UnlockAccountRequest, ctx context.Context) error {
+	defer closeCnn(user_cnn)
+
+	var account entity.User
+	if err := verifyAccount(req.UserId, id_validate, &account, u.userRepo, ctx); err != nil {
+		return err
+	}
+
+	usc, err := u.userSecurityRepo.GetUserSecurity(account.UserId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if usc == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, "Account"))
+	}
+
+	usc.LockedUntil = nil
+	usc.LockCount = 0
+	usc.FailAccess = 0
+
+	if err := u.userSecurityRepo.EditUserSecurity(*usc, ctx); err != nil {
+		return err
+	}
+
+	var event = entity.LockoutEvent{
+		EventId:   utils.GenerateId(),
+		UserId:    account.UserId,
+		Action:    action_type.LOCKOUT_UNLOCK_ACTION,
+		ActorId:   &req.ActorId,
+		CreatedAt: time.Now(),
+	}
+
+	if req.Note != "" {
+		event.Note = &req.Note
+	}
+
+	return u.lockoutRepo.CreateLockoutEvent(event, ctx)
+}
+
+// GetLockoutEvents implements businesslogic.IUserService.
+func (u *userService) GetLockoutEvents(userId string, ctx context.Context) (*[]entity.LockoutEvent, error) {
+	defer closeCnn(user_cnn)
+
+	var account entity.User
+	if err := verifyAccount(userId, id_validate, &account, u.userRepo, ctx); err != nil {
+		return nil, err
+	}
 
-	return token, u.userSecurityRepo.EditUserSecurity(*sc, ctx)
+	return u.lockoutRepo.GetLockoutEventsByUser(account.UserId, ctx)
 }
@@ -0,0 +1,232 @@
+package businesslogic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sonit_server/constant/noti"
+	repo "sonit_server/data_access"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	business_logic "sonit_server/interface/business_logic"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	"sonit_server/model/entity"
+	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"strings"
+	"time"
+)
+
+type vipTierService struct {
+	tierRepo     data_access.IVipTierRepo
+	userTierRepo data_access.IUserTierRepo
+	userRepo     data_access.IUserRepo
+	logger       *log.Logger
+}
+
+func GenerateVipTierService() (business_logic.IVipTierService, error) {
+	var logger = utils.GetLogConfig()
+
+	cnn, err := db.ConnectDB(logger, db_server.InitializePostgreSQL())
+
+	if err != nil {
+		return nil, err
+	}
+
+	vip_tier_cnn = cnn
+
+	return InitializeVipTierService(cnn, logger), nil
+}
+
+func InitializeVipTierService(db *sql.DB, logger *log.Logger) business_logic.IVipTierService {
+	return &vipTierService{
+		tierRepo:     repo.InitializeCachedVipTierRepo(db, logger),
+		userTierRepo: repo.InitializeUserTierRepo(db, logger),
+		userRepo:     repo.InitializeUserRepo(db, logger),
+		logger:       logger,
+	}
+}
+
+var vip_tier_cnn *sql.DB
+
+// GetVipTiers implements businesslogic.IVipTierService.
+func (v *vipTierService) GetVipTiers(ctx context.Context) (*[]entity.VipTier, error) {
+	defer closeCnn(vip_tier_cnn)
+	return v.tierRepo.GetVipTiers(ctx)
+}
+
+// CreateVipTier implements businesslogic.IVipTierService.
+func (v *vipTierService) CreateVipTier(req request.CreateVipTierRequest, ctx context.Context) error {
+	defer closeCnn(vip_tier_cnn)
+
+	req.TierName = strings.TrimSpace(req.TierName)
+	if req.TierName == "" || !isVipTierValueValid(req.MinSpend, req.DiscountPercent) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	// Tier name existed
+	tier, err := v.tierRepo.GetVipTierByName(req.TierName, ctx)
+	if err != nil {
+		return err
+	}
+
+	if tier != nil {
+		return errors.New(noti.ITEM_EXISTED_WARN_MSG)
+	}
+	//---------------------------------------
+	var curTime time.Time = time.Now()
+	return v.tierRepo.CreateVipTier(entity.VipTier{
+		TierId:          utils.GenerateId(),
+		TierName:        req.TierName,
+		MinSpend:        req.MinSpend,
+		DiscountPercent: req.DiscountPercent,
+		CreatedAt:       curTime,
+		UpdatedAt:       curTime,
+	}, ctx)
+}
+
+// UpdateVipTier implements businesslogic.IVipTierService.
+// Thresholds take effect on users at next evaluation, discounts at next checkout
+func (v *vipTierService) UpdateVipTier(req request.UpdateVipTierRequest, ctx context.Context) error {
+	defer closeCnn(vip_tier_cnn)
+
+	tier, err := v.tierRepo.GetVipTierById(req.TierId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if tier == nil {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	if name := strings.TrimSpace(req.TierName); name != "" && !strings.EqualFold(name, tier.TierName) {
+		existed, err := v.tierRepo.GetVipTierByName(name, ctx)
+		if err != nil {
+			return err
+		}
+
+		if existed != nil {
+			return errors.New(noti.ITEM_EXISTED_WARN_MSG)
+		}
+
+		tier.TierName = name
+	}
+
+	if req.MinSpend != nil {
+		tier.MinSpend = *req.MinSpend
+	}
+
+	if req.DiscountPercent != nil {
+		tier.DiscountPercent = *req.DiscountPercent
+	}
+
+	if !isVipTierValueValid(tier.MinSpend, tier.DiscountPercent) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	tier.UpdatedAt = time.Now()
+
+	return v.tierRepo.UpdateVipTier(*tier, ctx)
+}
+
+// RemoveVipTier implements businesslogic.IVipTierService.
+// Users of the removed tier lose it until next evaluation
+func (v *vipTierService) RemoveVipTier(id string, ctx context.Context) error {
+	defer closeCnn(vip_tier_cnn)
+
+	if id == "" {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	return v.tierRepo.RemoveVipTier(id, ctx)
+}
+
+// GetVipTierProgress implements businesslogic.IVipTierService.
+func (v *vipTierService) GetVipTierProgress(userId string, ctx context.Context) (*response.VipTierProgressResponse, error) {
+	defer closeCnn(vip_tier_cnn)
+
+	if !isEntityExist(v.userRepo, userId, id_type, ctx) {
+		return nil, errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	tiers, err := v.tierRepo.GetVipTiers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var windowStart = time.Now().Add(-config.Get().Vip.SpendWindow)
+
+	spend, err := v.userTierRepo.GetUserSpend(userId, windowStart, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userTier, err := v.userTierRepo.GetUserTier(userId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = response.VipTierProgressResponse{
+		UserId:       userId,
+		EligibleTier: resolveVipTier(*tiers, spend),
+		NextTier:     getNextVipTier(*tiers, spend),
+		TotalSpend:   spend,
+		WindowStart:  windowStart,
+	}
+
+	if res.NextTier != nil {
+		res.AmountToNextTier = res.NextTier.MinSpend - spend
+	}
+
+	if userTier != nil {
+		res.EvaluatedAt = &userTier.EvaluatedAt
+		res.CurrentTier = findVipTier(*tiers, userTier.TierId)
+	}
+
+	return &res, nil
+}
+
+// EvaluateVipTiers implements businesslogic.IVipTierService.
+// Promotes and demotes every active user to the tier matching its spend in the window, returns number of users whose tier changed
+func (v *vipTierService) EvaluateVipTiers(ctx context.Context) (int, error) {
+	defer closeCnn(vip_tier_cnn)
+
+	tiers, err := v.tierRepo.GetVipTiers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var curTime = time.Now()
+
+	userTiers, err := v.userTierRepo.GetUserTiersWithSpend(curTime.Add(-config.Get().Vip.SpendWindow), ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var res int
+	for _, userTier := range *userTiers {
+		var tierId *string
+		if tier := resolveVipTier(*tiers, userTier.TotalSpend); tier != nil {
+			tierId = &tier.TierId
+		}
+
+		var isChanged = !isSameVipTier(userTier.TierId, tierId)
+
+		userTier.TierId = tierId
+		userTier.EvaluatedAt = curTime
+
+		if err := v.userTierRepo.SaveUserTier(userTier, ctx); err != nil {
+			v.logger.Println("VIP tier evaluation of user " + userTier.UserId + " failed - " + err.Error())
+			continue
+		}
+
+		if isChanged {
+			res++
+		}
+	}
+
+	return res, nil
+}
@@ -38,7 +38,7 @@ func GenerateVoucherService() (business_logic.IVoucherService, error) {
 
 func InitializeVoucherService(db *sql.DB, logger *log.Logger) business_logic.IVoucherService {
 	return &voucherService{
-		voucherRepo: repo.InitializeVoucherRepo(db, logger),
+		voucherRepo: repo.InitializeCachedVoucherRepo(db, logger),
 		logger:      logger,
 	}
 }
@@ -0,0 +1,132 @@
+package businesslogic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sonit_server/constant/noti"
+	repo "sonit_server/data_access"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	business_logic "sonit_server/interface/business_logic"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/entity"
+	"sonit_server/utils"
+	"strings"
+	"time"
+)
+
+type warehouseService struct {
+	warehouseRepo data_access.IWarehouseRepo
+	logger        *log.Logger
+}
+
+func GenerateWarehouseService() (business_logic.IWarehouseService, error) {
+	var logger = utils.GetLogConfig()
+
+	cnn, err := db.ConnectDB(logger, db_server.InitializePostgreSQL())
+
+	if err != nil {
+		return nil, err
+	}
+
+	warehouse_cnn = cnn
+
+	return InitializeWarehouseService(cnn, logger), nil
+}
+
+func InitializeWarehouseService(db *sql.DB, logger *log.Logger) business_logic.IWarehouseService {
+	return &warehouseService{
+		warehouseRepo: repo.InitializeWarehouseRepo(db, logger),
+		logger:        logger,
+	}
+}
+
+var warehouse_cnn *sql.DB
+
+// GetWarehouses implements businesslogic.IWarehouseService.
+func (w *warehouseService) GetWarehouses(ctx context.Context) (*[]entity.Warehouse, error) {
+	defer closeCnn(warehouse_cnn)
+	return w.warehouseRepo.GetWarehouses(ctx)
+}
+
+// CreateWarehouse implements businesslogic.IWarehouseService.
+func (w *warehouseService) CreateWarehouse(req request.CreateWarehouseRequest, ctx context.Context) error {
+	defer closeCnn(warehouse_cnn)
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || !isWarehouseLocationValid(req.Latitude, req.Longitude) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	var curTime time.Time = time.Now()
+	return w.warehouseRepo.CreateWarehouse(entity.Warehouse{
+		WarehouseId: utils.GenerateId(),
+		Name:        req.Name,
+		Address:     req.Address,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		Priority:    req.Priority,
+		IsActive:    true,
+		CreatedAt:   curTime,
+		UpdatedAt:   curTime,
+	}, ctx)
+}
+
+// UpdateWarehouse implements businesslogic.IWarehouseService.
+// Default warehouse can't be deactivated, stock of a deactivated warehouse stays until transferred
+func (w *warehouseService) UpdateWarehouse(req request.UpdateWarehouseRequest, ctx context.Context) error {
+	defer closeCnn(warehouse_cnn)
+
+	warehouse, err := w.warehouseRepo.GetWarehouseById(req.WarehouseId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if warehouse == nil {
+		return errors.New(fmt.Sprintf(noti.UNDEFINED_OBJECT_WARN_MSG, entity.GetWarehouseTable()))
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		warehouse.Name = name
+	}
+
+	if req.Address != "" {
+		warehouse.Address = req.Address
+	}
+
+	// Coordinates are set together
+	if req.Latitude != nil || req.Longitude != nil {
+		if !isWarehouseLocationValid(req.Latitude, req.Longitude) {
+			return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+		}
+
+		warehouse.Latitude = req.Latitude
+		warehouse.Longitude = req.Longitude
+	}
+
+	if req.Priority != nil {
+		warehouse.Priority = *req.Priority
+	}
+
+	if req.IsActive != nil {
+		if !*req.IsActive && warehouse.WarehouseId == getDefaultWarehouseId() {
+			return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+		}
+
+		warehouse.IsActive = *req.IsActive
+	}
+
+	warehouse.UpdatedAt = time.Now()
+
+	return w.warehouseRepo.UpdateWarehouse(*warehouse, ctx)
+}
+
+// GetWarehouseInventories implements businesslogic.IWarehouseService.
+func (w *warehouseService) GetWarehouseInventories(req request.GetWarehouseInventoriesRequest, ctx context.Context) (*[]entity.WarehouseInventory, error) {
+	defer closeCnn(warehouse_cnn)
+	return w.warehouseRepo.GetWarehouseInventories(req.ProductId, req.WarehouseId, ctx)
+}
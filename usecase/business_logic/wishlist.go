@@ -0,0 +1,263 @@
+package businesslogic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math"
+	"sonit_server/constant/noti"
+	wishlist_type "sonit_server/constant/wishlist_type"
+	repo "sonit_server/data_access"
+	"sonit_server/data_access/db"
+	db_server "sonit_server/data_access/db_server"
+	business_logic "sonit_server/interface/business_logic"
+	data_access "sonit_server/interface/data_access"
+	"sonit_server/model/dto/request"
+	"sonit_server/model/dto/response"
+	"sonit_server/model/entity"
+	"sonit_server/utils"
+	"time"
+)
+
+type wishlistService struct {
+	userRepo      data_access.IUserRepo
+	productRepo   data_access.IProductRepo
+	inventoryRepo data_access.IProductInventoryRepo
+	cartRepo      data_access.ICartRepo
+	wishlistRepo  data_access.IWishlistRepo
+	logger        *log.Logger
+}
+
+func GenerateWishlistService() (business_logic.IWishlistService, error) {
+	var logger = utils.GetLogConfig()
+
+	cnn, err := db.ConnectDB(logger, db_server.InitializePostgreSQL())
+
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist_cnn = cnn
+
+	return InitializeWishlistService(cnn, logger), nil
+}
+
+func InitializeWishlistService(db *sql.DB, logger *log.Logger) business_logic.IWishlistService {
+	return &wishlistService{
+		userRepo:      repo.InitializeUserRepo(db, logger),
+		productRepo:   repo.InitializeCachedProductRepo(db, logger),
+		inventoryRepo: repo.InitializeProductInventoryRepo(db, logger),
+		cartRepo:      repo.InitializeCartRepo(db, logger),
+		wishlistRepo:  repo.InitializeWishlistRepo(db, logger),
+		logger:        logger,
+	}
+}
+
+var wishlist_cnn *sql.DB
+
+// Page size of wishlists
+const items_in_wishlist_limit int = 10
+
+// ViewWishlist implements businesslogic.IWishlistService.
+func (w *wishlistService) ViewWishlist(userId, listType string, pageNumber int, ctx context.Context) (response.PaginationDataResponse, error) {
+	if pageNumber <= 0 {
+		pageNumber = 1
+	}
+
+	defer closeCnn(wishlist_cnn)
+
+	if listType != "" {
+		var err error
+		if listType, err = getWishlistType(listType); err != nil {
+			return response.PaginationDataResponse{}, err
+		}
+	}
+
+	items, err := getWishlistItemsDetail(userId, listType, w.wishlistRepo, w.productRepo, w.inventoryRepo, ctx)
+	if err != nil {
+		return response.PaginationDataResponse{}, err
+	}
+
+	var start = min((pageNumber-1)*items_in_wishlist_limit, len(items))
+	var end = min(start+items_in_wishlist_limit, len(items))
+
+	return response.PaginationDataResponse{
+		Data:       items[start:end],
+		PageNumber: pageNumber,
+		TotalPages: int(math.Ceil(float64(len(items)) / float64(items_in_wishlist_limit))),
+	}, nil
+}
+
+// AddItemToWishlist implements businesslogic.IWishlistService.
+func (w *wishlistService) AddItemToWishlist(req request.AddWishlistItemRequest, ctx context.Context) error {
+	defer closeCnn(wishlist_cnn)
+
+	listType, err := getWishlistType(req.ListType)
+	if err != nil {
+		return err
+	}
+
+	if !isEntityExist(w.userRepo, req.UserId, id_type, ctx) {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	product, err := w.productRepo.GetProductById(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if product == nil {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	inventory, err := w.inventoryRepo.GetProductInventory(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	var curTime time.Time = time.Now()
+	return w.wishlistRepo.SaveWishlistItem(entity.WishlistItem{
+		WishlistItemId:    utils.GenerateId(),
+		UserId:            req.UserId,
+		ProductId:         req.ProductId,
+		ListType:          listType,
+		NotifyBackInStock: req.NotifyBackInStock,
+		NotifyPriceDrop:   req.NotifyPriceDrop,
+		NotifiedPrice:     product.Price,
+		IsInStock:         inventory != nil && inventory.CurrentQuantity > 0,
+		CreatedAt:         curTime,
+		UpdatedAt:         curTime,
+	}, ctx)
+}
+
+// RemoveItemFromWishlist implements businesslogic.IWishlistService.
+func (w *wishlistService) RemoveItemFromWishlist(req request.WishlistItemRequest, ctx context.Context) error {
+	defer closeCnn(wishlist_cnn)
+	return w.wishlistRepo.RemoveWishlistItem(req.UserId, req.ProductId, ctx)
+}
+
+// MoveItemToCart implements businesslogic.IWishlistService.
+// The item is added through the cart service so stock and product are checked the same way, then leaves the wishlist
+func (w *wishlistService) MoveItemToCart(req request.MoveWishlistItemToCartRequest, ctx context.Context) error {
+	defer closeCnn(wishlist_cnn)
+
+	item, err := w.wishlistRepo.GetWishlistItem(req.UserId, req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	if item == nil {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	if req.Quantity < 1 {
+		req.Quantity = 1
+	}
+
+	// Cart service owns and closes its connection
+	cartService, err := GenerateCartService()
+	if err != nil {
+		return err
+	}
+
+	if err := cartService.AddItemToCart(request.AddItemToCartRequest{
+		Request: request.RemoveItemFromCartRequest{
+			UserId:    req.UserId,
+			ProductId: req.ProductId,
+		},
+		Quantity: req.Quantity,
+	}, ctx); err != nil {
+		return err
+	}
+
+	return w.wishlistRepo.RemoveWishlistItem(req.UserId, req.ProductId, ctx)
+}
+
+// SaveCartItemForLater implements businesslogic.IWishlistService.
+// Moves a cart line to the saved for later list, notification settings of a product already saved are kept
+func (w *wishlistService) SaveCartItemForLater(req request.WishlistItemRequest, ctx context.Context) error {
+	defer closeCnn(wishlist_cnn)
+
+	itemsInCart, err := getCartItemsByProduct(req.UserId, w.cartRepo, ctx)
+	if err != nil {
+		return err
+	}
+
+	line, isExisted := itemsInCart[req.ProductId]
+	if !isExisted {
+		return errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	}
+
+	inventory, err := w.inventoryRepo.GetProductInventory(req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	saved, err := w.wishlistRepo.GetWishlistItem(req.UserId, req.ProductId, ctx)
+	if err != nil {
+		return err
+	}
+
+	var curTime time.Time = time.Now()
+	var item = entity.WishlistItem{
+		WishlistItemId: utils.GenerateId(),
+		UserId:         req.UserId,
+		ProductId:      req.ProductId,
+		ListType:       wishlist_type.SAVED_FOR_LATER,
+		NotifiedPrice:  line.UnitPrice,
+		IsInStock:      inventory != nil && inventory.CurrentQuantity > 0,
+		CreatedAt:      curTime,
+		UpdatedAt:      curTime,
+	}
+
+	if saved != nil {
+		item.NotifyBackInStock = saved.NotifyBackInStock
+		item.NotifyPriceDrop = saved.NotifyPriceDrop
+	}
+
+	if err := w.wishlistRepo.SaveWishlistItem(item, ctx); err != nil {
+		return err
+	}
+
+	return w.cartRepo.RemoveCartItem(req.UserId, req.ProductId, ctx)
+}
+
+// NotifyWishlistChanges implements businesslogic.IWishlistService.
+// Items waiting for a restock are recorded first, then each user gets one mail of restocked and cheaper items
+func (w *wishlistService) NotifyWishlistChanges(ctx context.Context) (int, error) {
+	defer closeCnn(wishlist_cnn)
+
+	if _, err := w.wishlistRepo.MarkOutOfStockWishlistItems(time.Now(), ctx); err != nil {
+		return 0, err
+	}
+
+	items, err := w.wishlistRepo.GetDueWishlistItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Group items by user, users keep the order of the query
+	var itemsByUser = map[string][]entity.WishlistItem{}
+	var userIds []string
+	for _, item := range *items {
+		if _, isExisted := itemsByUser[item.UserId]; !isExisted {
+			userIds = append(userIds, item.UserId)
+		}
+
+		itemsByUser[item.UserId] = append(itemsByUser[item.UserId], item)
+	}
+
+	var res int
+	for _, userId := range userIds {
+		notified, err := sendWishlistNotice(userId, itemsByUser[userId], w.logger, w.userRepo, w.productRepo, w.inventoryRepo, w.wishlistRepo, ctx)
+		if err != nil {
+			w.logger.Println("Wishlist notice of user " + userId + " failed - " + err.Error())
+			continue
+		}
+
+		res += notified
+	}
+
+	return res, nil
+}
@@ -65,6 +65,11 @@ func ProcessLoginResponse(data response.APIResponse) {
 		data.Context.IndentedJSON(http.StatusContinue, singleResponse)
 	case action_type.REDIRECT:
 		processRedirectResponse(stringRes2, data.Context)
+	case action_type.MFA_REQUIRED_TYPE:
+		data.Context.IndentedJSON(http.StatusAccepted, response.MfaChallengeResponse{
+			MfaRequired:    true,
+			ChallengeToken: stringRes2,
+		})
 	default:
 		data.Context.IndentedJSON(http.StatusOK, response.LoginSuccessResponse{
 			AccessToken:  stringRes1,
@@ -85,7 +90,8 @@ func ProcessRefreshTokenResponse(data response.APIResponse) {
 
 	}
 	data.Context.IndentedJSON(http.StatusOK, response.RefreshTokenSuccessResponse{
-		AccessToken: fmt.Sprint(data.Data1),
+		AccessToken:  fmt.Sprint(data.Data1),
+		RefreshToken: fmt.Sprint(data.Data2),
 	})
 }
 
@@ -97,6 +103,14 @@ func processFailResponse(err error, ctx *gin.Context) {
 		errCode = http.StatusInternalServerError
 	case noti.GENERIC_RIGHT_ACCESS_WARN_MSG:
 		errCode = http.StatusForbidden
+	case noti.TOO_MANY_REQUESTS_WARN_MSG:
+		errCode = http.StatusTooManyRequests
+	case noti.ACCOUNT_LOCKED_WARN_MSG:
+		errCode = http.StatusLocked
+	case noti.MFA_REQUIRED_WARN_MSG:
+		errCode = http.StatusForbidden
+	case noti.CART_CHANGED_WARN_MSG:
+		errCode = http.StatusConflict
 	default:
 		errCode = http.StatusBadRequest
 	}
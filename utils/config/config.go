@@ -0,0 +1,222 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Application configuration, each field is resolved by priority: env variable > YAML file > default value
+//
+// Tags:
+//   - env: env variable name (see constant/env)
+//   - yaml: key in the YAML config file
+//   - default: fallback value
+//   - required: startup fails if the value is empty
+//   - min, max: startup fails if a number or duration is out of bounds
+//   - oneof: comma separated values allowed
+//   - secret: value is masked when printed redacted
+//
+// Values are parsed into the field types on load, startup fails if a value can't be parsed
+type Config struct {
+	Profile   string          `env:"APP_PROFILE" yaml:"profile" default:"dev"`
+	Server    ServerConfig    `yaml:"server"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Role      RoleConfig      `yaml:"role"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Mail      MailConfig      `yaml:"mail"`
+	Payment   PaymentConfig   `yaml:"payment"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Lockout   LockoutConfig   `yaml:"lockout"`
+	Password  PasswordConfig  `yaml:"password"`
+	Oidc      OidcConfig      `yaml:"oidc"`
+	Account   AccountConfig   `yaml:"account"`
+	Vip       VipConfig       `yaml:"vip"`
+	Cart      CartConfig      `yaml:"cart"`
+	Inventory InventoryConfig `yaml:"inventory"`
+	Job       JobConfig       `yaml:"job"`
+}
+
+type ServerConfig struct {
+	ApiPort     string `env:"API_PORT" yaml:"api_port" default:"8080" required:"true"`
+	SwaggerHost string `env:"SWAGGER_HOST" yaml:"swagger_host" default:"localhost:8080"`
+}
+
+type AuthConfig struct {
+	SecretKey        string `env:"SECRET_KEY" yaml:"secret_key" required:"true" secret:"true"`
+	ProcessActionUrl string `env:"PROCESS_ACTION_URL" yaml:"process_action_url"`
+	LoginPageUrl     string `env:"LOGIN_PAGE_URL" yaml:"login_page_url"`
+
+	// JWT signing, see utils/token_key.go
+	JwtAlgorithm string `env:"JWT_ALGORITHM" yaml:"jwt_algorithm" default:"HS256" required:"true"` // HS256, RS256 or EdDSA
+	JwtIssuer    string `env:"JWT_ISSUER" yaml:"jwt_issuer" default:"sonit-server" required:"true"`
+	JwtAudience  string `env:"JWT_AUDIENCE" yaml:"jwt_audience" default:"sonit-api" required:"true"`
+	JwtActiveKid string `env:"JWT_ACTIVE_KID" yaml:"jwt_active_kid"`             // Signing key id, newest key if empty
+	JwtKeyDir    string `env:"JWT_KEY_DIR" yaml:"jwt_key_dir" default:"keys"`    // PEM private keys named <kid>.pem for RS256 / EdDSA
+	JwtHmacKeys  string `env:"JWT_HMAC_KEYS" yaml:"jwt_hmac_keys" secret:"true"` // Extra HS256 keys as kid=secret,kid=secret
+
+	// Two-factor authentication
+	MfaIssuer           string `env:"MFA_ISSUER" yaml:"mfa_issuer" default:"Sonit"`                         // Account label in authenticator apps
+	MfaRequiredForAdmin bool   `env:"MFA_REQUIRED_FOR_ADMIN" yaml:"mfa_required_for_admin" default:"false"` // Permission protected routes reject logins without 2FA
+}
+
+type RoleConfig struct {
+	AdminRole string `env:"ADMIN_ROLE" yaml:"admin_role" default:"R001" required:"true"`
+	UserRole  string `env:"USER_ROLE" yaml:"user_role" default:"R003" required:"true"`
+}
+
+type DatabaseConfig struct {
+	PostgreCnnStr string `env:"DB_CNN_STR" yaml:"postgre_cnn_str" required:"true" secret:"true"`
+	MySQLCnnStr   string `env:"MYSQL_DB_CNNSTR" yaml:"mysql_cnn_str" secret:"true"`
+}
+
+type CacheConfig struct {
+	RedisAddress string        `env:"REDIS_PORT" yaml:"redis_address"`
+	Driver       string        `env:"CACHE_DRIVER" yaml:"driver" default:"redis"`                  // redis (falls back to memory if unavailable), memory or none
+	CatalogTTL   time.Duration `env:"CACHE_CATALOG_TTL" yaml:"catalog_ttl" default:"10m" min:"0s"` // 0 disables caching of the namespace
+	VoucherTTL   time.Duration `env:"CACHE_VOUCHER_TTL" yaml:"voucher_ttl" default:"1m" min:"0s"`
+	RoleTTL      time.Duration `env:"CACHE_ROLE_TTL" yaml:"role_ttl" default:"5m" min:"0s"` // Roles and their permission sets
+}
+
+// Rate limit of each route group as <max requests>/<window>, e.g. 10/1m. Empty disables the limit
+type RateLimitConfig struct {
+	Login    RateLimit `env:"RATE_LIMIT_LOGIN" yaml:"login" default:"10/1m"`
+	Register RateLimit `env:"RATE_LIMIT_REGISTER" yaml:"register" default:"5/1h"`
+	Checkout RateLimit `env:"RATE_LIMIT_CHECKOUT" yaml:"checkout" default:"20/1m"`
+}
+
+// Max requests in a sliding window, a zero limit disables it
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Parse limit as <max requests>/<window>, e.g. 10/1m
+func (r *RateLimit) UnmarshalText(text []byte) error {
+	var spec = string(text)
+	if spec == "" {
+		*r = RateLimit{}
+		return nil
+	}
+
+	limitValue, windowValue, ok := strings.Cut(spec, "/")
+	if !ok {
+		return errors.New("expected <max requests>/<window> but got " + spec)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(limitValue))
+	if err != nil {
+		return err
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(windowValue))
+	if err != nil {
+		return err
+	}
+
+	if limit < 0 || window <= 0 {
+		return errors.New("limit must not be negative and window must be positive but got " + spec)
+	}
+
+	*r = RateLimit{Limit: limit, Window: window}
+	return nil
+}
+
+func (r RateLimit) String() string {
+	if r.Limit == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(r.Limit) + "/" + r.Window.String()
+}
+
+// Account lockout after failed logins in a window, the lock duration doubles on each lockout up to the max duration
+type LockoutConfig struct {
+	Threshold    int           `env:"LOCKOUT_THRESHOLD" yaml:"threshold" default:"5" min:"1"` // Failed logins before lockout
+	Window       time.Duration `env:"LOCKOUT_WINDOW" yaml:"window" default:"15m" min:"1s"`    // Failed logins older than the window are forgotten
+	BaseDuration time.Duration `env:"LOCKOUT_BASE_DURATION" yaml:"base_duration" default:"5m" min:"1s"`
+	MaxDuration  time.Duration `env:"LOCKOUT_MAX_DURATION" yaml:"max_duration" default:"24h" min:"1s"`
+}
+
+// Password policy of every password set by users or operators
+type PasswordConfig struct {
+	MinLength       int    `env:"PASSWORD_MIN_LENGTH" yaml:"min_length" default:"8" min:"1"`
+	RequiredClasses string `env:"PASSWORD_REQUIRED_CLASSES" yaml:"required_classes" default:"upper,lower,digit,special"` // Comma separated of upper, lower, digit, special
+	HistorySize     int    `env:"PASSWORD_HISTORY_SIZE" yaml:"history_size" default:"5" min:"0"`                         // Last passwords which can't be reused, 0 disables
+	BreachListDir   string `env:"PASSWORD_BREACH_LIST_DIR" yaml:"breach_list_dir"`                                       // SHA-1 range files <PREFIX>.txt, empty disables breach check
+}
+
+// Self-service account deletion
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration `env:"ACCOUNT_DELETION_GRACE_PERIOD" yaml:"deletion_grace_period" default:"720h" min:"0s"` // Confirmed deletions can be cancelled until it passes
+}
+
+// VIP tiers are evaluated from completed orders created in the spend window, older orders no longer count
+type VipConfig struct {
+	SpendWindow time.Duration `env:"VIP_SPEND_WINDOW" yaml:"spend_window" default:"8760h" min:"1s"`
+}
+
+// Guest carts live for GuestTTL since last change and are merged into the user cart on login or registration.
+// Carts of users untouched for AbandonedAfter get one reminder per change until checkout or unsubscribe
+type CartConfig struct {
+	GuestTTL      time.Duration `env:"GUEST_CART_TTL" yaml:"guest_ttl" default:"168h" min:"1s"`
+	MergeStrategy string        `env:"CART_MERGE_STRATEGY" yaml:"merge_strategy" default:"sum" oneof:"sum,max,guest,user"` // See constant/action_type
+
+	AbandonedAfter         time.Duration `env:"ABANDONED_CART_AFTER" yaml:"abandoned_after" default:"24h" min:"1s"`                          // Carts untouched this long get a reminder
+	ReminderUrl            string        `env:"CART_REMINDER_URL" yaml:"reminder_url"`                                                       // Cart page linked by reminders
+	ReminderUnsubscribeUrl string        `env:"CART_REMINDER_UNSUBSCRIBE_URL" yaml:"reminder_unsubscribe_url"`                               // Token is appended as query parameter
+	ReminderVoucherPercent float64       `env:"CART_REMINDER_VOUCHER_PERCENT" yaml:"reminder_voucher_percent" default:"0" min:"0" max:"100"` // One-time voucher in reminders, 0 disables it
+	ReminderVoucherTTL     time.Duration `env:"CART_REMINDER_VOUCHER_TTL" yaml:"reminder_voucher_ttl" default:"72h" min:"1s"`
+}
+
+// Back in stock mails are sent by batches of StockNotificationBatch per run of the stock-notification job.
+// Days of cover of low stock products are estimated from units sold during the last SalesVelocityWindow
+type InventoryConfig struct {
+	StockNotificationBatch int           `env:"STOCK_NOTIFICATION_BATCH" yaml:"stock_notification_batch" default:"50" min:"1"`
+	ProductPageUrl         string        `env:"PRODUCT_PAGE_URL" yaml:"product_page_url"` // Product id is appended
+	SalesVelocityWindow    time.Duration `env:"INVENTORY_SALES_VELOCITY_WINDOW" yaml:"sales_velocity_window" default:"720h" min:"24h"`
+	DefaultWarehouse       string        `env:"INVENTORY_DEFAULT_WAREHOUSE" yaml:"default_warehouse" default:"main"` // Stock moved without a warehouse
+}
+
+// Interval of each background job run by serve, see "job run" command. 0 disables the job in serve
+type JobConfig struct {
+	AccountDeletionInterval time.Duration `env:"JOB_ACCOUNT_DELETION_INTERVAL" yaml:"account_deletion_interval" default:"1h" min:"0s"`
+	VipTierInterval         time.Duration `env:"JOB_VIP_TIER_INTERVAL" yaml:"vip_tier_interval" default:"24h" min:"0s"`
+	GuestCartInterval       time.Duration `env:"JOB_GUEST_CART_INTERVAL" yaml:"guest_cart_interval" default:"1h" min:"0s"`
+	AbandonedCartInterval   time.Duration `env:"JOB_ABANDONED_CART_INTERVAL" yaml:"abandoned_cart_interval" default:"1h" min:"0s"`
+	WishlistInterval        time.Duration `env:"JOB_WISHLIST_INTERVAL" yaml:"wishlist_interval" default:"1h" min:"0s"`
+	StockNotifyInterval     time.Duration `env:"JOB_STOCK_NOTIFICATION_INTERVAL" yaml:"stock_notification_interval" default:"5m" min:"0s"`
+	LowStockDigestInterval  time.Duration `env:"JOB_LOW_STOCK_DIGEST_INTERVAL" yaml:"low_stock_digest_interval" default:"24h" min:"0s"`
+}
+
+// OpenID Connect providers of social login, a provider is disabled if its client id is empty.
+// Issuers (comma separated) and JWKS URL can point to a local fake provider for testing
+type OidcConfig struct {
+	GoogleClientId   string `env:"GOOGLE_CLIENT_ID" yaml:"google_client_id"`
+	GoogleIssuers    string `env:"GOOGLE_ISSUERS" yaml:"google_issuers" default:"https://accounts.google.com,accounts.google.com"`
+	GoogleJwksUrl    string `env:"GOOGLE_JWKS_URL" yaml:"google_jwks_url" default:"https://www.googleapis.com/oauth2/v3/certs"`
+	FacebookClientId string `env:"FACEBOOK_APP_ID" yaml:"facebook_app_id"`
+	FacebookIssuers  string `env:"FACEBOOK_ISSUERS" yaml:"facebook_issuers" default:"https://www.facebook.com"`
+	FacebookJwksUrl  string `env:"FACEBOOK_JWKS_URL" yaml:"facebook_jwks_url" default:"https://limited.facebook.com/.well-known/oauth/openid/jwks/"`
+}
+
+type MailConfig struct {
+	SonitMailKey string `env:"SONIT_MAIL_KEY" yaml:"sonit_mail_key" secret:"true"`
+	ServiceEmail string `env:"SERVICE_EMAIL" yaml:"service_email"`
+	SecurityPass string `env:"SECURITY_PASS" yaml:"security_pass" secret:"true"`
+	Host         string `env:"HOST" yaml:"host"`
+	Port         string `env:"MAIL_PORT" yaml:"port"`
+}
+
+type PaymentConfig struct {
+	PayosBaseUrl           string `env:"PAYOS_BASE_URL" yaml:"payos_base_url" default:"https://api-merchant.payos.vn"`
+	PayosClientId          string `env:"PAYOS_CLIENT_ID" yaml:"payos_client_id"`
+	PayosApiKey            string `env:"PAYOS_API_KEY" yaml:"payos_api_key" secret:"true"`
+	PayosChecksumKey       string `env:"PAYOS_CHECKSUM_KEY" yaml:"payos_checksum_key" secret:"true"`
+	CallbackSuccess        string `env:"PAYMENT_CALLBACK_SUCCESS" yaml:"callback_success"`                 // FE
+	CallbackCancel         string `env:"PAYMENT_CALLBACK_CANCEL" yaml:"callback_cancel"`                   // FE
+	CallbackSuccessProcess string `env:"PAYMENT_CALLBACK_SUCCESS_PROCESS" yaml:"callback_success_process"` // Server
+	CallbackCancelProcess  string `env:"PAYMENT_CALLBACK_CANCEL_PROCESS" yaml:"callback_cancel_process"`   // Server
+}
@@ -0,0 +1,321 @@
+package config
+
+import (
+	"cmp"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	profile_env      string = "APP_PROFILE"
+	config_file_env  string = "CONFIG_FILE"
+	default_profile  string = "dev"
+	default_env_file string = ".env"
+	redacted_value   string = "******"
+)
+
+var (
+	current *Config
+	mu      sync.RWMutex
+
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Load configuration from env variables, .env / .env.<profile> files and an optional YAML file then validate it.
+// Values are parsed into the field types once here, an unparsable value fails loading.
+// The loaded configuration is kept as the global one even if validation fails so it can still be inspected
+func Load() (*Config, error) {
+	// .env.<profile> is loaded before .env so profile values win, real env variables are never overridden
+	var profile = os.Getenv(profile_env)
+	if profile != "" {
+		if err := loadEnvFile(default_env_file + "." + profile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnvFile(default_env_file); err != nil {
+		return nil, err
+	}
+
+	// Profile may be declared in .env
+	if profile == "" {
+		profile = os.Getenv(profile_env)
+		if profile == "" {
+			profile = default_profile
+		}
+
+		if err := loadEnvFile(default_env_file + "." + profile); err != nil {
+			return nil, err
+		}
+	}
+
+	var cfg Config
+	var invalid = applyDefaults(&cfg)
+
+	if err := applyYamlFile(&cfg, resolveYamlFile(profile)); err != nil {
+		return nil, err
+	}
+
+	invalid = append(invalid, applyEnv(&cfg)...)
+	cfg.Profile = profile
+
+	set(&cfg)
+
+	if len(invalid) > 0 {
+		return &cfg, errors.New("invalid configuration: " + strings.Join(invalid, ", "))
+	}
+
+	return &cfg, cfg.Validate()
+}
+
+// Get current configuration, falls back to defaults and env variables if it has not been loaded yet
+func Get() *Config {
+	mu.RLock()
+	var res = current
+	mu.RUnlock()
+
+	if res != nil {
+		return res
+	}
+
+	var cfg Config
+	applyDefaults(&cfg)
+	applyEnv(&cfg)
+	set(&cfg)
+
+	return &cfg
+}
+
+func set(cfg *Config) {
+	mu.Lock()
+	current = cfg
+	mu.Unlock()
+}
+
+// Validate required fields, bounds and allowed values
+func (c *Config) Validate() error {
+	var missing, invalid []string
+	walkFields(reflect.ValueOf(c).Elem(), func(field reflect.StructField, value reflect.Value) {
+		var name = field.Tag.Get("env")
+		if field.Tag.Get("required") == "true" && value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" {
+			missing = append(missing, name)
+		}
+
+		if bound, ok := field.Tag.Lookup("min"); ok && compareValue(value, bound) < 0 {
+			invalid = append(invalid, name+" must be at least "+bound)
+		}
+
+		if bound, ok := field.Tag.Lookup("max"); ok && compareValue(value, bound) > 0 {
+			invalid = append(invalid, name+" must be at most "+bound)
+		}
+
+		if values, ok := field.Tag.Lookup("oneof"); ok && !isOneOf(value.String(), values) {
+			invalid = append(invalid, name+" must be one of "+values)
+		}
+	})
+
+	if len(missing) > 0 {
+		return errors.New("missing required configuration: " + strings.Join(missing, ", "))
+	}
+
+	if len(invalid) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(invalid, ", "))
+	}
+
+	return nil
+}
+
+// Print configuration as env variables, secrets are masked when redacted
+func (c *Config) Print(w io.Writer, redacted bool) {
+	walkFields(reflect.ValueOf(c).Elem(), func(field reflect.StructField, value reflect.Value) {
+		var val = formatValue(value)
+		if redacted && field.Tag.Get("secret") == "true" && val != "" {
+			val = redacted_value
+		}
+
+		fmt.Fprintf(w, "%s = %q\n", field.Tag.Get("env"), val)
+	})
+}
+
+func loadEnvFile(path string) error {
+	if err := godotenv.Load(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("invalid env file %s - %s", path, err.Error())
+	}
+
+	return nil
+}
+
+// YAML file is taken from CONFIG_FILE, otherwise config.<profile>.yaml or config.yaml if existed
+func resolveYamlFile(profile string) string {
+	if path := os.Getenv(config_file_env); path != "" {
+		return path
+	}
+
+	for _, path := range []string{"config." + profile + ".yaml", "config.yaml"} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
+func applyYamlFile(cfg *Config, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("invalid config file %s - %s", path, err.Error())
+	}
+
+	return nil
+}
+
+// Apply default values, returns the fields whose default can't be parsed
+func applyDefaults(cfg *Config) []string {
+	var invalid []string
+	walkFields(reflect.ValueOf(cfg).Elem(), func(field reflect.StructField, value reflect.Value) {
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if err := setValue(value, def); err != nil {
+				invalid = append(invalid, field.Tag.Get("env")+" default - "+err.Error())
+			}
+		}
+	})
+
+	return invalid
+}
+
+// Apply env variables, returns the variables whose value can't be parsed. Such fields keep their previous value
+func applyEnv(cfg *Config) []string {
+	var invalid []string
+	walkFields(reflect.ValueOf(cfg).Elem(), func(field reflect.StructField, value reflect.Value) {
+		var name = field.Tag.Get("env")
+		if val, ok := os.LookupEnv(name); ok && val != "" {
+			if err := setValue(value, val); err != nil {
+				invalid = append(invalid, name+" - "+err.Error())
+			}
+		}
+	})
+
+	return invalid
+}
+
+// Parse a raw value into the field by its type: string, int, bool, float64, time.Duration or encoding.TextUnmarshaler
+func setValue(value reflect.Value, raw string) error {
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(strings.TrimSpace(raw)))
+	}
+
+	if value.Kind() == reflect.String {
+		value.SetString(raw)
+		return nil
+	}
+
+	raw = strings.TrimSpace(raw)
+	if value.Type() == durationType {
+		res, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(int64(res))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Int:
+		res, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(int64(res))
+	case reflect.Bool:
+		res, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(res)
+	case reflect.Float64:
+		res, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(res)
+	default:
+		return errors.New("unsupported type " + value.Type().String())
+	}
+
+	return nil
+}
+
+// Compare a numeric or duration field with a bound written like its values, e.g. 1 or 24h
+func compareValue(value reflect.Value, bound string) int {
+	var parsed = reflect.New(value.Type()).Elem()
+	if err := setValue(parsed, bound); err != nil {
+		panic("invalid bound " + bound + " of " + value.Type().String() + " field")
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int64:
+		return cmp.Compare(value.Int(), parsed.Int())
+	case reflect.Float64:
+		return cmp.Compare(value.Float(), parsed.Float())
+	}
+
+	panic("bound on unsupported type " + value.Type().String())
+}
+
+// Check value against comma separated allowed values
+func isOneOf(value, values string) bool {
+	for _, allowed := range strings.Split(values, ",") {
+		if value == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value as written in env variables
+func formatValue(value reflect.Value) string {
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+// Visit every field holding an env tag, nested sections included
+func walkFields(v reflect.Value, fn func(field reflect.StructField, value reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		var field = v.Type().Field(i)
+		var value = v.Field(i)
+
+		if field.Tag.Get("env") != "" {
+			fn(field, value)
+		} else if value.Kind() == reflect.Struct {
+			walkFields(value, fn)
+		}
+	}
+}
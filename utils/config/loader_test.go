@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Load with the given env variables, run from an empty dir so no .env or config file is picked up
+func loadWithEnv(t *testing.T, env map[string]string) (*Config, error) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+
+	t.Setenv("SECRET_KEY", "secret")
+	t.Setenv("DB_CNN_STR", "postgres://localhost/sonit")
+	for key, val := range env {
+		t.Setenv(key, val)
+	}
+
+	return Load()
+}
+
+func TestLoadParsesTypedValues(t *testing.T) {
+	cfg, err := loadWithEnv(t, map[string]string{
+		"LOCKOUT_THRESHOLD":             "3",
+		"LOCKOUT_WINDOW":                "30m",
+		"MFA_REQUIRED_FOR_ADMIN":        "true",
+		"CART_REMINDER_VOUCHER_PERCENT": "12.5",
+		"RATE_LIMIT_LOGIN":              " 7 / 2m ",
+		"RATE_LIMIT_REGISTER":           "",
+		"JOB_VIP_TIER_INTERVAL":         "0",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if cfg.Lockout.Threshold != 3 || cfg.Lockout.Window != 30*time.Minute || cfg.Lockout.MaxDuration != 24*time.Hour {
+		t.Errorf("got lockout %+v", cfg.Lockout)
+	}
+
+	if !cfg.Auth.MfaRequiredForAdmin {
+		t.Errorf("got MFA required %v, want true", cfg.Auth.MfaRequiredForAdmin)
+	}
+
+	if cfg.Cart.ReminderVoucherPercent != 12.5 {
+		t.Errorf("got voucher percent %v, want 12.5", cfg.Cart.ReminderVoucherPercent)
+	}
+
+	if cfg.RateLimit.Login != (RateLimit{Limit: 7, Window: 2 * time.Minute}) {
+		t.Errorf("got login rate limit %+v", cfg.RateLimit.Login)
+	}
+
+	// Empty env variables are ignored
+	if cfg.RateLimit.Register != (RateLimit{Limit: 5, Window: time.Hour}) {
+		t.Errorf("got register rate limit %+v", cfg.RateLimit.Register)
+	}
+
+	if cfg.Job.VipTierInterval != 0 || cfg.Job.StockNotifyInterval != 5*time.Minute {
+		t.Errorf("got jobs %+v", cfg.Job)
+	}
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	var tests = []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"unparsable int", map[string]string{"LOCKOUT_THRESHOLD": "five"}, "LOCKOUT_THRESHOLD"},
+		{"unparsable duration", map[string]string{"LOCKOUT_WINDOW": "15"}, "LOCKOUT_WINDOW"},
+		{"unparsable bool", map[string]string{"MFA_REQUIRED_FOR_ADMIN": "maybe"}, "MFA_REQUIRED_FOR_ADMIN"},
+		{"unparsable rate limit", map[string]string{"RATE_LIMIT_LOGIN": "10 per minute"}, "RATE_LIMIT_LOGIN"},
+		{"rate limit without window", map[string]string{"RATE_LIMIT_CHECKOUT": "10/0s"}, "RATE_LIMIT_CHECKOUT"},
+		{"below min", map[string]string{"LOCKOUT_THRESHOLD": "0"}, "LOCKOUT_THRESHOLD must be at least 1"},
+		{"negative duration", map[string]string{"JOB_WISHLIST_INTERVAL": "-1h"}, "JOB_WISHLIST_INTERVAL must be at least 0s"},
+		{"duration below min", map[string]string{"INVENTORY_SALES_VELOCITY_WINDOW": "12h"}, "INVENTORY_SALES_VELOCITY_WINDOW must be at least 24h"},
+		{"above max", map[string]string{"CART_REMINDER_VOUCHER_PERCENT": "150"}, "CART_REMINDER_VOUCHER_PERCENT must be at most 100"},
+		{"not allowed", map[string]string{"CART_MERGE_STRATEGY": "newest"}, "CART_MERGE_STRATEGY must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadWithEnv(t, tt.env)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got %v, want error with %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadYamlFile(t *testing.T) {
+	var dir = t.TempDir()
+	var path = filepath.Join(dir, "config.yaml")
+	var data = "lockout:\n  threshold: 4\n  base_duration: 10m\nrate_limit:\n  checkout: 3/1s\nauth:\n  mfa_required_for_admin: true\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadWithEnv(t, map[string]string{"CONFIG_FILE": path, "LOCKOUT_THRESHOLD": "6"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Env variables win over the file
+	if cfg.Lockout.Threshold != 6 || cfg.Lockout.BaseDuration != 10*time.Minute {
+		t.Errorf("got lockout %+v", cfg.Lockout)
+	}
+
+	if cfg.RateLimit.Checkout != (RateLimit{Limit: 3, Window: time.Second}) || !cfg.Auth.MfaRequiredForAdmin {
+		t.Errorf("got rate limit %+v, MFA required %v", cfg.RateLimit.Checkout, cfg.Auth.MfaRequiredForAdmin)
+	}
+}
+
+func TestDefaultsAreValid(t *testing.T) {
+	var cfg Config
+	if invalid := applyDefaults(&cfg); len(invalid) > 0 {
+		t.Fatalf("invalid defaults %v", invalid)
+	}
+
+	cfg.Auth.SecretKey = "secret"
+	cfg.Database.PostgreCnnStr = "postgres://localhost/sonit"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Pack each value as an indented JSON file of a ZIP archive, files are named by their keys
+func ToJsonZipArchive(files map[string]interface{}) ([]byte, error) {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	var writer = zip.NewWriter(&buf)
+	for _, name := range names {
+		data, err := json.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := writer.Create(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := f.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
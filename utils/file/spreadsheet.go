@@ -0,0 +1,244 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"path/filepath"
+	file_support "sonit_server/constant/file/file_support"
+	"sonit_server/constant/noti"
+	"strconv"
+	"strings"
+)
+
+// Largest spreadsheet accepted, also caps each decompressed part of an XLSX file
+const Spreadsheet_max_size int64 = 5 << 20
+
+// Row of a spreadsheet with its line number in the file
+type SpreadsheetRow struct {
+	Line  int
+	Cells []string
+}
+
+// Rows of a CSV file or of the first sheet of an XLSX file, format is told by the file name
+func ReadSpreadsheet(name string, data []byte) ([]SpreadsheetRow, error) {
+	if int64(len(data)) > Spreadsheet_max_size {
+		return nil, errors.New(noti.SPREADSHEET_INVALID_WARN_MSG)
+	}
+
+	var rows []SpreadsheetRow
+	var err error
+
+	switch strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".") {
+	case file_support.CSV_FORMAT:
+		rows, err = readCsv(data)
+	case file_support.XLSX_FORMAT:
+		rows, err = readXlsx(data)
+	default:
+		return nil, errors.New(noti.SPREADSHEET_INVALID_WARN_MSG)
+	}
+
+	if err != nil {
+		return nil, errors.New(noti.SPREADSHEET_INVALID_WARN_MSG)
+	}
+
+	return rows, nil
+}
+
+func readCsv(data []byte) ([]SpreadsheetRow, error) {
+	var reader = csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))) // Excel adds a BOM
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var res []SpreadsheetRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		res = append(res, SpreadsheetRow{Line: line, Cells: record})
+	}
+
+	return res, nil
+}
+
+// Parts of an XLSX file read to get the cells of its first sheet
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationId string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// Plain text or rich text runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Line  int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func (t xlsxText) String() string {
+	var res = t.Text
+	for _, run := range t.Runs {
+		res += run.Text
+	}
+
+	return res
+}
+
+func readXlsx(data []byte) ([]SpreadsheetRow, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var workbook xlsxWorkbook
+	if err := readXlsxPart(archive, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+
+	var relationships xlsxRelationships
+	if err := readXlsxPart(archive, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return nil, errors.New("workbook has no sheet")
+	}
+
+	var sheetPath string
+	for _, relationship := range relationships.Relationships {
+		if relationship.Id == workbook.Sheets[0].RelationId {
+			// Targets are relative to the workbook or absolute in the archive
+			if strings.HasPrefix(relationship.Target, "/") {
+				sheetPath = strings.TrimPrefix(relationship.Target, "/")
+			} else {
+				sheetPath = path.Join("xl", relationship.Target)
+			}
+		}
+	}
+
+	// Workbooks without text have no shared strings
+	var sharedStrings xlsxSharedStrings
+	if err := readXlsxPart(archive, "xl/sharedStrings.xml", &sharedStrings); err != nil && !errors.Is(err, errXlsxPartMissing) {
+		return nil, err
+	}
+
+	var sheet xlsxSheet
+	if err := readXlsxPart(archive, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	var res []SpreadsheetRow
+	for index, row := range sheet.Rows {
+		var line = row.Line
+		if line == 0 { // Row number is optional
+			line = index + 1
+		}
+
+		var cells []string
+		for _, cell := range row.Cells {
+			var column = len(cells)
+			if cell.Ref != "" {
+				column = getXlsxColumn(cell.Ref)
+			}
+
+			if column < 0 || column >= xlsx_max_columns {
+				return nil, errors.New("invalid reference of cell " + cell.Ref)
+			}
+
+			// Empty cells are left out of the sheet
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				position, err := strconv.Atoi(cell.Value)
+				if err != nil || position < 0 || position >= len(sharedStrings.Items) {
+					return nil, errors.New("invalid shared string of cell " + cell.Ref)
+				}
+
+				cells[column] = sharedStrings.Items[position].String()
+			case "inlineStr":
+				cells[column] = cell.Inline.String()
+			default:
+				cells[column] = cell.Value
+			}
+		}
+
+		res = append(res, SpreadsheetRow{Line: line, Cells: cells})
+	}
+
+	return res, nil
+}
+
+// Columns of an Excel sheet, A to XFD
+const xlsx_max_columns int = 16384
+
+var errXlsxPartMissing = errors.New("xlsx part missing")
+
+func readXlsxPart(archive *zip.Reader, name string, v interface{}) error {
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		return xml.NewDecoder(io.LimitReader(rc, Spreadsheet_max_size)).Decode(v)
+	}
+
+	return errXlsxPartMissing
+}
+
+// Zero based column of a cell reference, e.g. "B3" -> 1
+func getXlsxColumn(ref string) int {
+	var res int
+	for _, c := range strings.ToUpper(ref) {
+		if c < 'A' || c > 'Z' || res > xlsx_max_columns {
+			break
+		}
+
+		res = res*26 + int(c-'A'+1)
+	}
+
+	return res - 1
+}
@@ -0,0 +1,216 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"sonit_server/constant/noti"
+	"strings"
+	"testing"
+)
+
+const (
+	xlsx_workbook string = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Stock" sheetId="1" r:id="rId1"/><sheet name="Other" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`
+
+	xlsx_relationships string = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	xlsx_shared_strings string = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="5" uniqueCount="5">
+<si><t>sku</t></si><si><t>quantity</t></si><si><t>action</t></si><si><t>note</t></si>
+<si><r><rPr><b/></rPr><t>Restock </t></r><r><t>from supplier</t></r></si>
+</sst>`
+
+	xlsx_other_sheet string = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>wrong sheet</t></is></c></row>
+</sheetData></worksheet>`
+)
+
+// XLSX archive of the given parts
+func buildXlsx(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var writer = zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// Workbook parts of a file whose first sheet is the given one
+func xlsxParts(sheet string) map[string]string {
+	return map[string]string{
+		"xl/workbook.xml":            xlsx_workbook,
+		"xl/_rels/workbook.xml.rels": xlsx_relationships,
+		"xl/sharedStrings.xml":       xlsx_shared_strings,
+		"xl/worksheets/sheet1.xml":   sheet,
+		"xl/worksheets/sheet2.xml":   xlsx_other_sheet,
+	}
+}
+
+func xlsxSheetOf(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func TestReadSpreadsheetCsv(t *testing.T) {
+	var data = "\xef\xbb\xbfsku,quantity,action,note\n" +
+		"prod1, 10,import,\"Restock, from supplier\"\n" +
+		"\n" +
+		"prod2,5,export,\"Two\nlines\"\n" +
+		"prod3,1\n"
+
+	rows, err := ReadSpreadsheet("IMPORT.CSV", []byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var want = []SpreadsheetRow{
+		{Line: 1, Cells: []string{"sku", "quantity", "action", "note"}},
+		{Line: 2, Cells: []string{"prod1", "10", "import", "Restock, from supplier"}},
+		{Line: 4, Cells: []string{"prod2", "5", "export", "Two\nlines"}},
+		{Line: 6, Cells: []string{"prod3", "1"}},
+	}
+
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("got %+v, want %+v", rows, want)
+	}
+}
+
+func TestReadSpreadsheetXlsx(t *testing.T) {
+	var sheet = xlsxSheetOf(`
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>prod1</t></is></c><c r="B2"><v>10</v></c><c r="C2" t="str"><v>import</v></c><c r="D2" t="s"><v>4</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><r><t>prod</t></r><r><t>2</t></r></is></c><c r="C4" t="inlineStr"><is><t>export</t></is></c></row>
+<row r="5"><c r="D5" t="inlineStr"><is><t>note only</t></is></c><c r="A5" t="inlineStr"><is><t>prod3</t></is></c></row>`)
+
+	rows, err := ReadSpreadsheet("import.xlsx", buildXlsx(t, xlsxParts(sheet)))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var want = []SpreadsheetRow{
+		{Line: 1, Cells: []string{"sku", "quantity", "action", "note"}},
+		{Line: 2, Cells: []string{"prod1", "10", "import", "Restock from supplier"}},
+		{Line: 4, Cells: []string{"prod2", "", "export"}},
+		{Line: 5, Cells: []string{"prod3", "", "", "note only"}},
+	}
+
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("got %+v, want %+v", rows, want)
+	}
+}
+
+func TestReadSpreadsheetXlsxOptionalParts(t *testing.T) {
+	// Rows and cells without references, no shared strings and a sheet target absolute in the archive
+	var parts = map[string]string{
+		"xl/workbook.xml":            xlsx_workbook,
+		"xl/_rels/workbook.xml.rels": strings.Replace(xlsx_relationships, `Target="worksheets/sheet1.xml"`, `Target="/xl/sheets/first.xml"`, 1),
+		"xl/sheets/first.xml": xlsxSheetOf(`
+<row><c t="inlineStr"><is><t>sku</t></is></c><c t="inlineStr"><is><t>quantity</t></is></c></row>
+<row><c t="inlineStr"><is><t>prod1</t></is></c><c><v>3</v></c></row>`),
+	}
+
+	rows, err := ReadSpreadsheet("import.xlsx", buildXlsx(t, parts))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var want = []SpreadsheetRow{
+		{Line: 1, Cells: []string{"sku", "quantity"}},
+		{Line: 2, Cells: []string{"prod1", "3"}},
+	}
+
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("got %+v, want %+v", rows, want)
+	}
+}
+
+func TestReadSpreadsheetRejectsInvalidFiles(t *testing.T) {
+	var withoutPart = func(name string) map[string]string {
+		var parts = xlsxParts(xlsxSheetOf(""))
+		delete(parts, name)
+		return parts
+	}
+
+	var tests = []struct {
+		name     string
+		fileName string
+		data     func(t *testing.T) []byte
+	}{
+		{"unsupported format", "import.xls", func(t *testing.T) []byte { return []byte("sku\nprod1") }},
+		{"too large", "import.csv", func(t *testing.T) []byte { return bytes.Repeat([]byte("a"), int(Spreadsheet_max_size)+1) }},
+		{"malformed csv", "import.csv", func(t *testing.T) []byte { return []byte("sku,note\nprod1,\"unclosed\n") }},
+		{"not a zip archive", "import.xlsx", func(t *testing.T) []byte { return []byte("sku,quantity") }},
+		{"missing workbook", "import.xlsx", func(t *testing.T) []byte { return buildXlsx(t, withoutPart("xl/workbook.xml")) }},
+		{"missing sheet", "import.xlsx", func(t *testing.T) []byte { return buildXlsx(t, withoutPart("xl/worksheets/sheet1.xml")) }},
+		{"workbook without sheet", "import.xlsx", func(t *testing.T) []byte {
+			var parts = xlsxParts(xlsxSheetOf(""))
+			parts["xl/workbook.xml"] = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheets/></workbook>`
+			return buildXlsx(t, parts)
+		}},
+		{"unknown shared string", "import.xlsx", func(t *testing.T) []byte {
+			return buildXlsx(t, xlsxParts(xlsxSheetOf(`<row r="1"><c r="A1" t="s"><v>5</v></c></row>`)))
+		}},
+		{"shared string without index", "import.xlsx", func(t *testing.T) []byte {
+			return buildXlsx(t, xlsxParts(xlsxSheetOf(`<row r="1"><c r="A1" t="s"><v>sku</v></c></row>`)))
+		}},
+		{"column beyond XFD", "import.xlsx", func(t *testing.T) []byte {
+			return buildXlsx(t, xlsxParts(xlsxSheetOf(`<row r="1"><c r="XFE1"><v>1</v></c></row>`)))
+		}},
+		{"reference without column", "import.xlsx", func(t *testing.T) []byte {
+			return buildXlsx(t, xlsxParts(xlsxSheetOf(`<row r="1"><c r="11"><v>1</v></c></row>`)))
+		}},
+		{"malformed sheet", "import.xlsx", func(t *testing.T) []byte {
+			return buildXlsx(t, xlsxParts(`<worksheet><sheetData><row>`))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ReadSpreadsheet(tt.fileName, tt.data(t))
+			if err == nil || err.Error() != noti.SPREADSHEET_INVALID_WARN_MSG {
+				t.Fatalf("got %+v, %v, want rejected", rows, err)
+			}
+		})
+	}
+}
+
+func TestGetXlsxColumn(t *testing.T) {
+	var tests = []struct {
+		ref  string
+		want int
+	}{
+		{"A1", 0},
+		{"b3", 1},
+		{"Z10", 25},
+		{"AA1", 26},
+		{"AZ7", 51},
+		{"XFD1048576", xlsx_max_columns - 1},
+		{"1", -1},
+	}
+
+	for _, tt := range tests {
+		if res := getXlsxColumn(tt.ref); res != tt.want {
+			t.Errorf("column of %s: got %d, want %d", tt.ref, res, tt.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"log"
 	"sonit_server/constant/noti"
@@ -22,3 +24,9 @@ func ToHashString(src string, logger *log.Logger) (string, error) {
 func IsHashStringMatched(inputtedStr, hashedStr string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hashedStr), []byte(inputtedStr)) == nil
 }
+
+// Hash of high-entropy secrets such as tokens, which are looked up by hash so bcrypt is not needed
+func ToSHA256String(src string) string {
+	var sum = sha256.Sum256([]byte(src))
+	return hex.EncodeToString(sum[:])
+}
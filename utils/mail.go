@@ -4,10 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"os"
-	"sonit_server/constant/env"
 	"sonit_server/constant/noti"
 	"sonit_server/model/dto/request"
+	"sonit_server/utils/config"
 	"text/template"
 
 	"github.com/sendgrid/sendgrid-go"
@@ -50,7 +49,7 @@ func SendMail(req request.SendMailRequest) error {
 	// 	return errors.New(noti.GENERATE_MAIL_WARN_MSG)
 	// }
 
-	res, err := sendgrid.NewSendClient(os.Getenv(env.SONIT_MAIL_KEY)).Send(mail.NewSingleEmail(
+	res, err := sendgrid.NewSendClient(config.Get().Mail.SonitMailKey).Send(mail.NewSingleEmail(
 		mail.NewEmail("Sonit Custom", "phuchtqse183980@fpt.edu.vn"),
 		req.Body.Subject,
 		mail.NewEmail("", req.Body.Email),
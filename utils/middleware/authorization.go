@@ -1,10 +1,14 @@
 package middleware
 
 import (
+	"errors"
 	"log"
-	"os"
-	"sonit_server/constant/env"
+	"sonit_server/constant/noti"
+	"sonit_server/data_access/cache"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
 	"sonit_server/utils"
+	"sonit_server/utils/config"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,27 +26,96 @@ func Authorize(ctx *gin.Context) {
 		return
 	}
 
-	userId, role, _, err := utils.ExtractDataFromToken(token, utils.GetLogConfig())
+	var logger = utils.GetLogConfig()
+
+	claims, err := utils.ParseToken(token, logger)
 	if err != nil {
 		utils.ProcessResponse(unAuthBodyResponse)
 		ctx.Abort()
 		return
 	}
 
-	log.Println("Role: ", role)
+	// Only access tokens which are not revoked are accepted
+	if claims.TokenUse != utils.ACCESS_TOKEN_USE || claims.Role == "" || cache.IsTokenRevoked(claims.ID, logger) {
+		utils.ProcessResponse(unAuthBodyResponse)
+		ctx.Abort()
+		return
+	}
+
+	log.Println("Role: ", claims.Role)
 
-	ctx.Set("userId", userId)
-	ctx.Set("role", role)
+	ctx.Set("userId", claims.UserId)
+	ctx.Set("role", claims.Role)
+	ctx.Set("jti", claims.ID)
+	ctx.Set("sid", claims.SessionId)
+	ctx.Set("mfa", claims.Mfa)
+	ctx.Set("tokenExp", claims.ExpiresAt.Time)
 	ctx.Next()
 }
 
-func AdminAuthorization(ctx *gin.Context) {
-	log.Println("Role access: ", ctx.GetString("role"))
-	if ctx.GetString("role") != os.Getenv(env.ADMIN_ROLE) {
+// Allow request only if the role of the user is granted every permission
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !checkPermissions(ctx, permissions) {
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Require permissions only when the given request value matches, e.g. a status update to REFUNDED
+func RequirePermissionWhen(source IdSource, value string, permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if source(ctx) == value && !checkPermissions(ctx, permissions) {
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Whether the role set by Authorize is granted every permission, overridden in tests
+var hasPermissions = func(ctx *gin.Context, permissions []string) (bool, error) {
+	service, err := business_logic.GenerateRoleService()
+	if err != nil {
+		return false, err
+	}
+
+	return service.HasPermissions(ctx.GetString("role"), permissions, ctx)
+}
+
+// Check permissions and the second factor policy of staff, the rejection response is written if not allowed
+func checkPermissions(ctx *gin.Context, permissions []string) bool {
+	isAllowed, err := hasPermissions(ctx, permissions)
+	if err != nil {
+		utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+		return false
+	}
+
+	if !isAllowed {
 		utils.ProcessResponse(utils.GetUnAuthBodyResponse(ctx))
-		ctx.Abort()
-		return
+		return false
 	}
 
-	ctx.Next()
+	if isMfaMissing(ctx) {
+		utils.ProcessResponse(getMfaRequiredResponse(ctx))
+		return false
+	}
+
+	return true
+}
+
+// Staff has to log in with second factor when the policy is on
+func isMfaMissing(ctx *gin.Context) bool {
+	return config.Get().Auth.MfaRequiredForAdmin && !ctx.GetBool("mfa")
+}
+
+func getMfaRequiredResponse(ctx *gin.Context) response.APIResponse {
+	return response.APIResponse{
+		ErrMsg:  errors.New(noti.MFA_REQUIRED_WARN_MSG),
+		Context: ctx,
+	}
 }
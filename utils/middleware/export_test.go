@@ -0,0 +1,11 @@
+package middleware
+
+// Method and path of the routes covered by the ownership tests
+func GuardedRouteNames() []string {
+	var res []string
+	for _, route := range guardedRoutes {
+		res = append(res, route.name())
+	}
+
+	return res
+}
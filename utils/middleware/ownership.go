@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sonit_server/constant/noti"
+	"sonit_server/model/dto/response"
+	business_logic "sonit_server/usecase/business_logic"
+	"sonit_server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Read id of the requested resource from the request
+type IdSource func(ctx *gin.Context) string
+
+// Resolve the user owning the requested resource, empty if the resource doesn't exist
+type OwnerResolver func(ctx *gin.Context) (string, error)
+
+// Id from a path param
+func FromParam(name string) IdSource {
+	return func(ctx *gin.Context) string {
+		return ctx.Param(name)
+	}
+}
+
+// Id from a JSON body field, nested fields are given as path e.g. FromBody("request", "user_id").
+// The body is restored so handlers can still bind it
+func FromBody(path ...string) IdSource {
+	return func(ctx *gin.Context) string {
+		if ctx.Request.Body == nil {
+			return ""
+		}
+
+		data, err := io.ReadAll(ctx.Request.Body)
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+		if err != nil {
+			return ""
+		}
+
+		var value interface{}
+		if json.Unmarshal(data, &value) != nil {
+			return ""
+		}
+
+		for _, key := range path {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+
+			value = obj[key]
+		}
+
+		res, _ := value.(string)
+		return res
+	}
+}
+
+// The id is the owner's user id itself
+func UserOwner(id IdSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		return id(ctx), nil
+	}
+}
+
+// Owner of an order
+func OrderOwner(id IdSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		return getOrderOwner(id(ctx), ctx)
+	}
+}
+
+// Owner of a payment
+func PaymentOwner(id IdSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		return getPaymentOwner(id(ctx), ctx)
+	}
+}
+
+// Owner lookups of orders and payments, overridden in tests
+var (
+	getOrderOwner = func(id string, ctx *gin.Context) (string, error) {
+		service, err := business_logic.GenerateOrderService()
+		if err != nil {
+			return "", err
+		}
+
+		order, err := service.GetOrder(id, ctx)
+		if err != nil {
+			if err.Error() == noti.GENERIC_ERROR_WARN_MSG { // Order not found
+				return "", nil
+			}
+
+			return "", err
+		}
+
+		return order.UserId, nil
+	}
+
+	getPaymentOwner = func(id string, ctx *gin.Context) (string, error) {
+		service, err := business_logic.GeneratePaymentService()
+		if err != nil {
+			return "", err
+		}
+
+		payment, err := service.GetPaymentById(id, ctx)
+		if err != nil || payment == nil {
+			return "", err
+		}
+
+		return payment.UserId, nil
+	}
+)
+
+// Allow request only if the user set by Authorize owns the resource.
+// Roles granted the bypass permission access resources of every user, empty bypass means owner only.
+// The bypass follows the second factor policy of RequirePermission, without it users only reach their own resources
+func RequireOwnership(resolve OwnerResolver, bypass string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var isBypassed bool
+		if bypass != "" {
+			isAllowed, err := hasPermissions(ctx, []string{bypass})
+			if err != nil {
+				utils.ProcessResponse(utils.GenerateInvalidRequestAndSystemProblemModel(ctx, err))
+				ctx.Abort()
+				return
+			}
+
+			if isAllowed && !isMfaMissing(ctx) {
+				ctx.Next()
+				return
+			}
+
+			isBypassed = isAllowed
+		}
+
+		owner, err := resolve(ctx)
+		if err != nil {
+			utils.ProcessResponse(response.APIResponse{
+				ErrMsg:  err,
+				Context: ctx,
+			})
+			ctx.Abort()
+			return
+		}
+
+		// Missing resources are treated as not owned so ids of other users can't be probed
+		if owner == "" || owner != ctx.GetString("userId") {
+			if isBypassed {
+				utils.ProcessResponse(getMfaRequiredResponse(ctx))
+			} else {
+				utils.ProcessResponse(utils.GetUnAuthBodyResponse(ctx))
+			}
+
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
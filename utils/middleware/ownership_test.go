@@ -0,0 +1,344 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sonit_server/constant/noti"
+	"sonit_server/constant/permission"
+	"sonit_server/utils/config"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	owner_id      string = "user1"
+	other_id      string = "user2"
+	customer_role string = "R003"
+	staff_role    string = "R002"
+)
+
+// Routes of api_route guarded by RequireOwnership, id is read from param or from the body path.
+// TestRoutesAreGuarded checks the list against the registered routes
+type guardedRoute struct {
+	method string
+	path   string
+	param  string
+	body   []string
+	owner  func(IdSource) OwnerResolver
+	bypass string
+}
+
+var guardedRoutes = []guardedRoute{
+	{method: http.MethodGet, path: "/users/:id", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodPut, path: "/users/update-info/:actorId", param: "actorId", owner: UserOwner},
+	{method: http.MethodPost, path: "/users/vip/create", body: []string{"user_id"}, owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodPut, path: "/users/:id/password", param: "id", owner: UserOwner},
+	{method: http.MethodGet, path: "/users/:id/export", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodGet, path: "/users/:id/vip-tier", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodDelete, path: "/users/:id", param: "id", owner: UserOwner},
+	{method: http.MethodDelete, path: "/users/:id/deletion", param: "id", owner: UserOwner},
+	{method: http.MethodGet, path: "/users/:id/sessions", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodDelete, path: "/users/:id/sessions", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodDelete, path: "/users/:id/sessions/:sessionId", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodPost, path: "/users/:id/mfa/totp/enroll", param: "id", owner: UserOwner},
+	{method: http.MethodPost, path: "/users/:id/mfa/totp/confirm", param: "id", owner: UserOwner},
+	{method: http.MethodDelete, path: "/users/:id/mfa/totp", param: "id", owner: UserOwner},
+	{method: http.MethodPost, path: "/users/:id/mfa/recovery-codes", param: "id", owner: UserOwner},
+	{method: http.MethodPost, path: "/auth/logout/:id", param: "id", owner: UserOwner},
+	{method: http.MethodGet, path: "/stock-subscriptions/:id", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodPost, path: "/stock-subscriptions", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodDelete, path: "/stock-subscriptions", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodGet, path: "/wishlists/:id", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodPost, path: "/wishlists/item/add", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodDelete, path: "/wishlists/item/remove", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodPost, path: "/wishlists/item/move-to-cart", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodPost, path: "/wishlists/item/save-for-later", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodGet, path: "/carts/:id", param: "id", owner: UserOwner, bypass: permission.USER_MANAGE},
+	{method: http.MethodPut, path: "/carts/:id/acknowledge", param: "id", owner: UserOwner},
+	{method: http.MethodPost, path: "/carts/item/add", body: []string{"request", "user_id"}, owner: UserOwner},
+	{method: http.MethodPut, path: "/carts/item/edit", body: []string{"request", "user_id"}, owner: UserOwner},
+	{method: http.MethodDelete, path: "/carts/item/remove", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodPut, path: "/carts/reminders", body: []string{"user_id"}, owner: UserOwner},
+	{method: http.MethodGet, path: "/orders/user/:id", param: "id", owner: UserOwner, bypass: permission.ORDER_READ},
+	{method: http.MethodGet, path: "/orders/:id", param: "id", owner: OrderOwner, bypass: permission.ORDER_READ},
+	{method: http.MethodPost, path: "/orders/create", body: []string{"user_id"}, owner: UserOwner, bypass: permission.ORDER_WRITE},
+	{method: http.MethodPut, path: "/orders/update", body: []string{"order_id"}, owner: OrderOwner, bypass: permission.ORDER_WRITE},
+	{method: http.MethodGet, path: "/payments/user/:id", param: "id", owner: UserOwner, bypass: permission.PAYMENT_READ},
+	{method: http.MethodGet, path: "/payments/:id", param: "id", owner: PaymentOwner, bypass: permission.PAYMENT_READ},
+	{method: http.MethodPost, path: "/payments/create/cart", body: []string{"user_id"}, owner: UserOwner, bypass: permission.PAYMENT_WRITE},
+	{method: http.MethodPost, path: "/payments/create/direct", body: []string{"user_id"}, owner: UserOwner, bypass: permission.PAYMENT_WRITE},
+}
+
+// Resource ids owned by owner_id, "missing" doesn't exist
+var testOwners = map[string]string{
+	"order1": owner_id,
+	"pay1":   owner_id,
+}
+
+// Id of the resource of owner_id requested through the route
+func (r guardedRoute) ownedId() string {
+	switch r.path {
+	case "/orders/:id", "/orders/update":
+		return "order1"
+	case "/payments/:id":
+		return "pay1"
+	}
+
+	return owner_id
+}
+
+func (r guardedRoute) name() string {
+	return r.method + " " + r.path
+}
+
+func (r guardedRoute) source() IdSource {
+	if r.param != "" {
+		return FromParam(r.param)
+	}
+
+	return FromBody(r.body...)
+}
+
+// Request of the route for id, body routes carry the id in a JSON body next to other fields
+func (r guardedRoute) request(id string) *http.Request {
+	if r.param != "" {
+		return httptest.NewRequest(r.method, strings.Replace(r.path, ":"+r.param, id, 1), nil)
+	}
+
+	var value interface{} = id
+	for i := len(r.body) - 1; i >= 0; i-- {
+		var obj = map[string]interface{}{r.body[i]: value}
+		if i == 0 {
+			obj["note"] = "kept for the handler"
+		}
+
+		value = obj
+	}
+
+	data, _ := json.Marshal(value)
+	return httptest.NewRequest(r.method, r.path, strings.NewReader(string(data)))
+}
+
+type caller struct {
+	userId string
+	role   string
+	mfa    bool
+}
+
+// Serve the request through the guard of the route, the handler echoes the body it receives
+func serveGuarded(t *testing.T, route guardedRoute, as caller, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var engine = gin.New()
+	engine.Handle(route.method, route.path, func(ctx *gin.Context) {
+		ctx.Set("userId", as.userId)
+		ctx.Set("role", as.role)
+		ctx.Set("mfa", as.mfa)
+	}, RequireOwnership(route.owner(route.source()), route.bypass), func(ctx *gin.Context) {
+		var data []byte
+		if ctx.Request.Body != nil {
+			data, _ = io.ReadAll(ctx.Request.Body)
+		}
+
+		ctx.String(http.StatusOK, string(data))
+	})
+
+	var res = httptest.NewRecorder()
+	engine.ServeHTTP(res, req)
+	return res
+}
+
+// Fake permission and owner lookups, staff is granted every permission
+func stubLookups(t *testing.T) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	var prevPermissions, prevOrder, prevPayment = hasPermissions, getOrderOwner, getPaymentOwner
+	var prevMfa = config.Get().Auth.MfaRequiredForAdmin
+
+	hasPermissions = func(ctx *gin.Context, permissions []string) (bool, error) {
+		return ctx.GetString("role") == staff_role, nil
+	}
+
+	var lookup = func(id string, ctx *gin.Context) (string, error) {
+		return testOwners[id], nil
+	}
+
+	getOrderOwner, getPaymentOwner = lookup, lookup
+
+	t.Cleanup(func() {
+		hasPermissions, getOrderOwner, getPaymentOwner = prevPermissions, prevOrder, prevPayment
+		config.Get().Auth.MfaRequiredForAdmin = prevMfa
+	})
+}
+
+func TestRequireOwnership(t *testing.T) {
+	stubLookups(t)
+
+	var tests = []struct {
+		name        string
+		as          caller
+		missing     bool
+		mfaRequired bool
+		status      func(r guardedRoute) int
+	}{
+		{
+			name:   "owner allowed",
+			as:     caller{userId: owner_id, role: customer_role},
+			status: func(r guardedRoute) int { return http.StatusOK },
+		},
+		{
+			name:   "other user rejected",
+			as:     caller{userId: other_id, role: customer_role},
+			status: func(r guardedRoute) int { return http.StatusForbidden },
+		},
+		{
+			name: "bypass permission allowed",
+			as:   caller{userId: other_id, role: staff_role},
+			status: func(r guardedRoute) int {
+				if r.bypass == "" {
+					return http.StatusForbidden
+				}
+
+				return http.StatusOK
+			},
+		},
+		{
+			name:        "bypass with second factor allowed",
+			as:          caller{userId: other_id, role: staff_role, mfa: true},
+			mfaRequired: true,
+			status: func(r guardedRoute) int {
+				if r.bypass == "" {
+					return http.StatusForbidden
+				}
+
+				return http.StatusOK
+			},
+		},
+		{
+			name:        "bypass without second factor rejected",
+			as:          caller{userId: other_id, role: staff_role},
+			mfaRequired: true,
+			status:      func(r guardedRoute) int { return http.StatusForbidden },
+		},
+		{
+			name:        "own resource without second factor allowed",
+			as:          caller{userId: owner_id, role: staff_role},
+			mfaRequired: true,
+			status:      func(r guardedRoute) int { return http.StatusOK },
+		},
+		{
+			name:    "missing resource rejected",
+			as:      caller{userId: owner_id, role: customer_role},
+			missing: true,
+			status:  func(r guardedRoute) int { return http.StatusForbidden },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Get().Auth.MfaRequiredForAdmin = false
+			if tt.mfaRequired {
+				config.Get().Auth.MfaRequiredForAdmin = true
+			}
+
+			for _, route := range guardedRoutes {
+				var id = route.ownedId()
+				if tt.missing {
+					id = "missing"
+				}
+
+				var res = serveGuarded(t, route, tt.as, route.request(id))
+				if want := tt.status(route); res.Code != want {
+					t.Errorf("%s: got status %d, want %d", route.name(), res.Code, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireOwnershipMfaMessage(t *testing.T) {
+	stubLookups(t)
+	config.Get().Auth.MfaRequiredForAdmin = true
+
+	var route = guardedRoutes[0]
+	var res = serveGuarded(t, route, caller{userId: other_id, role: staff_role}, route.request(owner_id))
+
+	if res.Code != http.StatusForbidden || !strings.Contains(res.Body.String(), noti.MFA_REQUIRED_WARN_MSG) {
+		t.Fatalf("got %d %s, want the second factor required", res.Code, res.Body.String())
+	}
+}
+
+func TestRequireOwnershipRejectsBodyWithoutId(t *testing.T) {
+	stubLookups(t)
+
+	for _, route := range guardedRoutes {
+		if route.param != "" {
+			continue
+		}
+
+		for _, body := range []string{"", "not json", `{"note":"no id"}`, `{"user_id":5}`} {
+			var req = httptest.NewRequest(route.method, route.path, strings.NewReader(body))
+			if res := serveGuarded(t, route, caller{userId: owner_id, role: customer_role}, req); res.Code != http.StatusForbidden {
+				t.Errorf("%s with body %q: got status %d, want %d", route.name(), body, res.Code, http.StatusForbidden)
+			}
+		}
+	}
+}
+
+func TestFromBodyRestoresBody(t *testing.T) {
+	stubLookups(t)
+
+	for _, route := range guardedRoutes {
+		if route.param != "" {
+			continue
+		}
+
+		var req = route.request(route.ownedId())
+		data, _ := io.ReadAll(req.Body)
+		req = route.request(route.ownedId())
+
+		var res = serveGuarded(t, route, caller{userId: owner_id, role: customer_role}, req)
+		if res.Code != http.StatusOK || res.Body.String() != string(data) {
+			t.Errorf("%s: handler got %d %q, want the original body %q", route.name(), res.Code, res.Body.String(), data)
+		}
+	}
+}
+
+func TestRequirePermissionWhen(t *testing.T) {
+	stubLookups(t)
+	config.Get().Auth.MfaRequiredForAdmin = false
+
+	var tests = []struct {
+		name   string
+		role   string
+		body   string
+		status int
+	}{
+		{"other status without permission", customer_role, `{"status":"CANCELLED"}`, http.StatusOK},
+		{"matching status without permission", customer_role, `{"status":"REFUNDED"}`, http.StatusForbidden},
+		{"matching status with permission", staff_role, `{"status":"REFUNDED"}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var engine = gin.New()
+			engine.PUT("/orders/update", func(ctx *gin.Context) {
+				ctx.Set("role", tt.role)
+			}, RequirePermissionWhen(FromBody("status"), "REFUNDED", permission.ORDER_REFUND), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			var res = httptest.NewRecorder()
+			engine.ServeHTTP(res, httptest.NewRequest(http.MethodPut, "/orders/update", strings.NewReader(tt.body)))
+			if res.Code != tt.status {
+				t.Fatalf("got status %d, want %d", res.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"sonit_server/constant/noti"
+	"sonit_server/data_access/cache"
+	"sonit_server/model/dto/response"
+	"sonit_server/utils"
+	"sonit_server/utils/config"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit policies, each one reads its limit from config
+const (
+	LOGIN_POLICY    string = "login"
+	REGISTER_POLICY string = "register"
+	CHECKOUT_POLICY string = "checkout"
+)
+
+const rate_limit_key_prefix string = "sonit:ratelimit:"
+
+// Limit requests of a route group in a sliding window, per client IP and per authenticated user.
+// Place it after Authorize to also limit by user
+func RateLimit(policy string) gin.HandlerFunc {
+	var logger = utils.GetLogConfig()
+	var rule = getRateLimit(policy)
+
+	return func(ctx *gin.Context) {
+		if rule.Limit <= 0 {
+			ctx.Next()
+			return
+		}
+
+		var keys = []string{rate_limit_key_prefix + policy + ":ip:" + ctx.ClientIP()}
+		if userId := ctx.GetString("userId"); userId != "" {
+			keys = append(keys, rate_limit_key_prefix+policy+":user:"+userId)
+		}
+
+		var limiter = cache.GetRateLimiter(logger)
+		for _, key := range keys {
+			allowed, retryAfter, err := limiter.Allow(key, rule.Limit, rule.Window)
+			if err != nil { // Limiter down, let request through
+				continue
+			}
+
+			if !allowed {
+				ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				utils.ProcessResponse(response.APIResponse{
+					ErrMsg:  errors.New(noti.TOO_MANY_REQUESTS_WARN_MSG),
+					Context: ctx,
+				})
+
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+func getRateLimit(policy string) config.RateLimit {
+	var cfg = config.Get().RateLimit
+
+	switch policy {
+	case LOGIN_POLICY:
+		return cfg.Login
+	case REGISTER_POLICY:
+		return cfg.Register
+	case CHECKOUT_POLICY:
+		return cfg.Checkout
+	}
+
+	return config.RateLimit{}
+}
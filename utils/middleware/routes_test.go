@@ -0,0 +1,145 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	api_route "sonit_server/api_route"
+	"sonit_server/utils/middleware"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ownership_guard  string = "sonit_server/utils/middleware.RequireOwnership.func"
+	permission_guard string = "sonit_server/utils/middleware.RequirePermission.func"
+)
+
+// Path params naming a user or a user's resource
+var ownedParams = []string{":id", ":userId", ":actorId"}
+
+// Routes with an owned param which are open to every caller on purpose
+var unguardedRoutes = map[string]bool{
+	"GET /categories/:id":                true, // Catalog
+	"GET /collections/:id":               true,
+	"GET /products/:id":                  true,
+	"GET /vouchers/:id":                  true,
+	"GET /roles/:id":                     true,
+	"GET /payments/callback-success/:id": true, // Redirects of the payment gateway
+	"GET /payments/callback-cancel/:id":  true,
+}
+
+// Handler names of every registered route, by method and path.
+// A middleware added before the routes records the chain and stops the request
+func getRouteHandlers(t *testing.T) map[string][]string {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	var res = map[string][]string{}
+	var engine = gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		res[ctx.Request.Method+" "+ctx.FullPath()] = ctx.HandlerNames()
+		ctx.AbortWithStatus(http.StatusNoContent)
+	})
+
+	for _, initialize := range []func(*gin.Engine, string){
+		api_route.InitializeRoleHandlerRoute,
+		api_route.InitializeUserHandlerRoute,
+		api_route.InitializeMfaHandlerRoute,
+		api_route.InitializeCollectionHandlerRoute,
+		api_route.InitializeCategoryHandlerRoute,
+		api_route.InitializeVoucherHandlerRoute,
+		api_route.InitializeVipTierHandlerRoute,
+		api_route.InitializeProductHandlerRoute,
+		api_route.InitializeProductInventoryTransactionHandlerRoute,
+		api_route.InitializeInventoryHandlerRoute,
+		api_route.InitializeWarehouseHandlerRoute,
+		api_route.InitializeCartHandlerRoute,
+		api_route.InitializeWishlistHandlerRoute,
+		api_route.InitializeStockSubscriptionHandlerRoute,
+		api_route.InitializePaymentHandlerRoute,
+		api_route.InitializeOrderHandlerRoute,
+		api_route.InitializeCacheHandlerRoute,
+	} {
+		initialize(engine, "")
+	}
+
+	for _, route := range engine.Routes() {
+		var segments = strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+				segments[i] = "value"
+			}
+		}
+
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(route.Method, strings.Join(segments, "/"), nil))
+		if _, ok := res[route.Method+" "+route.Path]; !ok {
+			t.Fatalf("%s %s: request didn't reach the route", route.Method, route.Path)
+		}
+	}
+
+	return res
+}
+
+func hasHandler(names []string, prefix string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasOwnedParam(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		for _, param := range ownedParams {
+			if segment == param {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func TestRoutesAreGuarded(t *testing.T) {
+	var routes = getRouteHandlers(t)
+
+	for name, handlers := range routes {
+		var path = name[strings.Index(name, " ")+1:]
+		if !hasOwnedParam(path) || unguardedRoutes[name] {
+			continue
+		}
+
+		if !hasHandler(handlers, ownership_guard) && !hasHandler(handlers, permission_guard) {
+			t.Errorf("%s: neither ownership nor permission is required", name)
+		}
+	}
+
+	for name := range unguardedRoutes {
+		if _, ok := routes[name]; !ok {
+			t.Errorf("%s: allowed unguarded but not registered", name)
+		}
+	}
+}
+
+func TestGuardedRoutesMatchRegisteredRoutes(t *testing.T) {
+	var routes = getRouteHandlers(t)
+
+	var tested = map[string]bool{}
+	for _, name := range middleware.GuardedRouteNames() {
+		tested[name] = true
+		if !hasHandler(routes[name], ownership_guard) {
+			t.Errorf("%s: tested as guarded but not registered with RequireOwnership", name)
+		}
+	}
+
+	for name, handlers := range routes {
+		if hasHandler(handlers, ownership_guard) && !tested[name] {
+			t.Errorf("%s: registered with RequireOwnership but missing in the ownership tests", name)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	action_type "sonit_server/constant/action_type"
+	"sonit_server/constant/noti"
+	"sonit_server/utils/config"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	jwks_cache_duration    time.Duration = time.Hour
+	jwks_refresh_cooldown  time.Duration = time.Minute // Min time between fetches caused by unknown kids
+	jwks_request_timeout   time.Duration = 10 * time.Second
+	oidc_clock_skew_leeway time.Duration = time.Minute
+)
+
+// OpenID Connect provider verifying ID tokens of social login
+type OidcProvider struct {
+	Name     string
+	ClientId string
+	Issuers  []string
+	JwksUrl  string
+}
+
+// Claims of an ID token, email_verified is a string in some providers
+type OidcClaims struct {
+	Email         string      `json:"email"`
+	EmailVerified oidcBoolean `json:"email_verified"`
+	Name          string      `json:"name"`
+	Nonce         string      `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+type oidcBoolean bool
+
+func (b *oidcBoolean) UnmarshalJSON(data []byte) error {
+	*b = oidcBoolean(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+// Get configured provider by name, false if unknown or disabled
+func GetOidcProvider(name string) (*OidcProvider, bool) {
+	var cfg = config.Get().Oidc
+	var res OidcProvider
+
+	switch name {
+	case action_type.GOOGLE_PROVIDER:
+		res = OidcProvider{Name: name, ClientId: cfg.GoogleClientId, Issuers: splitList(cfg.GoogleIssuers), JwksUrl: cfg.GoogleJwksUrl}
+	case action_type.FACEBOOK_PROVIDER:
+		res = OidcProvider{Name: name, ClientId: cfg.FacebookClientId, Issuers: splitList(cfg.FacebookIssuers), JwksUrl: cfg.FacebookJwksUrl}
+	default:
+		return nil, false
+	}
+
+	if res.ClientId == "" || res.JwksUrl == "" || len(res.Issuers) == 0 {
+		return nil, false
+	}
+
+	return &res, true
+}
+
+// Verify ID token of a provider: signature against provider JWKS, iss, aud, exp and nonce if given
+func VerifyIdToken(provider OidcProvider, rawToken, nonce string, logger *log.Logger) (*OidcClaims, error) {
+	var errRes error = errors.New(noti.GENERIC_ERROR_WARN_MSG)
+	var errLogMsg string = "Error at VerifyIdToken of " + provider.Name + " - "
+
+	var claims OidcClaims
+	token, err := jwt.ParseWithClaims(strings.TrimSpace(rawToken), &claims, func(token *jwt.Token) (interface{}, error) {
+		var kid, _ = token.Header["kid"].(string)
+		return getJwksKey(provider.JwksUrl, kid)
+	},
+		jwt.WithValidMethods([]string{rs256_algorithm}),
+		jwt.WithExpirationRequired(),
+		jwt.WithAudience(provider.ClientId),
+		jwt.WithLeeway(oidc_clock_skew_leeway),
+	)
+
+	if err != nil {
+		logger.Println(errLogMsg + err.Error())
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.New(noti.TOKEN_EXPIRED_MESSAGE)
+		}
+
+		return nil, errRes
+	}
+
+	if !token.Valid || claims.Subject == "" {
+		logger.Println(errLogMsg + "invalid claims or token")
+		return nil, errRes
+	}
+
+	var isIssuerValid bool
+	for _, issuer := range provider.Issuers {
+		if claims.Issuer == issuer {
+			isIssuerValid = true
+		}
+	}
+
+	if !isIssuerValid {
+		logger.Println(errLogMsg + "unexpected issuer " + claims.Issuer)
+		return nil, errRes
+	}
+
+	if nonce != "" && claims.Nonce != nonce {
+		logger.Println(errLogMsg + "nonce mismatch")
+		return nil, errRes
+	}
+
+	return &claims, nil
+}
+
+// Public keys of a JWKS URL by kid. Hand-written as the keyfunc module in go.mod (v1) builds keyfuncs
+// of golang-jwt/jwt/v4 while tokens here are parsed with jwt/v5
+type jwksCache struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+var (
+	jwksCaches = make(map[string]*jwksCache)
+	jwksMu     sync.Mutex
+	jwksClient = &http.Client{Timeout: jwks_request_timeout}
+)
+
+// Resolve key from cached JWKS, the set is fetched again when expired or the kid is unknown as provider rotated keys
+func getJwksKey(url, kid string) (*rsa.PublicKey, error) {
+	jwksMu.Lock()
+	defer jwksMu.Unlock()
+
+	var cached = jwksCaches[url]
+	var isExpired = cached == nil || time.Since(cached.fetchedAt) > jwks_cache_duration
+
+	if !isExpired {
+		if key, ok := cached.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	if isExpired || time.Since(cached.fetchedAt) > jwks_refresh_cooldown {
+		keys, err := fetchJwks(url)
+		if err != nil {
+			return nil, err
+		}
+
+		cached = &jwksCache{keys: keys, fetchedAt: time.Now()}
+		jwksCaches[url] = cached
+	}
+
+	key, ok := cached.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	return key, nil
+}
+
+func fetchJwks(url string) (map[string]*rsa.PublicKey, error) {
+	res, err := jwksClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch JWKS %s - status %d", url, res.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	var keys = make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	return keys, nil
+}
+
+func splitList(value string) []string {
+	var res []string
+	for _, x := range strings.Split(value, ",") {
+		if x = strings.TrimSpace(x); x != "" {
+			res = append(res, x)
+		}
+	}
+
+	return res
+}
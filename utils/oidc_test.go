@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sonit_server/constant/noti"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	test_client_id string = "sonit-client"
+	test_issuer    string = "https://accounts.test"
+)
+
+var oidcTestLogger = log.New(io.Discard, "", 0)
+
+// JWKS endpoint serving the public keys of its current signing keys
+type testJwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	fetches int
+}
+
+func newTestJwksServer(t *testing.T, kids ...string) *testJwksServer {
+	t.Helper()
+
+	var srv = &testJwksServer{keys: make(map[string]*rsa.PrivateKey)}
+	for _, kid := range kids {
+		srv.addKey(t, kid)
+	}
+
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+
+		srv.fetches++
+
+		type jwk struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		}
+
+		var set struct {
+			Keys []jwk `json:"keys"`
+		}
+
+		for kid, key := range srv.keys {
+			set.Keys = append(set.Keys, jwk{
+				Kty: "RSA",
+				Kid: kid,
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+
+		json.NewEncoder(w).Encode(set)
+	}))
+
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (s *testJwksServer) addKey(t *testing.T, kid string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	s.keys[kid] = key
+	s.mu.Unlock()
+}
+
+// Provider rotating its keys: kid replaces every previous key
+func (s *testJwksServer) rotate(t *testing.T, kid string) {
+	s.mu.Lock()
+	s.keys = make(map[string]*rsa.PrivateKey)
+	s.mu.Unlock()
+
+	s.addKey(t, kid)
+}
+
+func (s *testJwksServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.fetches
+}
+
+func (s *testJwksServer) provider() OidcProvider {
+	return OidcProvider{Name: "test", ClientId: test_client_id, Issuers: []string{test_issuer}, JwksUrl: s.URL}
+}
+
+func (s *testJwksServer) sign(t *testing.T, kid string, claims OidcClaims) string {
+	t.Helper()
+
+	s.mu.Lock()
+	var key = s.keys[kid]
+	s.mu.Unlock()
+
+	if key == nil {
+		// Key unknown to the provider
+		var err error
+		if key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var token = jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
+
+func validClaims() OidcClaims {
+	return OidcClaims{
+		Email: "user@sonit.vn",
+		Nonce: "n-0S6_WzA2Mj",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "10769150350006150715113082367",
+			Issuer:    test_issuer,
+			Audience:  jwt.ClaimStrings{test_client_id},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestVerifyIdToken(t *testing.T) {
+	var srv = newTestJwksServer(t, "key1")
+
+	var tests = []struct {
+		name   string
+		kid    string
+		modify func(c *OidcClaims)
+		nonce  string
+		errMsg string
+	}{
+		{name: "valid", kid: "key1", nonce: "n-0S6_WzA2Mj"},
+		{name: "nonce not requested", kid: "key1"},
+		{name: "wrong audience", kid: "key1", errMsg: noti.GENERIC_ERROR_WARN_MSG, modify: func(c *OidcClaims) {
+			c.Audience = jwt.ClaimStrings{"other-client"}
+		}},
+		{name: "wrong issuer", kid: "key1", errMsg: noti.GENERIC_ERROR_WARN_MSG, modify: func(c *OidcClaims) {
+			c.Issuer = "https://evil.test"
+		}},
+		{name: "expired", kid: "key1", errMsg: noti.TOKEN_EXPIRED_MESSAGE, modify: func(c *OidcClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-2 * oidc_clock_skew_leeway))
+		}},
+		{name: "expired within leeway", kid: "key1", modify: func(c *OidcClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-oidc_clock_skew_leeway / 2))
+		}},
+		{name: "without expiry", kid: "key1", errMsg: noti.GENERIC_ERROR_WARN_MSG, modify: func(c *OidcClaims) {
+			c.ExpiresAt = nil
+		}},
+		{name: "without subject", kid: "key1", errMsg: noti.GENERIC_ERROR_WARN_MSG, modify: func(c *OidcClaims) {
+			c.Subject = ""
+		}},
+		{name: "nonce mismatch", kid: "key1", nonce: "other-nonce", errMsg: noti.GENERIC_ERROR_WARN_MSG},
+		{name: "unknown key", kid: "key2", errMsg: noti.GENERIC_ERROR_WARN_MSG},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims = validClaims()
+			if tt.modify != nil {
+				tt.modify(&claims)
+			}
+
+			res, err := VerifyIdToken(srv.provider(), srv.sign(t, tt.kid, claims), tt.nonce, oidcTestLogger)
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Fatalf("got error %v, want %q", err, tt.errMsg)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if res.Subject != claims.Subject || res.Email != claims.Email {
+				t.Fatalf("got claims %+v", res)
+			}
+		})
+	}
+}
+
+func TestVerifyIdTokenRejectsOtherAlgorithms(t *testing.T) {
+	var srv = newTestJwksServer(t, "key1")
+
+	var token = jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	token.Header["kid"] = "key1"
+
+	raw, err := token.SignedString([]byte("shared secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifyIdToken(srv.provider(), raw, "", oidcTestLogger); err == nil {
+		t.Fatal("HS256 token accepted")
+	}
+}
+
+func TestVerifyIdTokenKeyRotation(t *testing.T) {
+	var srv = newTestJwksServer(t, "key1")
+	var provider = srv.provider()
+
+	if _, err := VerifyIdToken(provider, srv.sign(t, "key1", validClaims()), "", oidcTestLogger); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Cached keys are reused
+	if _, err := VerifyIdToken(provider, srv.sign(t, "key1", validClaims()), "", oidcTestLogger); err != nil || srv.fetchCount() != 1 {
+		t.Fatalf("got %v after %d fetches, want the cached key", err, srv.fetchCount())
+	}
+
+	srv.rotate(t, "key2")
+	var rotated = srv.sign(t, "key2", validClaims())
+
+	// Unknown kids within the cooldown don't hit the provider again
+	if _, err := VerifyIdToken(provider, rotated, "", oidcTestLogger); err == nil || srv.fetchCount() != 1 {
+		t.Fatalf("got %v after %d fetches, want rejected without fetching", err, srv.fetchCount())
+	}
+
+	jwksMu.Lock()
+	jwksCaches[provider.JwksUrl].fetchedAt = time.Now().Add(-2 * jwks_refresh_cooldown)
+	jwksMu.Unlock()
+
+	if _, err := VerifyIdToken(provider, rotated, "", oidcTestLogger); err != nil || srv.fetchCount() != 2 {
+		t.Fatalf("got %v after %d fetches, want the rotated key fetched", err, srv.fetchCount())
+	}
+
+	// Tokens of the retired key are rejected
+	if _, err := VerifyIdToken(provider, srv.sign(t, "key1", validClaims()), "", oidcTestLogger); err == nil {
+		t.Fatal("token of the retired key accepted")
+	}
+}
+
+func TestVerifyIdTokenRefetchesExpiredKeys(t *testing.T) {
+	var srv = newTestJwksServer(t, "key1")
+	var provider = srv.provider()
+
+	VerifyIdToken(provider, srv.sign(t, "key1", validClaims()), "", oidcTestLogger)
+
+	jwksMu.Lock()
+	jwksCaches[provider.JwksUrl].fetchedAt = time.Now().Add(-2 * jwks_cache_duration)
+	jwksMu.Unlock()
+
+	if _, err := VerifyIdToken(provider, srv.sign(t, "key1", validClaims()), "", oidcTestLogger); err != nil || srv.fetchCount() != 2 {
+		t.Fatalf("got %v after %d fetches, want the expired set fetched again", err, srv.fetchCount())
+	}
+}
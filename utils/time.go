@@ -6,6 +6,7 @@ const (
 	NormalActionDuration time.Duration = time.Minute * 15   // 15'
 	AccessDuration       time.Duration = time.Hour * 24     // 1 ngày
 	RefreshDuration      time.Duration = AccessDuration * 7 // 1 tuần
+	MfaChallengeDuration time.Duration = time.Minute * 5    // 5'
 )
 
 func GetPrimitiveTime() time.Time {
@@ -4,9 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
-	"sonit_server/constant/env"
 	"sonit_server/constant/noti"
+	"sonit_server/utils/config"
 	"strings"
 	"time"
 	"unicode"
@@ -14,47 +13,64 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// Generate tokens for login action
-func GenerateTokens(email, userId, role string, logger *log.Logger) (string, string, error) {
-	var bytes = []byte(os.Getenv(env.SECRET_KEY))
+// Purpose of a token, a token can't be used for another purpose
+const (
+	ACCESS_TOKEN_USE  string = "access"
+	REFRESH_TOKEN_USE string = "refresh"
+	ACTION_TOKEN_USE  string = "action"
+	MFA_TOKEN_USE     string = "mfa_challenge" // Second login step after password is verified
+)
+
+type TokenClaims struct {
+	UserId    string `json:"user_id"`
+	Email     string `json:"email,omitempty"`
+	Role      string `json:"role,omitempty"`
+	TokenUse  string `json:"token_use"`
+	SessionId string `json:"sid,omitempty"` // Session of access and refresh tokens
+	Mfa       bool   `json:"mfa,omitempty"` // Login passed two-factor authentication
+	jwt.RegisteredClaims
+}
+
+// Access and refresh tokens issued for a session
+type SessionTokens struct {
+	AccessToken      string
+	RefreshToken     string
+	AccessTokenId    string // jti of the access token
+	AccessExpiredAt  time.Time
+	RefreshExpiredAt time.Time
+}
+
+// Generate tokens for login and refresh actions
+func GenerateTokens(email, userId, role, sessionId string, isMfa bool, logger *log.Logger) (*SessionTokens, error) {
 	var errMsg string = "Error while generating tokens - "
+	var claims = TokenClaims{Email: email, UserId: userId, Role: role, SessionId: sessionId, Mfa: isMfa}
 
-	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userId,
-		"email":   email,
-		"role":    role,
-		"expire":  time.Now().Add(AccessDuration).Unix(),
-	}).SignedString(bytes)
+	accessToken, accessClaims, err := signToken(claims, ACCESS_TOKEN_USE, AccessDuration)
 	if err != nil {
 		logger.Print(errMsg + fmt.Sprint(err))
-		return "", "", errors.New(noti.INTERNALL_ERR_MSG)
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
 	}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userId,
-		"email":   email,
-		"role":    role,
-		"expire":  time.Now().Add(RefreshDuration).Unix(),
-	}).SignedString(bytes)
+	refreshToken, refreshClaims, err := signToken(claims, REFRESH_TOKEN_USE, RefreshDuration)
 	if err != nil {
 		logger.Print(errMsg + fmt.Sprint(err))
-		return "", "", errors.New(noti.INTERNALL_ERR_MSG)
+		return nil, errors.New(noti.INTERNALL_ERR_MSG)
 	}
 
-	return accessToken, refreshToken, nil
+	return &SessionTokens{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessTokenId:    accessClaims.ID,
+		AccessExpiredAt:  accessClaims.ExpiresAt.Time,
+		RefreshExpiredAt: refreshClaims.ExpiresAt.Time,
+	}, nil
 }
 
 // Generate token for actions such as: register, verify, activate, ...
 func GenerateActionToken(email, userId, role string, logger *log.Logger) (string, error) {
-	var bytes = []byte(os.Getenv(env.SECRET_KEY))
 	var errMsg string = "Error while generating action token - "
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userId,
-		"email":   email,
-		"role":    role,
-		"expire":  time.Now().Add(NormalActionDuration).Unix(),
-	}).SignedString(bytes)
+	token, _, err := signToken(TokenClaims{Email: email, UserId: userId, Role: role}, ACTION_TOKEN_USE, NormalActionDuration)
 	if err != nil {
 		logger.Print(errMsg + fmt.Sprint(err))
 		return "", errors.New(noti.INTERNALL_ERR_MSG)
@@ -63,14 +79,56 @@ func GenerateActionToken(email, userId, role string, logger *log.Logger) (string
 	return token, nil
 }
 
-func ExtractDataFromToken(tokenString string, logger *log.Logger) (string, string, time.Time, error) {
+// Generate token to complete login with a second factor, it doesn't grant access by itself
+func GenerateMfaChallengeToken(email, userId, role string, logger *log.Logger) (string, error) {
+	var errMsg string = "Error while generating mfa challenge token - "
+
+	token, _, err := signToken(TokenClaims{Email: email, UserId: userId, Role: role}, MFA_TOKEN_USE, MfaChallengeDuration)
+	if err != nil {
+		logger.Print(errMsg + fmt.Sprint(err))
+		return "", errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	return token, nil
+}
+
+// Sign token with the active key, the key id is set in kid header
+func signToken(claims TokenClaims, use string, duration time.Duration) (string, *TokenClaims, error) {
+	ring, err := getKeyRing()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var cfg = config.Get().Auth
+	var now = time.Now()
+
+	claims.TokenUse = use
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        GenerateId(),
+		Subject:   claims.UserId,
+		Issuer:    cfg.JwtIssuer,
+		Audience:  jwt.ClaimStrings{cfg.JwtAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+	}
+
+	var token = jwt.NewWithClaims(ring.active.method, &claims)
+	token.Header["kid"] = ring.active.kid
+
+	res, err := token.SignedString(ring.active.private)
+	return res, &claims, err
+}
+
+// Parse and validate token: signature, exp, iat, nbf, iss and aud
+func ParseToken(tokenString string, logger *log.Logger) (*TokenClaims, error) {
 	var errRes error = errors.New(noti.GENERIC_ERROR_WARN_MSG)
-	var errLogMsg string = "Error at ExtractDataFromToken - "
+	var errLogMsg string = "Error at ParseToken - "
 
 	// Check for empty token
 	if tokenString == "" {
 		logger.Println(errLogMsg + "empty token")
-		return "", "", time.Time{}, errRes
+		return nil, errRes
 	}
 
 	// Remove "Bearer " prefix if present
@@ -85,61 +143,49 @@ func ExtractDataFromToken(tokenString string, logger *log.Logger) (string, strin
 	for i, c := range tokenString {
 		if !unicode.IsPrint(c) || c == ' ' {
 			logger.Printf(errLogMsg+"invalid character at position %d: %q\n", i, c)
-			return "", "", time.Time{}, errRes
+			return nil, errRes
 		}
 	}
 
-	// Parse the token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		secretKey := os.Getenv(env.SECRET_KEY)
-		if secretKey == "" {
-			logger.Println(errLogMsg + "secret key not found in environment")
-			return nil, errors.New("missing secret key")
-		}
+	var cfg = config.Get().Auth
+	var claims TokenClaims
 
-		return []byte(secretKey), nil
-	})
+	// Parse the token
+	token, err := jwt.ParseWithClaims(tokenString, &claims, lookupVerifyKey,
+		jwt.WithValidMethods([]string{hs256_algorithm, rs256_algorithm, eddsa_algorithm}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithIssuer(cfg.JwtIssuer),
+		jwt.WithAudience(cfg.JwtAudience),
+	)
 
 	if err != nil {
 		logger.Println(errLogMsg + err.Error())
-		return "", "", time.Time{}, errRes
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.New(noti.TOKEN_EXPIRED_MESSAGE)
+		}
+
+		return nil, errRes
 	}
 
-	// Extract claims
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
+	if !token.Valid || claims.ID == "" {
 		logger.Println(errLogMsg + "invalid claims or token")
-		return "", "", time.Time{}, errRes
+		return nil, errRes
 	}
 
-	// Extract user_id
-	userId, ok := claims["user_id"].(string)
-	if !ok || userId == "" {
+	if claims.UserId == "" {
 		logger.Println(errLogMsg + "missing or invalid user_id claim")
-		return "", "", time.Time{}, errRes
+		return nil, errRes
 	}
 
-	// Extract role
-	role, ok := claims["role"].(string)
-	if !ok || role == "" {
-		logger.Println(errLogMsg + "missing or invalid role claim")
-		return "", "", time.Time{}, errRes
-	}
+	return &claims, nil
+}
 
-	// Extract expiration
-	expFloat, ok := claims["expire"].(float64)
-	if !ok {
-		logger.Println(errLogMsg + "missing or invalid expiration claim")
-		return "", "", time.Time{}, errRes
+func ExtractDataFromToken(tokenString string, logger *log.Logger) (string, string, time.Time, error) {
+	claims, err := ParseToken(tokenString, logger)
+	if err != nil {
+		return "", "", time.Time{}, err
 	}
 
-	// Convert Unix timestamp to time.Time
-	exp := time.Unix(int64(expFloat), 0)
-
-	return userId, role, exp, nil
+	return claims.UserId, claims.Role, claims.ExpiresAt.Time, nil
 }
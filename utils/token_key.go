@@ -0,0 +1,288 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sonit_server/model/dto/response"
+	"sonit_server/utils/config"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	hs256_algorithm string = "HS256"
+	rs256_algorithm string = "RS256"
+	eddsa_algorithm string = "EdDSA"
+
+	primary_hmac_kid string = "primary" // Kid of SECRET_KEY
+	rsa_key_bits     int    = 2048
+)
+
+// Key used to sign or verify tokens, public is nil for HMAC keys
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private interface{}
+	public  crypto.PublicKey
+}
+
+// Every known key by kid, the active one signs new tokens while the rest still verify tokens issued before rotation
+type keyRing struct {
+	keys   map[string]*signingKey
+	active *signingKey
+}
+
+var (
+	ring    *keyRing
+	ringErr error
+	ringMu  sync.Mutex
+)
+
+// Load signing keys from config, fails if the active key of the configured algorithm is not available
+func InitializeTokenKeys(logger *log.Logger) error {
+	ringMu.Lock()
+	defer ringMu.Unlock()
+
+	ring, ringErr = loadKeyRing()
+	if ringErr != nil {
+		logger.Println("Error while loading token signing keys - " + ringErr.Error())
+	}
+
+	return ringErr
+}
+
+func getKeyRing() (*keyRing, error) {
+	ringMu.Lock()
+	defer ringMu.Unlock()
+
+	if ring == nil && ringErr == nil {
+		ring, ringErr = loadKeyRing()
+	}
+
+	return ring, ringErr
+}
+
+func loadKeyRing() (*keyRing, error) {
+	var cfg = config.Get().Auth
+	var res = &keyRing{keys: make(map[string]*signingKey)}
+
+	// HMAC keys
+	if cfg.SecretKey != "" {
+		res.keys[primary_hmac_kid] = &signingKey{kid: primary_hmac_kid, method: jwt.SigningMethodHS256, private: []byte(cfg.SecretKey)}
+	}
+
+	for _, pair := range strings.Split(cfg.JwtHmacKeys, ",") {
+		kid, secret, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || kid == "" || secret == "" {
+			continue
+		}
+
+		res.keys[kid] = &signingKey{kid: kid, method: jwt.SigningMethodHS256, private: []byte(secret)}
+	}
+
+	// Asymmetric keys
+	asymmetricKids, err := loadPemKeys(cfg.JwtKeyDir, res.keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Active key
+	var kid = cfg.JwtActiveKid
+	if kid == "" {
+		switch cfg.JwtAlgorithm {
+		case hs256_algorithm:
+			kid = primary_hmac_kid
+		default:
+			// Kids are generated from creation time so the last one is the newest
+			for _, k := range asymmetricKids {
+				if res.keys[k].method.Alg() == cfg.JwtAlgorithm {
+					kid = k
+				}
+			}
+		}
+	}
+
+	active, ok := res.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no %s signing key found, expected kid %q", cfg.JwtAlgorithm, kid)
+	}
+
+	if active.method.Alg() != cfg.JwtAlgorithm {
+		return nil, fmt.Errorf("signing key %s is %s but JWT_ALGORITHM is %s", kid, active.method.Alg(), cfg.JwtAlgorithm)
+	}
+
+	res.active = active
+	return res, nil
+}
+
+// Load <kid>.pem private keys of a directory, returns sorted kids
+func loadPemKeys(dir string, keys map[string]*signingKey) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	var kids []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("invalid PEM file " + file)
+		}
+
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			if privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+				return nil, fmt.Errorf("invalid private key %s - %s", file, err.Error())
+			}
+		}
+
+		var kid = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		switch k := privateKey.(type) {
+		case *rsa.PrivateKey:
+			keys[kid] = &signingKey{kid: kid, method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}
+		case ed25519.PrivateKey:
+			keys[kid] = &signingKey{kid: kid, method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}
+		default:
+			return nil, errors.New("unsupported private key type in " + file)
+		}
+
+		kids = append(kids, kid)
+	}
+
+	sort.Strings(kids)
+	return kids, nil
+}
+
+// Resolve verification key of a token by its kid header
+func lookupVerifyKey(token *jwt.Token) (interface{}, error) {
+	ring, err := getKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	var kid, _ = token.Header["kid"].(string)
+	if kid == "" {
+		kid = primary_hmac_kid
+	}
+
+	key, ok := ring.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	if key.public != nil {
+		return key.public, nil
+	}
+
+	return key.private, nil
+}
+
+// Public keys as JSON Web Key Set so other services can verify tokens, HMAC keys are never published
+func GetJWKS() (response.JWKSResponse, error) {
+	var res = response.JWKSResponse{Keys: []response.JWK{}}
+
+	ring, err := getKeyRing()
+	if err != nil {
+		return res, err
+	}
+
+	var kids []string
+	for kid := range ring.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	var encode = base64.RawURLEncoding.EncodeToString
+	for _, kid := range kids {
+		switch pub := ring.keys[kid].public.(type) {
+		case *rsa.PublicKey:
+			res.Keys = append(res.Keys, response.JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: rs256_algorithm,
+				N:   encode(pub.N.Bytes()),
+				E:   encode(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			res.Keys = append(res.Keys, response.JWK{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: eddsa_algorithm,
+				Crv: "Ed25519",
+				X:   encode(pub),
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// Generate a new private key file for rotation, returns its kid
+func GenerateTokenKeyFile(dir, algorithm string) (string, error) {
+	var privateKey interface{}
+	switch algorithm {
+	case rs256_algorithm:
+		key, err := rsa.GenerateKey(rand.Reader, rsa_key_bits)
+		if err != nil {
+			return "", err
+		}
+
+		privateKey = key
+	case eddsa_algorithm:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", err
+		}
+
+		privateKey = key
+	default:
+		return "", errors.New("key generation supports " + rs256_algorithm + " and " + eddsa_algorithm + " only")
+	}
+
+	data, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	var kid = strings.ToLower(algorithm) + "-" + time.Now().UTC().Format("20060102150405")
+	var file = filepath.Join(dir, kid+".pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data}), 0600); err != nil {
+		return "", err
+	}
+
+	return kid, nil
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters of RFC 6238, supported by common authenticator apps
+const (
+	totp_secret_bytes int           = 20
+	totp_digits       int           = 6
+	totp_period       time.Duration = 30 * time.Second
+	totp_skew_steps   int64         = 1 // Accepted steps before and after current one for clock drift
+
+	recovery_code_count  int    = 10
+	recovery_code_length int    = 10
+	recovery_code_chars  string = "abcdefghjkmnpqrstuvwxyz23456789" // No look-alike characters
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate a base32 TOTP secret
+func GenerateTotpSecret() (string, error) {
+	var secret = make([]byte, totp_secret_bytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// Build otpauth URI for authenticator apps, it is the payload of the enrollment QR code
+func BuildTotpUri(issuer, account, secret string) string {
+	var query = url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totp_digits))
+	query.Set("period", fmt.Sprint(int(totp_period.Seconds())))
+
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// Verify a TOTP code at the given time, returns the matched time step so a code can't be replayed
+func VerifyTotpCode(secret, code string, at time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totp_digits {
+		return 0, false
+	}
+
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	var step = at.Unix() / int64(totp_period.Seconds())
+	for i := -totp_skew_steps; i <= totp_skew_steps; i++ {
+		if subtle.ConstantTimeCompare([]byte(generateTotpCode(key, step+i)), []byte(code)) == 1 {
+			return step + i, true
+		}
+	}
+
+	return 0, false
+}
+
+// HOTP value of RFC 4226 for a counter
+func generateTotpCode(key []byte, counter int64) string {
+	var msg = make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	var mac = hmac.New(sha1.New, key)
+	mac.Write(msg)
+	var sum = mac.Sum(nil)
+
+	var offset = sum[len(sum)-1] & 0x0f
+	var value = binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	var mod uint32 = 1
+	for i := 0; i < totp_digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totp_digits, value%mod)
+}
+
+// Generate one-time recovery codes formatted as xxxxx-xxxxx, only their hashes should be stored
+func GenerateRecoveryCodes() ([]string, error) {
+	var res []string
+	for i := 0; i < recovery_code_count; i++ {
+		var buf = make([]byte, recovery_code_length)
+		for j := range buf {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(recovery_code_chars))))
+			if err != nil {
+				return nil, err
+			}
+
+			buf[j] = recovery_code_chars[n.Int64()]
+		}
+
+		res = append(res, string(buf[:recovery_code_length/2])+"-"+string(buf[recovery_code_length/2:]))
+	}
+
+	return res, nil
+}
+
+// Normalize recovery code typed by user before hashing
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Base32 of the RFC 6238 SHA-1 test key "12345678901234567890"
+const rfc_test_secret string = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestVerifyTotpCodeRfcVectors(t *testing.T) {
+	// Last 6 digits of the 8 digit values of RFC 6238 appendix B
+	var tests = []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		step, ok := VerifyTotpCode(rfc_test_secret, tt.code, time.Unix(tt.unix, 0))
+		if !ok {
+			t.Errorf("code %s rejected at %d", tt.code, tt.unix)
+			continue
+		}
+
+		if step != tt.unix/30 {
+			t.Errorf("code %s matched step %d, want %d", tt.code, step, tt.unix/30)
+		}
+	}
+}
+
+func TestVerifyTotpCodeSkew(t *testing.T) {
+	var at = time.Unix(1111111111, 0)
+	var code = generateTotpCode(mustDecodeSecret(t, rfc_test_secret), at.Unix()/30)
+
+	var tests = []struct {
+		name   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"same step", 0, true},
+		{"previous step", -30 * time.Second, true},
+		{"next step", 30 * time.Second, true},
+		{"two steps before", -60 * time.Second, false},
+		{"two steps after", 60 * time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := VerifyTotpCode(rfc_test_secret, code, at.Add(tt.offset)); ok != tt.ok {
+				t.Fatalf("got %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestVerifyTotpCodeRejectsMalformedInput(t *testing.T) {
+	var at = time.Unix(59, 0)
+	var tests = []struct {
+		name, secret, code string
+	}{
+		{"short code", rfc_test_secret, "28708"},
+		{"long code", rfc_test_secret, "2870820"},
+		{"wrong code", rfc_test_secret, "287083"},
+		{"invalid secret", "not base32!", "287082"},
+		{"empty code", rfc_test_secret, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := VerifyTotpCode(tt.secret, tt.code, at); ok {
+				t.Fatal("code accepted")
+			}
+		})
+	}
+
+	// Surrounding spaces and a lower case secret are accepted
+	if _, ok := VerifyTotpCode(strings.ToLower(rfc_test_secret), " 287082 ", at); !ok {
+		t.Fatal("code with spaces rejected")
+	}
+}
+
+func TestGenerateTotpSecret(t *testing.T) {
+	secret, err := GenerateTotpSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key := mustDecodeSecret(t, secret); len(key) != totp_secret_bytes {
+		t.Fatalf("got %d bytes secret, want %d", len(key), totp_secret_bytes)
+	}
+
+	// A fresh secret verifies its own current code
+	var now = time.Now()
+	if _, ok := VerifyTotpCode(secret, generateTotpCode(mustDecodeSecret(t, secret), now.Unix()/30), now); !ok {
+		t.Fatal("current code of a generated secret rejected")
+	}
+}
+
+func TestBuildTotpUri(t *testing.T) {
+	var uri = BuildTotpUri("Sonit", "user@sonit.vn", rfc_test_secret)
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" || parsed.Path != "/Sonit:user@sonit.vn" {
+		t.Fatalf("unexpected uri %s", uri)
+	}
+
+	var query = parsed.Query()
+	if query.Get("secret") != rfc_test_secret || query.Get("issuer") != "Sonit" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Fatalf("unexpected parameters %v", query)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(codes) != recovery_code_count {
+		t.Fatalf("got %d codes, want %d", len(codes), recovery_code_count)
+	}
+
+	var seen = make(map[string]bool)
+	for _, code := range codes {
+		var parts = strings.Split(code, "-")
+		if len(parts) != 2 || len(parts[0])+len(parts[1]) != recovery_code_length {
+			t.Fatalf("malformed code %s", code)
+		}
+
+		if strings.Trim(parts[0]+parts[1], recovery_code_chars) != "" {
+			t.Fatalf("code %s has look-alike characters", code)
+		}
+
+		if seen[code] {
+			t.Fatalf("duplicated code %s", code)
+		}
+
+		seen[code] = true
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	if res := NormalizeRecoveryCode(" ABCDE-fghjk "); res != "abcdefghjk" {
+		t.Fatalf("got %s", res)
+	}
+}
+
+func mustDecodeSecret(t *testing.T, secret string) []byte {
+	t.Helper()
+
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
@@ -1,7 +1,22 @@
 package utils
 
-import "github.com/google/uuid"
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/google/uuid"
+)
 
 func GenerateId() string {
 	return uuid.NewString()
 }
+
+// Random token of 32 bytes in base64url, used as an unguessable identifier such as guest cart token
+func GenerateOpaqueToken() (string, error) {
+	var bytes = make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
@@ -1,22 +1,152 @@
 package utils
 
-import "regexp"
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sonit_server/constant/noti"
+	"sonit_server/utils/config"
+	"strings"
+	"unicode"
+)
 
-// Check password if secured
+// Character classes of password policy
+const (
+	upper_password_class   string = "upper"
+	lower_password_class   string = "lower"
+	digit_password_class   string = "digit"
+	special_password_class string = "special"
+)
+
+var passwordClassDescriptions = map[string]string{
+	upper_password_class:   "uppercase letters",
+	lower_password_class:   "lowercase letters",
+	digit_password_class:   "numbers",
+	special_password_class: "special characters",
+}
+
+// Password policy resolved from config
+type passwordPolicy struct {
+	minLength int
+	classes   []string
+}
+
+func getPasswordPolicy() passwordPolicy {
+	var cfg = config.Get().Password
+
+	var classes []string
+	for _, class := range strings.Split(cfg.RequiredClasses, ",") {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if _, ok := passwordClassDescriptions[class]; ok {
+			classes = append(classes, class)
+		}
+	}
+
+	return passwordPolicy{minLength: cfg.MinLength, classes: classes}
+}
+
+func (p passwordPolicy) isSatisfied(password string) bool {
+	if len([]rune(password)) < p.minLength {
+		return false
+	}
+
+	for _, class := range p.classes {
+		if strings.IndexFunc(password, passwordClassMatcher(class)) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Policy in words, e.g. " and include uppercase letters, numbers"
+func (p passwordPolicy) describe() string {
+	if len(p.classes) == 0 {
+		return ""
+	}
+
+	var words []string
+	for _, class := range p.classes {
+		words = append(words, passwordClassDescriptions[class])
+	}
+
+	return " and include " + strings.Join(words, ", ")
+}
+
+func passwordClassMatcher(class string) func(rune) bool {
+	switch class {
+	case upper_password_class:
+		return unicode.IsUpper
+	case lower_password_class:
+		return unicode.IsLower
+	case digit_password_class:
+		return unicode.IsDigit
+	default:
+		return func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+		}
+	}
+}
+
+// Check password if secured by the configured length and character classes
 func IsPasswordSecure(password string) bool {
-	upperCase := "(?i)[A-Z]"                // At least one uppercase letter
-	lowerCase := "[a-z]"                    // At least one lowercase letter
-	digit := "[0-9]"                        // At least one digit
-	specialChar := `[!@#$%^&*()_+{}|:"<>?]` // At least one special character
-
-	// Compile regular expressions
-	upRgx, _ := regexp.Compile(upperCase)
-	lowRgx, _ := regexp.Compile(lowerCase)
-	digRgx, _ := regexp.Compile(digit)
-	speRgx, _ := regexp.Compile(specialChar)
-
-	return upRgx.MatchString(password) &&
-		lowRgx.MatchString(password) &&
-		digRgx.MatchString(password) &&
-		speRgx.MatchString(password)
+	return getPasswordPolicy().isSatisfied(password)
+}
+
+// Validate a new password with password policy and breach list
+func ValidatePassword(password string, logger *log.Logger) error {
+	var policy = getPasswordPolicy()
+	if !policy.isSatisfied(password) {
+		return errors.New(fmt.Sprintf(noti.PASSWORD_NOT_SECURE_WARN_MSG, policy.minLength, policy.describe()))
+	}
+
+	isBreached, err := IsPasswordBreached(password)
+	if err != nil {
+		logger.Println("Error while checking breached password - " + err.Error())
+		return errors.New(noti.INTERNALL_ERR_MSG)
+	}
+
+	if isBreached {
+		return errors.New(noti.PASSWORD_BREACHED_WARN_MSG)
+	}
+
+	return nil
+}
+
+// Look up password in the local breach list with k-anonymity layout: SHA-1 hashes are split by their first 5 hex chars
+// into <PREFIX>.txt files of SUFFIX:COUNT lines, as served by range APIs of breached password services.
+// Only one small file is read per check and the list never has to be loaded into memory
+func IsPasswordBreached(password string) (bool, error) {
+	var dir = config.Get().Password.BreachListDir
+	if dir == "" {
+		return false, nil
+	}
+
+	var sum = sha1.Sum([]byte(password))
+	var hash = strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	file, err := os.Open(filepath.Join(dir, hash[:5]+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) { // No breached hash with this prefix
+			return false, nil
+		}
+
+		return false, err
+	}
+	defer file.Close()
+
+	var scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		suffix, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(suffix, hash[5:]) {
+			return count != "0", nil // Padding entries have count 0
+		}
+	}
+
+	return false, scanner.Err()
 }
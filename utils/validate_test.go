@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sonit_server/constant/noti"
+	"sonit_server/utils/config"
+	"strings"
+	"testing"
+)
+
+var validateTestLogger = log.New(io.Discard, "", 0)
+
+// Apply password config for the test, restored afterwards
+func setPasswordConfig(t *testing.T, cfg config.PasswordConfig) {
+	t.Helper()
+
+	var prev = config.Get().Password
+	config.Get().Password = cfg
+	t.Cleanup(func() {
+		config.Get().Password = prev
+	})
+}
+
+// Breach list dir holding the given passwords with their counts in k-anonymity range files
+func writeBreachList(t *testing.T, counts map[string]string) string {
+	t.Helper()
+
+	var dir = t.TempDir()
+	for password, count := range counts {
+		var sum = sha1.Sum([]byte(password))
+		var hash = strings.ToUpper(hex.EncodeToString(sum[:]))
+
+		file, err := os.OpenFile(filepath.Join(dir, hash[:5]+".txt"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Other suffixes of the range and a lower case entry as written by some dumps
+		fmt.Fprintf(file, "0000000000000000000000000000000000A:3\r\n%s:%s\n", strings.ToLower(hash[5:]), count)
+		file.Close()
+	}
+
+	return dir
+}
+
+func TestIsPasswordSecure(t *testing.T) {
+	var tests = []struct {
+		name     string
+		cfg      config.PasswordConfig
+		password string
+		want     bool
+	}{
+		{"every class", config.PasswordConfig{MinLength: 8, RequiredClasses: "upper,lower,digit,special"}, "Sonit@dev1", true},
+		{"too short", config.PasswordConfig{MinLength: 8, RequiredClasses: "upper,lower,digit,special"}, "So@dev1", false},
+		{"missing upper", config.PasswordConfig{MinLength: 8, RequiredClasses: "upper,lower,digit,special"}, "sonit@dev1", false},
+		{"missing lower", config.PasswordConfig{MinLength: 8, RequiredClasses: "upper,lower,digit,special"}, "SONIT@DEV1", false},
+		{"missing digit", config.PasswordConfig{MinLength: 8, RequiredClasses: "upper,lower,digit,special"}, "Sonit@dev", false},
+		{"missing special", config.PasswordConfig{MinLength: 8, RequiredClasses: "upper,lower,digit,special"}, "Sonitdev1", false},
+		{"space is not special", config.PasswordConfig{MinLength: 8, RequiredClasses: "special"}, "sonit dev", false},
+		{"length counts characters", config.PasswordConfig{MinLength: 8, RequiredClasses: "lower"}, "mậtkhẩuu", true},
+		{"length only", config.PasswordConfig{MinLength: 12, RequiredClasses: ""}, "correcthorsebattery", true},
+		{"unknown classes ignored", config.PasswordConfig{MinLength: 4, RequiredClasses: " Digit , emoji"}, "abc1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setPasswordConfig(t, tt.cfg)
+
+			if res := IsPasswordSecure(tt.password); res != tt.want {
+				t.Fatalf("got %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPasswordBreached(t *testing.T) {
+	var dir = writeBreachList(t, map[string]string{
+		"password":   "9545824",
+		"P@ssw0rd":   "73000",
+		"Padding@01": "0",
+	})
+
+	var tests = []struct {
+		name     string
+		dir      string
+		password string
+		want     bool
+	}{
+		{"breached", dir, "password", true},
+		{"breached with special characters", dir, "P@ssw0rd", true},
+		{"padding entry", dir, "Padding@01", false},
+		{"not in list", dir, "Sonit@dev-unlisted-42", false},
+		{"check disabled", "", "password", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setPasswordConfig(t, config.PasswordConfig{BreachListDir: tt.dir})
+
+			res, err := IsPasswordBreached(tt.password)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if res != tt.want {
+				t.Fatalf("got %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPasswordBreachedUnreadableList(t *testing.T) {
+	var dir = t.TempDir()
+	var sum = sha1.Sum([]byte("password"))
+	var prefix = strings.ToUpper(hex.EncodeToString(sum[:]))[:5]
+
+	// A directory in place of the range file can't be read
+	if err := os.Mkdir(filepath.Join(dir, prefix+".txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	setPasswordConfig(t, config.PasswordConfig{BreachListDir: dir})
+
+	if _, err := IsPasswordBreached("password"); err == nil {
+		t.Fatal("unreadable breach list not reported")
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	var dir = writeBreachList(t, map[string]string{"P@ssw0rd": "73000"})
+
+	var tests = []struct {
+		name     string
+		dir      string
+		password string
+		errMsg   string
+	}{
+		{"valid", dir, "Sonit@dev123", ""},
+		{"not secure", dir, "sonit", fmt.Sprintf(noti.PASSWORD_NOT_SECURE_WARN_MSG, 8, " and include uppercase letters, lowercase letters, numbers, special characters")},
+		{"breached", dir, "P@ssw0rd", noti.PASSWORD_BREACHED_WARN_MSG},
+		{"unreadable breach list", filepath.Join(dir, "missing", "\x00"), "Sonit@dev123", noti.INTERNALL_ERR_MSG},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setPasswordConfig(t, config.PasswordConfig{MinLength: 8, RequiredClasses: "upper,lower,digit,special", BreachListDir: tt.dir})
+
+			var err = ValidatePassword(tt.password, validateTestLogger)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tt.errMsg {
+				t.Fatalf("got error %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}